@@ -20,11 +20,14 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/feast-dev/feast/go/internal/feast"
+	"github.com/feast-dev/feast/go/internal/feast/auth"
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
 	"github.com/feast-dev/feast/go/internal/feast/model"
 	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
 	"github.com/feast-dev/feast/go/internal/feast/registry"
 	"github.com/feast-dev/feast/go/internal/feast/server"
 	"github.com/feast-dev/feast/go/internal/feast/server/logging"
+	"github.com/feast-dev/feast/go/internal/feast/tracing"
 	"github.com/feast-dev/feast/go/internal/feast/transformation"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
 	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
@@ -67,6 +70,18 @@ type LoggingOptions struct {
 	FlushInterval   time.Duration
 }
 
+// TlsOptions is a public (embedded) copy of server.TlsOptions struct.
+// See server.TlsOptions for properties description
+type TlsOptions struct {
+	CertFile     string
+	KeyFile      string
+	ClientCaFile string
+}
+
+func (o TlsOptions) toServerOptions() *server.TlsOptions {
+	return &server.TlsOptions{CertFile: o.CertFile, KeyFile: o.KeyFile, ClientCaFile: o.ClientCaFile}
+}
+
 func NewOnlineFeatureService(conf *OnlineFeatureServiceConfig, transformationCallback transformation.TransformationCallback) *OnlineFeatureService {
 	repoConfig, err := registry.NewRepoConfigFromJSON(conf.RepoPath, conf.RepoConfig)
 	if err != nil {
@@ -295,6 +310,8 @@ func (s *OnlineFeatureService) constructLoggingService(writeLoggedFeaturesCallba
 		if err != nil {
 			return nil, err
 		}
+		// Loggers are recreated with the new schema when their feature service changes
+		s.fs.OnFeatureServicesChange(loggingService.ResetLoggers)
 	}
 	return loggingService, nil
 }
@@ -302,6 +319,11 @@ func (s *OnlineFeatureService) constructLoggingService(writeLoggedFeaturesCallba
 // StartGrpcServerWithLogging starts gRPC server with enabled feature logging
 // Caller of this function must provide Python callback to flush buffered logs as well as logging configuration (loggingOpts)
 func (s *OnlineFeatureService) StartGrpcServerWithLogging(host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts LoggingOptions) error {
+	return s.StartGrpcServerWithTls(host, port, writeLoggedFeaturesCallback, loggingOpts, TlsOptions{})
+}
+
+// StartGrpcServerWithTls starts gRPC server with enabled feature logging that serves TLS if a certificate is configured (tlsOpts)
+func (s *OnlineFeatureService) StartGrpcServerWithTls(host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts LoggingOptions, tlsOpts TlsOptions) error {
 	//if strings.ToLower(os.Getenv("ENABLE_DATADOG_TRACING")) == "true" {
 	//	tracer.Start(tracer.WithRuntimeMetrics())
 	//	defer tracer.Stop()
@@ -318,10 +340,21 @@ func (s *OnlineFeatureService) StartGrpcServerWithLogging(host string, port int,
 		return err
 	}
 
-	//grpcServer := grpc.NewServer(grpc.UnaryInterceptor(grpctrace.UnaryServerInterceptor()))
-	grpcServer := grpc.NewServer()
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor, metrics.UnaryServerInterceptor, auth.UnaryServerInterceptor(s.fs.Authenticator())),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor),
+	}
+	if serverTlsOpts := tlsOpts.toServerOptions(); serverTlsOpts.Enabled() {
+		tlsOption, err := server.NewGrpcTlsServerOption(serverTlsOpts)
+		if err != nil {
+			return err
+		}
+		serverOpts = append(serverOpts, tlsOption)
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
 
 	serving.RegisterServingServiceServer(grpcServer, ser)
+	serving.RegisterGrpcFeatureServerServer(grpcServer, server.NewGrpcFeatureServer(s.fs, loggingService))
 	healthService := health.NewServer()
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthService)
 
@@ -363,6 +396,11 @@ func (s *OnlineFeatureService) StartHttpServerWithLoggingDefaultOpts(host string
 // StartHttpServerWithLogging starts HTTP server with enabled feature logging
 // Caller of this function must provide Python callback to flush buffered logs as well as logging configuration (loggingOpts)
 func (s *OnlineFeatureService) StartHttpServerWithLogging(host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts LoggingOptions) error {
+	return s.StartHttpServerWithTls(host, port, writeLoggedFeaturesCallback, loggingOpts, TlsOptions{})
+}
+
+// StartHttpServerWithTls starts HTTP server with enabled feature logging that serves TLS if a certificate is configured (tlsOpts)
+func (s *OnlineFeatureService) StartHttpServerWithTls(host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts LoggingOptions, tlsOpts TlsOptions) error {
 	loggingService, err := s.constructLoggingService(writeLoggedFeaturesCallback, loggingOpts)
 	if err != nil {
 		return err
@@ -384,7 +422,7 @@ func (s *OnlineFeatureService) StartHttpServerWithLogging(host string, port int,
 		log.Println("HTTP server terminated")
 	}()
 
-	return ser.Serve(host, port)
+	return ser.ServeTLS(host, port, tlsOpts.toServerOptions())
 }
 
 func (s *OnlineFeatureService) StopHttpServer() {
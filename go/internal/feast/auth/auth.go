@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	NoAuth         = "no_auth"
+	OidcAuth       = "oidc"
+	KubernetesAuth = "kubernetes"
+)
+
+// AuthConfig is the `auth` section of feature_store.yaml.
+type AuthConfig struct {
+	// One of no_auth, oidc or kubernetes
+	Type string
+	// OIDC discovery document of the identity provider, e.g. https://keycloak/realms/feast/.well-known/openid-configuration
+	AuthDiscoveryUrl string
+	// OIDC client whose roles in the resource_access claim are the roles of the user
+	ClientId string
+	// Local JWKS file to validate tokens with instead of the JWKS of the identity provider
+	JwksFile string
+	// Dot-separated path of the claim holding the roles of the user, e.g. realm_access.roles or groups.
+	// Defaults to resource_access.<client id>.roles.
+	RolesClaim string
+	// Kubernetes API server to review tokens with, defaults to the API server of the cluster the server runs in
+	ApiServerUrl string
+	// Namespace whose role bindings give roles to service accounts, defaults to the namespace of the server
+	Namespace string
+	// Kubernetes roles that give access to the feature server, any role if empty
+	Roles []string
+}
+
+// User is an authenticated user of the feature server.
+type User struct {
+	Username string
+	Roles    []string
+}
+
+// HasAnyRole tells whether the user has at least one of the roles.
+func (u *User) HasAnyRole(roles []string) bool {
+	for _, role := range roles {
+		for _, userRole := range u.Roles {
+			if role == userRole {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Authenticator resolves the user of a bearer token.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*User, error)
+}
+
+// NewAuthenticator creates the authenticator for the auth configuration. It returns nil if authentication is disabled.
+func NewAuthenticator(config *AuthConfig) (Authenticator, error) {
+	switch config.Type {
+	case "", NoAuth:
+		return nil, nil
+	case OidcAuth:
+		return NewOidcAuthenticator(config)
+	case KubernetesAuth:
+		return NewKubernetesAuthenticator(config)
+	default:
+		return nil, fmt.Errorf("auth type %s is not supported by the Go feature server", config.Type)
+	}
+}
+
+type userContextKey struct{}
+
+// NewContext returns a context carrying the authenticated user.
+func NewContext(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user of the request, if the request was authenticated.
+func UserFromContext(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(*User)
+	return user, ok
+}
+
+var errMissingToken = errors.New("missing bearer token")
+
+func bearerToken(authorization string) (string, error) {
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errMissingToken
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// HttpMiddleware authenticates the bearer token in the Authorization header and passes the user on in the
+// request context. Requests without a valid token get a 401 response written by onError.
+func HttpMiddleware(authenticator Authenticator, onError func(w http.ResponseWriter, err error, statusCode int), next http.Handler) http.Handler {
+	if authenticator == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := bearerToken(r.Header.Get("Authorization"))
+		if err != nil {
+			onError(w, err, http.StatusUnauthorized)
+			return
+		}
+		user, err := authenticator.Authenticate(r.Context(), token)
+		if err != nil {
+			onError(w, err, http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), user)))
+	})
+}
+
+// The health service is probed without credentials
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// UnaryServerInterceptor authenticates the bearer token in the authorization metadata of unary gRPC requests
+// and passes the user on in the request context.
+func UnaryServerInterceptor(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if authenticator == nil || strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+		var authorization string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				authorization = values[0]
+			}
+		}
+		token, err := bearerToken(authorization)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		user, err := authenticator.Authenticate(ctx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(NewContext(ctx, user), req)
+	}
+}
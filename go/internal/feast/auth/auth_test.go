@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type tokenAuthenticatorForTest map[string]*User
+
+func (a tokenAuthenticatorForTest) Authenticate(ctx context.Context, token string) (*User, error) {
+	if user, ok := a[token]; ok {
+		return user, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+func writeErrorForTest(w http.ResponseWriter, err error, statusCode int) {
+	http.Error(w, err.Error(), statusCode)
+}
+
+func TestHttpMiddleware(t *testing.T) {
+	alice := &User{Username: "alice"}
+	handler := HttpMiddleware(tokenAuthenticatorForTest{"token": alice}, writeErrorForTest, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := UserFromContext(r.Context())
+		require.True(t, ok)
+		assert.Same(t, alice, user)
+	}))
+
+	for authorization, expectedStatus := range map[string]int{
+		"Bearer token":   http.StatusOK,
+		"bearer token":   http.StatusOK,
+		"Bearer invalid": http.StatusUnauthorized,
+		"Basic token":    http.StatusUnauthorized,
+		"":               http.StatusUnauthorized,
+	} {
+		req := httptest.NewRequest("POST", "/get-online-features", nil)
+		req.Header.Set("Authorization", authorization)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, expectedStatus, rr.Code, authorization)
+	}
+}
+
+func TestHttpMiddlewareWithoutAuthenticator(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	rr := httptest.NewRecorder()
+	HttpMiddleware(nil, writeErrorForTest, next).ServeHTTP(rr, httptest.NewRequest("GET", "/projects", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	alice := &User{Username: "alice"}
+	interceptor := UnaryServerInterceptor(tokenAuthenticatorForTest{"token": alice})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		user, _ := UserFromContext(ctx)
+		return user, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/feast.serving.ServingService/GetOnlineFeatures"}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	user, err := interceptor(ctx, nil, info, handler)
+	require.Nil(t, err)
+	assert.Same(t, alice, user)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer invalid"))
+	_, err = interceptor(ctx, nil, info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = interceptor(context.Background(), nil, info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Health checks don't need a token
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	assert.Nil(t, err)
+}
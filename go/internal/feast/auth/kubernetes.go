@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// How long an authenticated token and the roles of its service account are trusted before they are reviewed again
+	tokenReviewCacheTtl = time.Minute
+)
+
+// KubernetesAuthenticator authenticates Kubernetes service account tokens with the TokenReview API, like the Python
+// feature server. The roles of a service account are the roles it is bound to in the namespace of the feature server.
+type KubernetesAuthenticator struct {
+	apiServerUrl string
+	namespace    string
+	// Roles that give access to the feature server, any role if empty
+	roles      []string
+	httpClient *http.Client
+	// Token of the feature server's own service account, which must be allowed to create token reviews and list role bindings
+	token string
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]*cachedUser
+}
+
+type cachedUser struct {
+	user    *User
+	expires time.Time
+}
+
+func NewKubernetesAuthenticator(config *AuthConfig) (*KubernetesAuthenticator, error) {
+	a := &KubernetesAuthenticator{
+		apiServerUrl: strings.TrimSuffix(config.ApiServerUrl, "/"),
+		namespace:    config.Namespace,
+		roles:        config.Roles,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		cache:        make(map[[sha256.Size]byte]*cachedUser),
+	}
+	if a.apiServerUrl == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, errors.New("kubernetes auth requires running in a cluster or setting api_server_url")
+		}
+		a.apiServerUrl = "https://" + net.JoinHostPort(host, port)
+		ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+		if err != nil {
+			return nil, err
+		}
+		certPool := x509.NewCertPool()
+		certPool.AppendCertsFromPEM(ca)
+		a.httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: certPool}}
+	}
+	if token, err := os.ReadFile(filepath.Join(serviceAccountDir, "token")); err == nil {
+		a.token = strings.TrimSpace(string(token))
+	}
+	if a.namespace == "" {
+		namespace, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the namespace of the feature server: %w", err)
+		}
+		a.namespace = strings.TrimSpace(string(namespace))
+	}
+	return a, nil
+}
+
+func (a *KubernetesAuthenticator) Authenticate(ctx context.Context, token string) (*User, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+	a.mu.Lock()
+	if cached, ok := a.cache[key]; ok && now.Before(cached.expires) {
+		a.mu.Unlock()
+		return cached.user, nil
+	}
+	a.mu.Unlock()
+
+	user, err := a.authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for cachedKey, cached := range a.cache {
+		if now.After(cached.expires) {
+			delete(a.cache, cachedKey)
+		}
+	}
+	a.cache[key] = &cachedUser{user: user, expires: now.Add(tokenReviewCacheTtl)}
+	return user, nil
+}
+
+func (a *KubernetesAuthenticator) authenticate(ctx context.Context, token string) (*User, error) {
+	namespace, serviceAccount, err := a.reviewToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	boundRoles, err := a.serviceAccountRoles(ctx, namespace, serviceAccount)
+	if err != nil {
+		return nil, err
+	}
+	roles := boundRoles
+	if len(a.roles) > 0 {
+		roles = make([]string, 0)
+		for _, role := range boundRoles {
+			for _, allowedRole := range a.roles {
+				if role == allowedRole {
+					roles = append(roles, role)
+				}
+			}
+		}
+		if len(roles) == 0 {
+			return nil, fmt.Errorf("service account %s:%s is not bound to any of the roles %v", namespace, serviceAccount, a.roles)
+		}
+	}
+	return &User{Username: namespace + ":" + serviceAccount, Roles: roles}, nil
+}
+
+func (a *KubernetesAuthenticator) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.apiServerUrl+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned status %d", method, path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+type tokenReview struct {
+	ApiVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Spec       tokenReviewSpec   `json:"spec"`
+	Status     tokenReviewStatus `json:"status"`
+}
+
+type tokenReviewSpec struct {
+	Token string `json:"token"`
+}
+
+type tokenReviewStatus struct {
+	Authenticated bool `json:"authenticated"`
+	User          struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Error string `json:"error"`
+}
+
+// reviewToken returns the namespace and name of the service account of the token.
+func (a *KubernetesAuthenticator) reviewToken(ctx context.Context, token string) (string, string, error) {
+	review := tokenReview{ApiVersion: "authentication.k8s.io/v1", Kind: "TokenReview", Spec: tokenReviewSpec{Token: token}}
+	var result tokenReview
+	if err := a.do(ctx, http.MethodPost, "/apis/authentication.k8s.io/v1/tokenreviews", review, &result); err != nil {
+		return "", "", fmt.Errorf("token review failed: %w", err)
+	}
+	if !result.Status.Authenticated {
+		if result.Status.Error != "" {
+			return "", "", fmt.Errorf("invalid token: %s", result.Status.Error)
+		}
+		return "", "", errors.New("invalid token")
+	}
+	// Service account usernames have the format system:serviceaccount:<namespace>:<name>
+	parts := strings.Split(result.Status.User.Username, ":")
+	if len(parts) != 4 || parts[0] != "system" || parts[1] != "serviceaccount" {
+		return "", "", fmt.Errorf("%s is not a service account", result.Status.User.Username)
+	}
+	return parts[2], parts[3], nil
+}
+
+type roleBindingList struct {
+	Items []struct {
+		Subjects []struct {
+			Kind      string `json:"kind"`
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"subjects"`
+		RoleRef struct {
+			Name string `json:"name"`
+		} `json:"roleRef"`
+	} `json:"items"`
+}
+
+// serviceAccountRoles returns the roles that the service account is bound to in the namespace of the feature server.
+func (a *KubernetesAuthenticator) serviceAccountRoles(ctx context.Context, namespace, serviceAccount string) ([]string, error) {
+	var roleBindings roleBindingList
+	path := fmt.Sprintf("/apis/rbac.authorization.k8s.io/v1/namespaces/%s/rolebindings", a.namespace)
+	if err := a.do(ctx, http.MethodGet, path, nil, &roleBindings); err != nil {
+		return nil, fmt.Errorf("failed to list role bindings: %w", err)
+	}
+	roles := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, binding := range roleBindings.Items {
+		for _, subject := range binding.Subjects {
+			if subject.Kind == "ServiceAccount" && subject.Name == serviceAccount && subject.Namespace == namespace && !seen[binding.RoleRef.Name] {
+				seen[binding.RoleRef.Name] = true
+				roles = append(roles, binding.RoleRef.Name)
+			}
+		}
+	}
+	return roles, nil
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKubernetesApiServer serves the token reviews and role bindings that the Kubernetes authenticator uses.
+type fakeKubernetesApiServer struct {
+	*httptest.Server
+	// Usernames of the valid tokens
+	tokens       map[string]string
+	roleBindings []interface{}
+	tokenReviews atomic.Int32
+}
+
+func newFakeKubernetesApiServer(t *testing.T, namespace string) *fakeKubernetesApiServer {
+	fake := &fakeKubernetesApiServer{tokens: make(map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /apis/authentication.k8s.io/v1/tokenreviews", func(w http.ResponseWriter, r *http.Request) {
+		fake.tokenReviews.Add(1)
+		var review tokenReview
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&review))
+		username, ok := fake.tokens[review.Spec.Token]
+		review.Status.Authenticated = ok
+		review.Status.User.Username = username
+		if !ok {
+			review.Status.Error = "token not found"
+		}
+		json.NewEncoder(w).Encode(review)
+	})
+	mux.HandleFunc("GET /apis/rbac.authorization.k8s.io/v1/namespaces/"+namespace+"/rolebindings", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": fake.roleBindings})
+	})
+	fake.Server = httptest.NewServer(mux)
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeKubernetesApiServer) bindRole(role string, namespace string, serviceAccount string) {
+	f.roleBindings = append(f.roleBindings, map[string]interface{}{
+		"metadata": map[string]string{"name": role + "-" + serviceAccount},
+		"subjects": []map[string]string{{"kind": "ServiceAccount", "name": serviceAccount, "namespace": namespace}},
+		"roleRef":  map[string]string{"kind": "Role", "name": role},
+	})
+}
+
+func TestKubernetesAuthenticator(t *testing.T) {
+	apiServer := newFakeKubernetesApiServer(t, "feast")
+	apiServer.tokens["client-token"] = "system:serviceaccount:clients:client"
+	apiServer.tokens["user-token"] = "alice"
+	apiServer.bindRole("feast-reader", "clients", "client")
+	apiServer.bindRole("feast-writer", "clients", "client")
+	apiServer.bindRole("feast-admin", "other", "client")
+
+	authenticator, err := NewAuthenticator(&AuthConfig{Type: KubernetesAuth, ApiServerUrl: apiServer.URL, Namespace: "feast"})
+	require.Nil(t, err)
+
+	user, err := authenticator.Authenticate(context.Background(), "client-token")
+	require.Nil(t, err)
+	assert.Equal(t, &User{Username: "clients:client", Roles: []string{"feast-reader", "feast-writer"}}, user)
+
+	// Reviewed tokens are cached
+	_, err = authenticator.Authenticate(context.Background(), "client-token")
+	require.Nil(t, err)
+	assert.Equal(t, int32(1), apiServer.tokenReviews.Load())
+
+	_, err = authenticator.Authenticate(context.Background(), "invalid-token")
+	assert.ErrorContains(t, err, "token not found")
+	_, err = authenticator.Authenticate(context.Background(), "user-token")
+	assert.ErrorContains(t, err, "not a service account")
+}
+
+func TestKubernetesAuthenticatorChecksConfiguredRoles(t *testing.T) {
+	apiServer := newFakeKubernetesApiServer(t, "feast")
+	apiServer.tokens["reader-token"] = "system:serviceaccount:clients:reader"
+	apiServer.tokens["other-token"] = "system:serviceaccount:clients:other"
+	apiServer.bindRole("feast-reader", "clients", "reader")
+	apiServer.bindRole("unrelated", "clients", "reader")
+	apiServer.bindRole("unrelated", "clients", "other")
+
+	authenticator, err := NewKubernetesAuthenticator(&AuthConfig{
+		Type:         KubernetesAuth,
+		ApiServerUrl: apiServer.URL,
+		Namespace:    "feast",
+		Roles:        []string{"feast-reader", "feast-writer"},
+	})
+	require.Nil(t, err)
+
+	user, err := authenticator.Authenticate(context.Background(), "reader-token")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"feast-reader"}, user.Roles)
+
+	_, err = authenticator.Authenticate(context.Background(), "other-token")
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "not bound to any of the roles"))
+}
+
+func TestKubernetesAuthenticatorOutsideCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err := NewAuthenticator(&AuthConfig{Type: KubernetesAuth, Namespace: "feast"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// Accepts tokens issued slightly in the future or expired slightly in the past, in case of clock skew
+	tokenLeeway = 10 * time.Second
+	// Minimum time between two fetches of the JWKS, which is refetched when a token is signed with an unknown key
+	jwksRefreshInterval = time.Minute
+)
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// OidcAuthenticator validates JWT access tokens issued by an OIDC identity provider, like the Python feature server.
+type OidcAuthenticator struct {
+	usernameClaim string
+	rolesClaim    []string
+	fetchJwks     func(ctx context.Context) ([]byte, error)
+	httpClient    *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastFetched time.Time
+}
+
+func NewOidcAuthenticator(config *AuthConfig) (*OidcAuthenticator, error) {
+	a := &OidcAuthenticator{
+		usernameClaim: "preferred_username",
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+	}
+	if config.RolesClaim != "" {
+		a.rolesClaim = strings.Split(config.RolesClaim, ".")
+	} else {
+		a.rolesClaim = []string{"resource_access", config.ClientId, "roles"}
+	}
+	if config.JwksFile != "" {
+		a.fetchJwks = func(context.Context) ([]byte, error) {
+			return os.ReadFile(config.JwksFile)
+		}
+	} else if config.AuthDiscoveryUrl != "" {
+		a.fetchJwks = func(ctx context.Context) ([]byte, error) {
+			return a.fetchJwksFromDiscoveryUrl(ctx, config.AuthDiscoveryUrl)
+		}
+	} else {
+		return nil, errors.New("oidc auth requires auth_discovery_url or jwks_file")
+	}
+	if err := a.refreshKeys(context.Background()); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *OidcAuthenticator) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (a *OidcAuthenticator) fetchJwksFromDiscoveryUrl(ctx context.Context, discoveryUrl string) ([]byte, error) {
+	body, err := a.get(ctx, discoveryUrl)
+	if err != nil {
+		return nil, err
+	}
+	var discovery struct {
+		JwksUri string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(body, &discovery); err != nil {
+		return nil, fmt.Errorf("invalid OIDC discovery document: %w", err)
+	}
+	if discovery.JwksUri == "" {
+		return nil, errors.New("OIDC discovery document has no jwks_uri")
+	}
+	return a.get(ctx, discovery.JwksUri)
+}
+
+func (a *OidcAuthenticator) refreshKeys(ctx context.Context) error {
+	data, err := a.fetchJwks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the JWKS: %w", err)
+	}
+	keys, err := parseJwks(data)
+	if err != nil {
+		return err
+	}
+	a.keys = keys
+	a.lastFetched = time.Now()
+	return nil
+}
+
+func (a *OidcAuthenticator) key(ctx context.Context, keyId string) (crypto.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if key, ok := a.lookupKey(keyId); ok {
+		return key, nil
+	}
+	// The identity provider may have rotated its keys
+	if time.Since(a.lastFetched) >= jwksRefreshInterval {
+		if err := a.refreshKeys(ctx); err != nil {
+			return nil, err
+		}
+		if key, ok := a.lookupKey(keyId); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", keyId)
+}
+
+func (a *OidcAuthenticator) lookupKey(keyId string) (crypto.PublicKey, bool) {
+	if keyId == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, true
+		}
+	}
+	key, ok := a.keys[keyId]
+	return key, ok
+}
+
+func (a *OidcAuthenticator) Authenticate(ctx context.Context, token string) (*User, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		keyId, _ := token.Header["kid"].(string)
+		return a.key(ctx, keyId)
+	}, jwt.WithValidMethods(signingMethods), jwt.WithLeeway(tokenLeeway), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	username, ok := claims[a.usernameClaim].(string)
+	if !ok || username == "" {
+		return nil, fmt.Errorf("missing %s field in access token", a.usernameClaim)
+	}
+	return &User{Username: username, Roles: rolesFromClaims(claims, a.rolesClaim)}, nil
+}
+
+// rolesFromClaims returns the roles at the path in the claims. A missing claim means that the user has no roles.
+func rolesFromClaims(claims map[string]interface{}, path []string) []string {
+	var value interface{} = claims
+	for _, key := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	switch value := value.(type) {
+	case []interface{}:
+		roles := make([]string, 0, len(value))
+		for _, role := range value {
+			if role, ok := role.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+		return roles
+	case string:
+		return strings.Fields(value)
+	default:
+		return nil
+	}
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJwks parses the RSA and EC signing keys of a JSON Web Key Set by key ID. Other keys are skipped.
+func parseJwks(data []byte) (map[string]crypto.PublicKey, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %q: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS has no signing keys")
+	}
+	return keys, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+func (jwk *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
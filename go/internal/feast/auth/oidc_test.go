@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSigningKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	return key
+}
+
+func jwksForTest(t *testing.T, keyId string, key *rsa.PrivateKey) []byte {
+	data, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": keyId,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	require.Nil(t, err)
+	return data
+}
+
+func writeJwksFile(t *testing.T, keyId string, key *rsa.PrivateKey) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.Nil(t, os.WriteFile(path, jwksForTest(t, keyId, key), 0600))
+	return path
+}
+
+func signToken(t *testing.T, keyId string, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyId
+	signed, err := token.SignedString(key)
+	require.Nil(t, err)
+	return signed
+}
+
+func TestOidcAuthenticatorWithJwksFile(t *testing.T) {
+	key := newSigningKey(t)
+	authenticator, err := NewAuthenticator(&AuthConfig{Type: OidcAuth, ClientId: "feast", JwksFile: writeJwksFile(t, "key-1", key)})
+	require.Nil(t, err)
+
+	user, err := authenticator.Authenticate(context.Background(), signToken(t, "key-1", key, jwt.MapClaims{
+		"preferred_username": "alice",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"resource_access":    map[string]interface{}{"feast": map[string]interface{}{"roles": []string{"reader", "writer"}}},
+	}))
+	require.Nil(t, err)
+	assert.Equal(t, &User{Username: "alice", Roles: []string{"reader", "writer"}}, user)
+
+	// Roles of other clients are not roles of the user
+	user, err = authenticator.Authenticate(context.Background(), signToken(t, "key-1", key, jwt.MapClaims{
+		"preferred_username": "bob",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"resource_access":    map[string]interface{}{"other": map[string]interface{}{"roles": []string{"reader"}}},
+	}))
+	require.Nil(t, err)
+	assert.Empty(t, user.Roles)
+}
+
+func TestOidcAuthenticatorRejectsInvalidTokens(t *testing.T) {
+	key := newSigningKey(t)
+	authenticator, err := NewOidcAuthenticator(&AuthConfig{Type: OidcAuth, ClientId: "feast", JwksFile: writeJwksFile(t, "key-1", key)})
+	require.Nil(t, err)
+	valid := jwt.MapClaims{"preferred_username": "alice", "exp": time.Now().Add(time.Hour).Unix()}
+
+	for name, token := range map[string]string{
+		"expired":          signToken(t, "key-1", key, jwt.MapClaims{"preferred_username": "alice", "exp": time.Now().Add(-time.Hour).Unix()}),
+		"without expiry":   signToken(t, "key-1", key, jwt.MapClaims{"preferred_username": "alice"}),
+		"without username": signToken(t, "key-1", key, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()}),
+		"unknown key":      signToken(t, "key-2", key, valid),
+		"wrong signature":  signToken(t, "key-1", newSigningKey(t), valid),
+		"malformed":        "not-a-token",
+	} {
+		_, err := authenticator.Authenticate(context.Background(), token)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestOidcAuthenticatorWithDiscoveryUrlAndRolesClaim(t *testing.T) {
+	key := newSigningKey(t)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": server.URL + "/certs"})
+	})
+	mux.HandleFunc("/certs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(jwksForTest(t, "key-1", key))
+	})
+
+	authenticator, err := NewAuthenticator(&AuthConfig{
+		Type:             OidcAuth,
+		AuthDiscoveryUrl: server.URL + "/.well-known/openid-configuration",
+		RolesClaim:       "realm_access.roles",
+	})
+	require.Nil(t, err)
+	user, err := authenticator.Authenticate(context.Background(), signToken(t, "key-1", key, jwt.MapClaims{
+		"preferred_username": "alice",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"realm_access":       map[string]interface{}{"roles": []string{"reader"}},
+	}))
+	require.Nil(t, err)
+	assert.Equal(t, []string{"reader"}, user.Roles)
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	authenticator, err := NewAuthenticator(&AuthConfig{Type: NoAuth})
+	assert.Nil(t, err)
+	assert.Nil(t, authenticator)
+
+	_, err = NewAuthenticator(&AuthConfig{Type: OidcAuth})
+	assert.Error(t, err)
+	_, err = NewAuthenticator(&AuthConfig{Type: "unknown"})
+	assert.Error(t, err)
+}
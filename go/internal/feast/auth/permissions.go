@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+// Resource is a registry object that permissions protect.
+type Resource struct {
+	Type core.PermissionSpec_Type
+	Name string
+	Tags map[string]string
+}
+
+// Stream feature views are feature views too, so permissions for feature views apply to them
+func (r *Resource) matchesType(types []core.PermissionSpec_Type) bool {
+	if slices.Contains(types, r.Type) {
+		return true
+	}
+	return r.Type == core.PermissionSpec_STREAM_FEATURE_VIEW && slices.Contains(types, core.PermissionSpec_FEATURE_VIEW)
+}
+
+func (r *Resource) matchesNamePatterns(namePatterns []string) bool {
+	if len(namePatterns) == 0 {
+		return true
+	}
+	for _, namePattern := range namePatterns {
+		// Patterns must match the whole name, like Python's re.fullmatch
+		if matched, err := regexp.MatchString("^(?:"+namePattern+")$", r.Name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resource) matchesTags(requiredTags map[string]string) bool {
+	for key, value := range requiredTags {
+		if tag, ok := r.Tags[key]; !ok || tag != value {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesPermission(permission *core.Permission, resource *Resource, action core.PermissionSpec_AuthzedAction) bool {
+	spec := permission.GetSpec()
+	return resource.matchesType(spec.GetTypes()) &&
+		resource.matchesNamePatterns(spec.GetNamePatterns()) &&
+		resource.matchesTags(spec.GetRequiredTags()) &&
+		slices.Contains(spec.GetActions(), action)
+}
+
+func grants(permission *core.Permission, user *User) bool {
+	policy := permission.GetSpec().GetPolicy().GetRoleBasedPolicy()
+	return policy != nil && user.HasAnyRole(policy.GetRoles())
+}
+
+// Enforce checks that the permissions allow the user to perform the action on all the resources, with the same
+// rules as the Python feature server: everything is allowed when there are no permissions at all, each resource
+// needs a matching permission, and the first permission that matches the resource and the action decides whether
+// the user is allowed.
+func Enforce(permissions []*core.Permission, user *User, resources []*Resource, action core.PermissionSpec_AuthzedAction) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	for _, resource := range resources {
+		matched := false
+		granted := false
+		for _, permission := range permissions {
+			if matchesPermission(permission, resource, action) {
+				matched = true
+				granted = grants(permission, user)
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("no permissions defined to manage %s on %s %s", action, resource.Type, resource.Name)
+		}
+		if !granted {
+			return fmt.Errorf("user %s is not allowed to %s %s %s", user.Username, action, resource.Type, resource.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+func permissionForTest(types []core.PermissionSpec_Type, namePatterns []string, requiredTags map[string]string, roles ...string) *core.Permission {
+	return &core.Permission{Spec: &core.PermissionSpec{
+		Name:         "permission",
+		Types:        types,
+		NamePatterns: namePatterns,
+		RequiredTags: requiredTags,
+		Actions:      []core.PermissionSpec_AuthzedAction{core.PermissionSpec_READ_ONLINE},
+		Policy:       &core.Policy{PolicyType: &core.Policy_RoleBasedPolicy{RoleBasedPolicy: &core.RoleBasedPolicy{Roles: roles}}},
+	}}
+}
+
+func TestEnforce(t *testing.T) {
+	reader := &User{Username: "reader", Roles: []string{"reader"}}
+	other := &User{Username: "other", Roles: []string{"other"}}
+	driverStats := &Resource{Type: core.PermissionSpec_FEATURE_VIEW, Name: "driver_stats", Tags: map[string]string{"team": "drivers"}}
+	driverStream := &Resource{Type: core.PermissionSpec_STREAM_FEATURE_VIEW, Name: "driver_stream"}
+	driverService := &Resource{Type: core.PermissionSpec_FEATURE_SERVICE, Name: "driver_service"}
+
+	// Without permissions everything is allowed
+	assert.Nil(t, Enforce(nil, other, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+
+	featureViews := []core.PermissionSpec_Type{core.PermissionSpec_FEATURE_VIEW}
+	permissions := []*core.Permission{permissionForTest(featureViews, nil, nil, "reader")}
+	assert.Nil(t, Enforce(permissions, reader, []*Resource{driverStats, driverStream}, core.PermissionSpec_READ_ONLINE))
+	assert.Error(t, Enforce(permissions, other, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+	// Resources without matching permissions are denied
+	assert.Error(t, Enforce(permissions, reader, []*Resource{driverService}, core.PermissionSpec_READ_ONLINE))
+	assert.Error(t, Enforce(permissions, reader, []*Resource{driverStats}, core.PermissionSpec_WRITE_ONLINE))
+
+	// Name patterns must match the whole name
+	permissions = []*core.Permission{permissionForTest(featureViews, []string{"driver_.*"}, nil, "reader")}
+	assert.Nil(t, Enforce(permissions, reader, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+	permissions = []*core.Permission{permissionForTest(featureViews, []string{"driver"}, nil, "reader")}
+	assert.Error(t, Enforce(permissions, reader, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+
+	permissions = []*core.Permission{permissionForTest(featureViews, nil, map[string]string{"team": "riders"}, "reader")}
+	assert.Error(t, Enforce(permissions, reader, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+	permissions = []*core.Permission{permissionForTest(featureViews, nil, map[string]string{"team": "drivers"}, "reader")}
+	assert.Nil(t, Enforce(permissions, reader, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+
+	// The first matching permission decides, even if a later one grants the action
+	permissions = []*core.Permission{
+		permissionForTest(featureViews, nil, nil, "admin"),
+		permissionForTest(featureViews, nil, nil, "other"),
+	}
+	assert.Error(t, Enforce(permissions, other, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+	assert.Error(t, Enforce(permissions, reader, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+
+	// Permissions that don't match the resource or the action are skipped
+	permissions = []*core.Permission{
+		permissionForTest(featureViews, []string{"rider_.*"}, nil, "admin"),
+		permissionForTest(featureViews, nil, nil, "other"),
+	}
+	assert.Nil(t, Enforce(permissions, other, []*Resource{driverStats}, core.PermissionSpec_READ_ONLINE))
+}
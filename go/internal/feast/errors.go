@@ -1,6 +1,8 @@
 package feast
 
 import (
+	"fmt"
+
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -20,3 +22,76 @@ func (FeastTransformationServiceNotConfigured) GRPCStatus() *status.Status {
 func (e FeastTransformationServiceNotConfigured) Error() string {
 	return e.GRPCStatus().Err().Error()
 }
+
+type FeastPushSourceNotFound struct {
+	PushSourceName string
+}
+
+func (e FeastPushSourceNotFound) GRPCStatus() *status.Status {
+	return status.New(codes.NotFound, fmt.Sprintf("No feature view found for push source %s", e.PushSourceName))
+}
+
+func (e FeastPushSourceNotFound) Error() string {
+	return e.GRPCStatus().Err().Error()
+}
+
+type FeastPushModeNotSupported struct {
+	PushMode PushMode
+}
+
+func (e FeastPushModeNotSupported) GRPCStatus() *status.Status {
+	return status.New(codes.Unimplemented, fmt.Sprintf("Push mode %s is not supported, the Go feature server can only push to the online store", e.PushMode))
+}
+
+func (e FeastPushModeNotSupported) Error() string {
+	return e.GRPCStatus().Err().Error()
+}
+
+type FeastProjectNotFound struct {
+	Project string
+}
+
+func (e FeastProjectNotFound) GRPCStatus() *status.Status {
+	return status.New(codes.NotFound, fmt.Sprintf("Project %s not found in the registry", e.Project))
+}
+
+func (e FeastProjectNotFound) Error() string {
+	return e.GRPCStatus().Err().Error()
+}
+
+type FeastPermissionDenied struct {
+	Message string
+}
+
+func (e FeastPermissionDenied) GRPCStatus() *status.Status {
+	return status.New(codes.PermissionDenied, fmt.Sprintf("Permission denied: %s", e.Message))
+}
+
+func (e FeastPermissionDenied) Error() string {
+	return e.GRPCStatus().Err().Error()
+}
+
+type FeastFeatureViewNotFound struct {
+	FeatureViewName string
+}
+
+func (e FeastFeatureViewNotFound) GRPCStatus() *status.Status {
+	return status.New(codes.NotFound, fmt.Sprintf("Feature view %s not found in the registry", e.FeatureViewName))
+}
+
+func (e FeastFeatureViewNotFound) Error() string {
+	return e.GRPCStatus().Err().Error()
+}
+
+type FeastInvalidFeatureData struct {
+	FeatureViewName string
+	Message         string
+}
+
+func (e FeastInvalidFeatureData) GRPCStatus() *status.Status {
+	return status.New(codes.InvalidArgument, fmt.Sprintf("Failed to write to feature view %s: %s", e.FeatureViewName, e.Message))
+}
+
+func (e FeastInvalidFeatureData) Error() string {
+	return e.GRPCStatus().Err().Error()
+}
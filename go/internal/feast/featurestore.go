@@ -3,16 +3,25 @@ package feast
 import (
 	"context"
 	"errors"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/apache/arrow/go/v17/arrow/memory"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
 
 	//"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 
+	"github.com/feast-dev/feast/go/internal/feast/auth"
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
 	"github.com/feast-dev/feast/go/internal/feast/model"
 	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
 	"github.com/feast-dev/feast/go/internal/feast/onlinestore"
 	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/internal/feast/tracing"
 	"github.com/feast-dev/feast/go/internal/feast/transformation"
+	"github.com/feast-dev/feast/go/protos/feast/core"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
 	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
 )
@@ -23,6 +32,24 @@ type FeatureStore struct {
 	onlineStore            onlinestore.OnlineStore
 	transformationCallback transformation.TransformationCallback
 	transformationService  *transformation.GrpcTransformationService
+	// Authenticates the requests of the feature servers, nil if authentication is disabled
+	authenticator auth.Authenticator
+	// Maximum number of entity groups of a request that are read from the online store concurrently
+	onlineReadConcurrency int
+	// Whether the features of on demand feature views written to the online store are transformed for the
+	// entities that they aren't found for
+	onDemandTransformationFallback bool
+
+	// Feature stores of the projects in the registry, which share the registry and transformation service
+	// but have their own online store. Shared by all of them and created on first use.
+	projectStores *projectFeatureStores
+	// Retrieval plans of the feature services and feature references requested from this project
+	retrievalPlans *retrievalPlanCache
+}
+
+type projectFeatureStores struct {
+	mu     sync.Mutex
+	stores map[string]*FeatureStore
 }
 
 // A Features struct specifies a list of features to be retrieved from the online store. These features
@@ -37,6 +64,21 @@ func (fs *FeatureStore) Registry() *registry.Registry {
 	return fs.registry
 }
 
+// OnFeatureServicesChange calls the callback with the names of the project's feature services whose features
+// may have changed whenever a registry refresh changes the registry. It returns a function to unsubscribe.
+func (fs *FeatureStore) OnFeatureServicesChange(callback func(featureServiceNames []string)) func() {
+	return fs.registry.OnChange(func(change *registry.RegistryChange) {
+		if featureServiceNames := fs.registry.AffectedFeatureServices(fs.config.Project, change); len(featureServiceNames) > 0 {
+			callback(featureServiceNames)
+		}
+	})
+}
+
+// Authenticator returns the authenticator of the feature server requests, or nil if authentication is disabled.
+func (fs *FeatureStore) Authenticator() auth.Authenticator {
+	return fs.authenticator
+}
+
 func (fs *FeatureStore) GetRepoConfig() *registry.RepoConfig {
 	return fs.config
 }
@@ -44,10 +86,6 @@ func (fs *FeatureStore) GetRepoConfig() *registry.RepoConfig {
 // NewFeatureStore constructs a feature store fat client using the
 // repo config (contents of feature_store.yaml converted to JSON map).
 func NewFeatureStore(config *registry.RepoConfig, callback transformation.TransformationCallback) (*FeatureStore, error) {
-	onlineStore, err := onlinestore.NewOnlineStore(config)
-	if err != nil {
-		return nil, err
-	}
 	registryConfig, err := config.GetRegistryConfig()
 	if err != nil {
 		return nil, err
@@ -60,120 +98,227 @@ func NewFeatureStore(config *registry.RepoConfig, callback transformation.Transf
 	if err != nil {
 		return nil, err
 	}
-
-	var transformationService *transformation.GrpcTransformationService
-	if transformationServerEndpoint, ok := config.FeatureServer["transformation_service_endpoint"]; ok {
-		// Use a scalable transformation service like Python Transformation Service.
-		// Assume the user will define the "transformation_service_endpoint" in the feature_store.yaml file
-		// under the "feature_server" section.
-		transformationService, _ = transformation.NewGrpcTransformationService(config, transformationServerEndpoint.(string))
-	}
-
-	return &FeatureStore{
-		config:                 config,
-		registry:               registry,
-		onlineStore:            onlineStore,
-		transformationCallback: callback,
-		transformationService:  transformationService,
-	}, nil
-}
-
-// TODO: Review all functions that use ODFV and Request FV since these have not been tested
-// ToDo: Split GetOnlineFeatures interface into two: GetOnlinFeaturesByFeatureService and GetOnlineFeaturesByFeatureRefs
-func (fs *FeatureStore) GetOnlineFeatures(
-	ctx context.Context,
-	featureRefs []string,
-	featureService *model.FeatureService,
-	joinKeyToEntityValues map[string]*prototypes.RepeatedValue,
-	requestData map[string]*prototypes.RepeatedValue,
-	fullFeatureNames bool) ([]*onlineserving.FeatureVector, error) {
-	fvs, odFvs, err := fs.listAllViews()
+	onlineStore, err := newOnlineStore(config, registry)
 	if err != nil {
 		return nil, err
 	}
-
-	entities, err := fs.ListEntities(false)
+	authConfig, err := config.GetAuthConfig()
 	if err != nil {
 		return nil, err
 	}
-
-	var requestedFeatureViews []*onlineserving.FeatureViewAndRefs
-	var requestedOnDemandFeatureViews []*model.OnDemandFeatureView
-	if featureService != nil {
-		requestedFeatureViews, requestedOnDemandFeatureViews, err =
-			onlineserving.GetFeatureViewsToUseByService(featureService, fvs, odFvs)
-	} else {
-		requestedFeatureViews, requestedOnDemandFeatureViews, err =
-			onlineserving.GetFeatureViewsToUseByFeatureRefs(featureRefs, fvs, odFvs)
-	}
+	authenticator, err := auth.NewAuthenticator(authConfig)
 	if err != nil {
 		return nil, err
 	}
-
-	if len(requestedOnDemandFeatureViews) > 0 && fs.transformationService == nil {
-		return nil, FeastTransformationServiceNotConfigured{}
+	onlineReadConcurrency, err := config.GetOnlineReadConcurrency()
+	if err != nil {
+		return nil, err
 	}
-
-	entityNameToJoinKeyMap, expectedJoinKeysSet, err := onlineserving.GetEntityMaps(requestedFeatureViews, entities)
+	onDemandTransformationFallback, err := config.GetOnDemandTransformationFallback()
 	if err != nil {
 		return nil, err
 	}
 
-	err = onlineserving.ValidateFeatureRefs(requestedFeatureViews, fullFeatureNames)
+	// Use a scalable transformation service like Python Transformation Service, defined by the
+	// "transformation_service_endpoint" and "transformation_service" options of the "feature_server" section.
+	var transformationService *transformation.GrpcTransformationService
+	transformationServiceConfig, err := config.GetTransformationServiceConfig()
 	if err != nil {
 		return nil, err
 	}
+	if transformationServiceConfig != nil {
+		transformationConcurrency, err := config.GetTransformationConcurrency()
+		if err != nil {
+			return nil, err
+		}
+		transformationService, err = transformation.NewGrpcTransformationService(config.Project, transformationConcurrency, transformationServiceConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fs := &FeatureStore{
+		config:                         config,
+		registry:                       registry,
+		onlineStore:                    onlineStore,
+		transformationCallback:         callback,
+		transformationService:          transformationService,
+		authenticator:                  authenticator,
+		onlineReadConcurrency:          onlineReadConcurrency,
+		onDemandTransformationFallback: onDemandTransformationFallback,
+		retrievalPlans:                 newRetrievalPlanCache(),
+	}
+	fs.projectStores = &projectFeatureStores{stores: map[string]*FeatureStore{config.Project: fs}}
+	return fs, nil
+}
 
-	numRows, err := onlineserving.ValidateEntityValues(joinKeyToEntityValues, requestData, expectedJoinKeysSet)
+// newOnlineStore creates the online store of the repo config's project, behind a cache if
+// feature_server.online_store_cache is set. Cached values expire with the TTLs of their views in the registry.
+func newOnlineStore(config *registry.RepoConfig, featureRegistry *registry.Registry) (onlinestore.OnlineStore, error) {
+	cacheConfig, err := onlinestore.GetCacheConfig(config)
 	if err != nil {
 		return nil, err
 	}
+	onlineStore, err := onlinestore.NewOnlineStore(config)
+	if err != nil || cacheConfig == nil {
+		return onlineStore, err
+	}
+	featureViewTtl := func(featureViewName string) (time.Duration, bool) {
+		featureView, err := featureRegistry.GetFeatureView(config.Project, featureViewName)
+		if err != nil {
+			featureView, err = featureRegistry.GetStreamFeatureView(config.Project, featureViewName)
+		}
+		if err != nil {
+			return 0, false
+		}
+		return featureView.Ttl.AsDuration(), true
+	}
+	return onlinestore.NewCachedOnlineStore(onlineStore, config, cacheConfig, featureViewTtl), nil
+}
 
-	err = transformation.EnsureRequestedDataExist(requestedOnDemandFeatureViews, requestData)
+// ForProject returns the feature store of another project in the registry. It shares the registry with this
+// feature store and reads from the same online store configuration, scoped to the project. An empty project
+// selects the project of the repo config.
+func (fs *FeatureStore) ForProject(project string) (*FeatureStore, error) {
+	if project == "" || project == fs.config.Project {
+		return fs, nil
+	}
+	if fs.projectStores == nil {
+		return nil, FeastProjectNotFound{Project: project}
+	}
+	fs.projectStores.mu.Lock()
+	defer fs.projectStores.mu.Unlock()
+	if projectStore, ok := fs.projectStores.stores[project]; ok {
+		return projectStore, nil
+	}
+	if !slices.Contains(fs.registry.ListProjects(), project) {
+		return nil, FeastProjectNotFound{Project: project}
+	}
+
+	config := *fs.config
+	config.Project = project
+	onlineStore, err := newOnlineStore(&config, fs.registry)
 	if err != nil {
 		return nil, err
 	}
+	projectStore := &FeatureStore{
+		config:                         &config,
+		registry:                       fs.registry,
+		onlineStore:                    onlineStore,
+		transformationCallback:         fs.transformationCallback,
+		transformationService:          fs.transformationService,
+		authenticator:                  fs.authenticator,
+		onlineReadConcurrency:          fs.onlineReadConcurrency,
+		onDemandTransformationFallback: fs.onDemandTransformationFallback,
+		projectStores:                  fs.projectStores,
+		retrievalPlans:                 newRetrievalPlanCache(),
+	}
+	fs.projectStores.stores[project] = projectStore
+	return projectStore, nil
+}
 
-	result := make([]*onlineserving.FeatureVector, 0)
-	arrowMemory := memory.NewGoAllocator()
-	featureViews := make([]*model.FeatureView, len(requestedFeatureViews))
-	index := 0
-	for _, featuresAndView := range requestedFeatureViews {
-		featureViews[index] = featuresAndView.View
-		index += 1
+// ListProjects returns the projects that features can be retrieved from.
+func (fs *FeatureStore) ListProjects() []string {
+	projects := fs.registry.ListProjects()
+	if !slices.Contains(projects, fs.config.Project) {
+		projects = append(projects, fs.config.Project)
+		slices.Sort(projects)
 	}
+	return projects
+}
 
-	entitylessCase := false
-	for _, featureView := range featureViews {
-		if featureView.HasEntity(model.DUMMY_ENTITY_NAME) {
-			entitylessCase = true
-			break
-		}
+// TODO: Review all functions that use ODFV and Request FV since these have not been tested
+// GetOnlineFeatures retrieves the features of the feature service, or the feature references if no feature
+// service is given, for the entities.
+func (fs *FeatureStore) GetOnlineFeatures(
+	ctx context.Context,
+	featureRefs []string,
+	featureService *model.FeatureService,
+	joinKeyToEntityValues map[string]*prototypes.RepeatedValue,
+	requestData map[string]*prototypes.RepeatedValue,
+	fullFeatureNames bool) ([]*onlineserving.FeatureVector, error) {
+	if featureService != nil {
+		return fs.GetOnlineFeaturesByFeatureService(ctx, featureService, joinKeyToEntityValues, requestData, fullFeatureNames)
 	}
+	return fs.GetOnlineFeaturesByFeatureRefs(ctx, featureRefs, joinKeyToEntityValues, requestData, fullFeatureNames)
+}
 
-	if entitylessCase {
-		dummyEntityColumn := &prototypes.RepeatedValue{Val: make([]*prototypes.Value, numRows)}
-		for index := 0; index < numRows; index++ {
-			dummyEntityColumn.Val[index] = &model.DUMMY_ENTITY_VALUE
-		}
-		joinKeyToEntityValues[model.DUMMY_ENTITY_ID] = dummyEntityColumn
+// GetOnlineFeaturesByFeatureService retrieves the features of a feature service of the registry for the entities.
+func (fs *FeatureStore) GetOnlineFeaturesByFeatureService(
+	ctx context.Context,
+	featureService *model.FeatureService,
+	joinKeyToEntityValues map[string]*prototypes.RepeatedValue,
+	requestData map[string]*prototypes.RepeatedValue,
+	fullFeatureNames bool) ([]*onlineserving.FeatureVector, error) {
+	return fs.observeOnlineFeatures(ctx, featureService.Name, func(ctx context.Context) ([]*onlineserving.FeatureVector, error) {
+		return fs.getOnlineFeatures(ctx, nil, featureService, joinKeyToEntityValues, requestData, fullFeatureNames)
+	})
+}
+
+// GetOnlineFeaturesByFeatureRefs retrieves features by their references, in the format "feature_view:feature",
+// for the entities.
+func (fs *FeatureStore) GetOnlineFeaturesByFeatureRefs(
+	ctx context.Context,
+	featureRefs []string,
+	joinKeyToEntityValues map[string]*prototypes.RepeatedValue,
+	requestData map[string]*prototypes.RepeatedValue,
+	fullFeatureNames bool) ([]*onlineserving.FeatureVector, error) {
+	return fs.observeOnlineFeatures(ctx, "", func(ctx context.Context) ([]*onlineserving.FeatureVector, error) {
+		return fs.getOnlineFeatures(ctx, featureRefs, nil, joinKeyToEntityValues, requestData, fullFeatureNames)
+	})
+}
+
+// observeOnlineFeatures traces and measures the retrieval of online features.
+func (fs *FeatureStore) observeOnlineFeatures(
+	ctx context.Context,
+	featureServiceName string,
+	getOnlineFeatures func(ctx context.Context) ([]*onlineserving.FeatureVector, error)) ([]*onlineserving.FeatureVector, error) {
+	start := time.Now()
+	ctx, span := tracing.StartSpan(ctx, "GetOnlineFeatures",
+		attribute.String("feast.project", fs.config.Project),
+		attribute.String("feast.feature_service", featureServiceName))
+
+	result, err := getOnlineFeatures(ctx)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return nil, err
 	}
+	metrics.OnlineFeaturesDuration.WithLabelValues(fs.config.Project, featureServiceName).Observe(time.Since(start).Seconds())
+	return result, nil
+}
+
+// resolvedFeatures holds the retrieval plan and the grouped feature references that a request reads.
+type resolvedFeatures struct {
+	plan        *retrievalPlan
+	groupedRefs map[string]*onlineserving.GroupedFeaturesPerEntitySet
+	numRows     int
+}
 
-	groupedRefs, err := onlineserving.GroupFeatureRefs(requestedFeatureViews, joinKeyToEntityValues, entityNameToJoinKeyMap, fullFeatureNames)
+func (fs *FeatureStore) getOnlineFeatures(
+	ctx context.Context,
+	featureRefs []string,
+	featureService *model.FeatureService,
+	joinKeyToEntityValues map[string]*prototypes.RepeatedValue,
+	requestData map[string]*prototypes.RepeatedValue,
+	fullFeatureNames bool) ([]*onlineserving.FeatureVector, error) {
+	resolved, err := fs.resolveFeatures(ctx, featureRefs, featureService, joinKeyToEntityValues, requestData, fullFeatureNames)
 	if err != nil {
 		return nil, err
 	}
+	numRows := resolved.numRows
 
-	for _, groupRef := range groupedRefs {
-		featureData, err := fs.readFromOnlineStore(ctx, groupRef.EntityKeys, groupRef.FeatureViewNames, groupRef.FeatureNames)
-		if err != nil {
-			return nil, err
-		}
+	groupRefs, featureData, err := fs.readGroupsFromOnlineStore(ctx, resolved.groupedRefs)
+	if err != nil {
+		return nil, err
+	}
 
+	result := make([]*onlineserving.FeatureVector, 0)
+	arrowMemory := memory.NewGoAllocator()
+	for index, groupRef := range groupRefs {
 		vectors, err := onlineserving.TransposeFeatureRowsIntoColumns(
-			featureData,
+			featureData[index],
 			groupRef,
-			requestedFeatureViews,
+			resolved.plan.featureViews,
 			arrowMemory,
 			numRows,
 		)
@@ -183,10 +328,11 @@ func (fs *FeatureStore) GetOnlineFeatures(
 		result = append(result, vectors...)
 	}
 
-	if fs.transformationCallback != nil || fs.transformationService != nil {
+	onDemandFeatureViews, fallbackFeatureViews := splitWrittenOnDemandFeatureViews(resolved.plan.onDemandFeatureViews, result, fullFeatureNames)
+	if len(onDemandFeatureViews) > 0 {
 		onDemandFeatures, err := transformation.AugmentResponseWithOnDemandTransforms(
 			ctx,
-			requestedOnDemandFeatureViews,
+			onDemandFeatureViews,
 			requestData,
 			joinKeyToEntityValues,
 			result,
@@ -201,8 +347,30 @@ func (fs *FeatureStore) GetOnlineFeatures(
 		}
 		result = append(result, onDemandFeatures...)
 	}
+	if len(fallbackFeatureViews) > 0 {
+		// The features that weren't found in the online store are transformed
+		fallbackFeatures, err := transformation.AugmentResponseWithOnDemandTransforms(
+			ctx,
+			fallbackFeatureViews,
+			requestData,
+			joinKeyToEntityValues,
+			result,
+			fs.transformationCallback,
+			fs.transformationService,
+			arrowMemory,
+			numRows,
+			fullFeatureNames,
+		)
+		if err != nil {
+			return nil, err
+		}
+		err = fillMissingOnDemandFeatures(result, fallbackFeatureViews, fallbackFeatures, arrowMemory, numRows, fullFeatureNames)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	result, err = onlineserving.KeepOnlyRequestedFeatures(result, featureRefs, featureService, fullFeatureNames)
+	result, err = onlineserving.KeepOnlyFeatures(result, resolved.plan.featureNames)
 	if err != nil {
 		return nil, err
 	}
@@ -212,8 +380,166 @@ func (fs *FeatureStore) GetOnlineFeatures(
 	return result, nil
 }
 
+// splitWrittenOnDemandFeatureViews separates the on demand feature views that are transformed from the ones that
+// are written to the online store, which are only transformed as a fallback. Written views whose features were
+// all found in the online store aren't returned.
+func splitWrittenOnDemandFeatureViews(
+	onDemandFeatureViews []*model.OnDemandFeatureView,
+	features []*onlineserving.FeatureVector,
+	fullFeatureNames bool) (transformed []*model.OnDemandFeatureView, fallback []*model.OnDemandFeatureView) {
+	vectorsByName := make(map[string]*onlineserving.FeatureVector, len(features))
+	for _, vector := range features {
+		vectorsByName[vector.Name] = vector
+	}
+	for _, odfv := range onDemandFeatureViews {
+		if !odfv.WriteToOnlineStore {
+			transformed = append(transformed, odfv)
+			continue
+		}
+		for _, feature := range odfv.Base.Projection.Features {
+			vector, ok := vectorsByName[writtenFeatureName(odfv, feature.Name, fullFeatureNames)]
+			if !ok || slices.Contains(vector.Statuses, serving.FieldStatus_NOT_FOUND) {
+				fallback = append(fallback, odfv)
+				break
+			}
+		}
+	}
+	return transformed, fallback
+}
+
+// fillMissingOnDemandFeatures fills the values of the written on demand feature views that weren't found in the
+// online store with their transformed values, and releases the transformed vectors.
+func fillMissingOnDemandFeatures(
+	features []*onlineserving.FeatureVector,
+	onDemandFeatureViews []*model.OnDemandFeatureView,
+	transformedFeatures []*onlineserving.FeatureVector,
+	arrowMemory memory.Allocator,
+	numRows int,
+	fullFeatureNames bool) error {
+	defer func() {
+		for _, vector := range transformedFeatures {
+			vector.Values.Release()
+		}
+	}()
+	transformedByName := make(map[string]*onlineserving.FeatureVector, len(transformedFeatures))
+	for _, vector := range transformedFeatures {
+		transformedByName[vector.Name] = vector
+	}
+	for _, vector := range features {
+		for _, odfv := range onDemandFeatureViews {
+			for _, feature := range odfv.Base.Projection.Features {
+				if vector.Name != writtenFeatureName(odfv, feature.Name, fullFeatureNames) {
+					continue
+				}
+				// Transformed features are named like the read ones, except for the transformation service,
+				// which returns the feature names without the view name
+				transformed, ok := transformedByName[vector.Name]
+				if !ok {
+					transformed, ok = transformedByName[feature.Name]
+				}
+				if !ok {
+					continue
+				}
+				if err := onlineserving.FillMissingValues(vector, transformed, arrowMemory, numRows); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func writtenFeatureName(odfv *model.OnDemandFeatureView, featureName string, fullFeatureNames bool) string {
+	if fullFeatureNames {
+		return odfv.Base.Projection.NameToUse() + "__" + featureName
+	}
+	return featureName
+}
+
+// resolveFeatures looks up the retrieval plan of the requested features, validates the request against it and
+// groups the feature references by the entities they are read for.
+func (fs *FeatureStore) resolveFeatures(
+	ctx context.Context,
+	featureRefs []string,
+	featureService *model.FeatureService,
+	joinKeyToEntityValues map[string]*prototypes.RepeatedValue,
+	requestData map[string]*prototypes.RepeatedValue,
+	fullFeatureNames bool) (resolved *resolvedFeatures, err error) {
+	_, span := tracing.StartSpan(ctx, "ResolveFeatures")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	plan, cached, err := fs.retrievalPlan(featureRefs, featureService, fullFeatureNames)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Bool("feast.cached_plan", cached))
+
+	if user, ok := auth.UserFromContext(ctx); ok {
+		err = fs.authorizeRead(user, plan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	numRows, err := onlineserving.ValidateEntityValues(joinKeyToEntityValues, requestData, plan.expectedJoinKeys)
+	if err != nil {
+		return nil, err
+	}
+	featureServiceName := ""
+	if featureService != nil {
+		featureServiceName = featureService.Name
+	}
+	metrics.EntityRows.WithLabelValues(fs.config.Project, featureServiceName).Observe(float64(numRows))
+	span.SetAttributes(attribute.Int("feast.entity_rows", numRows))
+
+	err = transformation.EnsureRequestedDataExist(plan.onDemandFeatureViews, requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.entityless {
+		dummyEntityColumn := &prototypes.RepeatedValue{Val: make([]*prototypes.Value, numRows)}
+		for index := 0; index < numRows; index++ {
+			dummyEntityColumn.Val[index] = &model.DUMMY_ENTITY_VALUE
+		}
+		joinKeyToEntityValues[model.DUMMY_ENTITY_ID] = dummyEntityColumn
+	}
+
+	groupedRefs, err := onlineserving.GroupFeatureRefs(plan.featureViews, joinKeyToEntityValues, plan.entityNameToJoinKey, fullFeatureNames)
+	if err != nil {
+		return nil, err
+	}
+	return &resolvedFeatures{
+		plan:        plan,
+		groupedRefs: groupedRefs,
+		numRows:     numRows,
+	}, nil
+}
+
+// authorizeRead checks that the project's permissions allow the user to read the resources of the retrieval
+// plan from the online store.
+func (fs *FeatureStore) authorizeRead(user *auth.User, plan *retrievalPlan) error {
+	permissions := fs.registry.ListPermissions(fs.config.Project)
+	if len(permissions) == 0 {
+		return nil
+	}
+	if err := auth.Enforce(permissions, user, plan.resources, core.PermissionSpec_READ_ONLINE); err != nil {
+		return FeastPermissionDenied{Message: err.Error()}
+	}
+	return nil
+}
+
+// DestructOnlineStore closes the online stores of all projects.
 func (fs *FeatureStore) DestructOnlineStore() {
-	fs.onlineStore.Destruct()
+	if fs.projectStores == nil {
+		fs.onlineStore.Destruct()
+		return
+	}
+	fs.projectStores.mu.Lock()
+	defer fs.projectStores.mu.Unlock()
+	for _, projectStore := range fs.projectStores.stores {
+		projectStore.onlineStore.Destruct()
+	}
 }
 
 // ParseFeatures parses the kind field of a GetOnlineFeaturesRequest protobuf message
@@ -317,20 +643,90 @@ func (fs *FeatureStore) GetFeatureView(featureViewName string, hideDummyEntity b
 	return fv, nil
 }
 
+// readGroupsFromOnlineStore reads the features of the entity groups concurrently, at most onlineReadConcurrency
+// groups at a time. The first failed read cancels the others. It returns the groups ordered by their key, with
+// the features read for each group at the same index.
+func (fs *FeatureStore) readGroupsFromOnlineStore(
+	ctx context.Context,
+	groupedRefs map[string]*onlineserving.GroupedFeaturesPerEntitySet,
+) ([]*onlineserving.GroupedFeaturesPerEntitySet, [][][]onlinestore.FeatureData, error) {
+	groupKeys := make([]string, 0, len(groupedRefs))
+	for groupKey := range groupedRefs {
+		groupKeys = append(groupKeys, groupKey)
+	}
+	slices.Sort(groupKeys)
+	groupRefs := make([]*onlineserving.GroupedFeaturesPerEntitySet, len(groupKeys))
+	for index, groupKey := range groupKeys {
+		groupRefs[index] = groupedRefs[groupKey]
+	}
+
+	featureData := make([][][]onlinestore.FeatureData, len(groupRefs))
+	if len(groupRefs) == 1 {
+		data, err := fs.readFromOnlineStore(ctx, groupRefs[0].EntityKeys, groupRefs[0].FeatureViewNames, groupRefs[0].FeatureNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		featureData[0] = data
+		return groupRefs, featureData, nil
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(max(fs.onlineReadConcurrency, 1))
+	for index, groupRef := range groupRefs {
+		group.Go(func() error {
+			data, err := fs.readFromOnlineStore(ctx, groupRef.EntityKeys, groupRef.FeatureViewNames, groupRef.FeatureNames)
+			if err != nil {
+				return err
+			}
+			featureData[index] = data
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, nil, err
+	}
+	return groupRefs, featureData, nil
+}
+
 func (fs *FeatureStore) readFromOnlineStore(ctx context.Context, entityRows []*prototypes.EntityKey,
 	requestedFeatureViewNames []string,
 	requestedFeatureNames []string,
 ) ([][]onlinestore.FeatureData, error) {
-	// Create a Datadog span from context
-	//span, _ := tracer.StartSpanFromContext(ctx, "fs.readFromOnlineStore")
-	//defer span.Finish()
+	onlineStoreType := onlinestore.GetOnlineStoreType(fs.config)
+	featureViewNames := uniqueNames(requestedFeatureViewNames)
+	ctx, span := tracing.StartSpan(ctx, "readFromOnlineStore",
+		attribute.String("feast.online_store", onlineStoreType),
+		attribute.StringSlice("feast.feature_views", featureViewNames),
+		attribute.Int("feast.entity_keys", len(entityRows)))
 
 	numRows := len(entityRows)
 	entityRowsValue := make([]*prototypes.EntityKey, numRows)
 	for index, entityKey := range entityRows {
 		entityRowsValue[index] = &prototypes.EntityKey{JoinKeys: entityKey.JoinKeys, EntityValues: entityKey.EntityValues}
 	}
-	return fs.onlineStore.OnlineRead(ctx, entityRowsValue, requestedFeatureViewNames, requestedFeatureNames)
+	start := time.Now()
+	featureData, err := fs.onlineStore.OnlineRead(ctx, entityRowsValue, requestedFeatureViewNames, requestedFeatureNames)
+	tracing.EndSpan(span, err)
+	if err == nil {
+		duration := time.Since(start).Seconds()
+		for _, featureViewName := range featureViewNames {
+			metrics.OnlineReadDuration.WithLabelValues(onlineStoreType, featureViewName).Observe(duration)
+		}
+	}
+	return featureData, err
+}
+
+// uniqueNames returns the names without duplicates, keeping the order of their first occurrence.
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
 }
 
 func (fs *FeatureStore) GetFcosMap() (map[string]*model.Entity, map[string]*model.FeatureView, map[string]*model.OnDemandFeatureView, error) {
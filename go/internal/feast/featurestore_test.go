@@ -2,21 +2,31 @@ package feast
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
 	"github.com/feast-dev/feast/go/internal/feast/onlinestore"
 	"github.com/feast-dev/feast/go/internal/feast/registry"
 	"github.com/feast-dev/feast/go/internal/test"
+	"github.com/feast-dev/feast/go/protos/feast/core"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
 	"github.com/feast-dev/feast/go/protos/feast/types"
+	gotypes "github.com/feast-dev/feast/go/types"
 )
 
 var featureRepoBasePath string
@@ -123,6 +133,11 @@ func (m *MockRedis) OnlineRead(ctx context.Context, entityKeys []*types.EntityKe
 	return fd, args.Error(1)
 }
 
+func (m *MockRedis) OnlineWrite(ctx context.Context, featureViewName string, rows []*onlinestore.FeatureRow) error {
+	args := m.Called(ctx, featureViewName, rows)
+	return args.Error(0)
+}
+
 func TestGetOnlineFeatures(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -159,6 +174,36 @@ func TestGetOnlineFeatures(t *testing.T) {
 			},
 			fn: testRedisODFVNoTransformationService,
 		},
+		{
+			name: "redis write to online store",
+			config: &registry.RepoConfig{
+				Project: "feature_repo",
+				Registry: map[string]interface{}{
+					"path": featureRepoRegistryFile,
+				},
+				Provider: "local",
+				OnlineStore: map[string]interface{}{
+					"type":              "redis",
+					"connection_string": "localhost:6379",
+				},
+			},
+			fn: testRedisWriteToOnlineStore,
+		},
+		{
+			name: "redis push to unknown push source",
+			config: &registry.RepoConfig{
+				Project: "feature_repo",
+				Registry: map[string]interface{}{
+					"path": featureRepoRegistryFile,
+				},
+				Provider: "local",
+				OnlineStore: map[string]interface{}{
+					"type":              "redis",
+					"connection_string": "localhost:6379",
+				},
+			},
+			fn: testRedisPushSourceNotFound,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -240,3 +285,234 @@ func testRedisODFVNoTransformationService(t *testing.T, fs *FeatureStore) {
 	assert.ErrorAs(t, err, &FeastTransformationServiceNotConfigured{})
 
 }
+
+func testRedisWriteToOnlineStore(t *testing.T, fs *FeatureStore) {
+	data := map[string]*types.RepeatedValue{
+		"driver_id":       {Val: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: 1001}}, {Val: &types.Value_Int64Val{Int64Val: 1002}}}},
+		"conv_rate":       {Val: []*types.Value{{Val: &types.Value_DoubleVal{DoubleVal: 0.5}}, {Val: &types.Value_DoubleVal{DoubleVal: 0.25}}}},
+		"event_timestamp": {Val: []*types.Value{{Val: &types.Value_StringVal{StringVal: "2024-01-02T03:04:05Z"}}, {Val: &types.Value_StringVal{StringVal: "2024-01-02T03:04:05Z"}}}},
+	}
+
+	ctx := context.Background()
+	mr := fs.onlineStore.(*MockRedis)
+	mr.On("OnlineWrite", ctx, "driver_hourly_stats", mock.Anything).Return(nil)
+	err := fs.WriteToOnlineStore(ctx, "driver_hourly_stats", data, true)
+	require.Nil(t, err)
+
+	rows := mr.Calls[0].Arguments.Get(2).([]*onlinestore.FeatureRow)
+	require.Len(t, rows, 2)
+	assert.Equal(t, int64(1001), rows[0].EntityKey.EntityValues[0].GetInt64Val())
+	assert.Equal(t, float32(0.25), rows[1].Values["conv_rate"].GetFloatVal())
+	assert.Equal(t, int64(1704164645), rows[0].EventTimestamp.Unix())
+}
+
+func testRedisPushSourceNotFound(t *testing.T, fs *FeatureStore) {
+	data := map[string]*types.RepeatedValue{
+		"driver_id": {Val: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: 1001}}}},
+		"conv_rate": {Val: []*types.Value{{Val: &types.Value_DoubleVal{DoubleVal: 0.5}}}},
+	}
+
+	err := fs.Push(context.Background(), "unknown_push_source", data, PushModeOnline, true)
+	assert.ErrorAs(t, err, &FeastPushSourceNotFound{})
+	err = fs.Push(context.Background(), "unknown_push_source", data, PushModeOffline, true)
+	assert.ErrorAs(t, err, &FeastPushModeNotSupported{})
+	fs.onlineStore.(*MockRedis).AssertNotCalled(t, "OnlineWrite", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestForProject(t *testing.T) {
+	repoPath := t.TempDir()
+	registryPath := filepath.Join(repoPath, "registry.db")
+	registryStore := registry.NewFileRegistryStore(&registry.RegistryConfig{Path: registryPath}, repoPath)
+	require.Nil(t, registryStore.UpdateRegistryProto(&core.Registry{
+		Entities: []*core.Entity{
+			{Spec: &core.EntitySpecV2{Name: "driver", Project: "feature_repo", JoinKey: "driver_id"}, Meta: &core.EntityMeta{}},
+			{Spec: &core.EntitySpecV2{Name: "customer", Project: "other_repo", JoinKey: "customer_id"}, Meta: &core.EntityMeta{}},
+		},
+	}))
+	config := &registry.RepoConfig{
+		Project:     "feature_repo",
+		RepoPath:    repoPath,
+		Registry:    map[string]interface{}{"path": registryPath},
+		Provider:    "local",
+		OnlineStore: map[string]interface{}{"type": "sqlite", "path": "online_store.db"},
+	}
+	fs, err := NewFeatureStore(config, nil)
+	require.Nil(t, err)
+	defer fs.DestructOnlineStore()
+
+	assert.Equal(t, []string{"feature_repo", "other_repo"}, fs.ListProjects())
+	sameFs, err := fs.ForProject("")
+	require.Nil(t, err)
+	assert.Same(t, fs, sameFs)
+
+	otherFs, err := fs.ForProject("other_repo")
+	require.Nil(t, err)
+	assert.Equal(t, "other_repo", otherFs.GetRepoConfig().Project)
+	assert.Equal(t, "feature_repo", fs.GetRepoConfig().Project)
+	entities, err := otherFs.ListEntities(true)
+	require.Nil(t, err)
+	require.Len(t, entities, 1)
+	assert.Equal(t, "customer", entities[0].Name)
+
+	// Project feature stores are created once and can switch back to the default project
+	sameOtherFs, err := fs.ForProject("other_repo")
+	require.Nil(t, err)
+	assert.Same(t, otherFs, sameOtherFs)
+	sameFs, err = otherFs.ForProject("feature_repo")
+	require.Nil(t, err)
+	assert.Same(t, fs, sameFs)
+
+	_, err = fs.ForProject("unknown_repo")
+	assert.ErrorAs(t, err, &FeastProjectNotFound{})
+}
+
+func TestNewFeatureStoreWithOnlineStoreCache(t *testing.T) {
+	repoPath := t.TempDir()
+	registryPath := filepath.Join(repoPath, "registry.db")
+	registryStore := registry.NewFileRegistryStore(&registry.RegistryConfig{Path: registryPath}, repoPath)
+	require.Nil(t, registryStore.UpdateRegistryProto(driverRegistryForTest("conv_rate")))
+	config := &registry.RepoConfig{
+		Project:       "feature_repo",
+		RepoPath:      repoPath,
+		Registry:      map[string]interface{}{"path": registryPath},
+		Provider:      "local",
+		OnlineStore:   map[string]interface{}{"type": "sqlite", "path": "online_store.db"},
+		FeatureServer: map[string]interface{}{"online_store_cache": map[string]interface{}{"max_entries": float64(10)}},
+	}
+	fs, err := NewFeatureStore(config, nil)
+	require.Nil(t, err)
+	defer fs.DestructOnlineStore()
+	assert.IsType(t, &onlinestore.CachedOnlineStore{}, fs.onlineStore)
+
+	config.FeatureServer = nil
+	uncachedFs, err := NewFeatureStore(config, nil)
+	require.Nil(t, err)
+	defer uncachedFs.DestructOnlineStore()
+	assert.IsType(t, &onlinestore.SqliteOnlineStore{}, uncachedFs.onlineStore)
+}
+
+// concurrentReadStore records how many reads run at the same time. Reads of the feature view "failing" fail,
+// and the other reads wait until released or cancelled.
+type concurrentReadStore struct {
+	MockRedis
+	mu            sync.Mutex
+	running       int
+	maxRunning    int
+	release       chan struct{}
+	cancelledRead chan struct{}
+}
+
+func (s *concurrentReadStore) OnlineRead(ctx context.Context, entityKeys []*types.EntityKey, featureViewNames []string, featureNames []string) ([][]onlinestore.FeatureData, error) {
+	s.mu.Lock()
+	s.running++
+	s.maxRunning = max(s.maxRunning, s.running)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running--
+		s.mu.Unlock()
+	}()
+
+	if featureViewNames[0] == "failing" {
+		return nil, errors.New("read failed")
+	}
+	select {
+	case <-s.release:
+		return [][]onlinestore.FeatureData{{{Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewNames[0], FeatureName: featureNames[0]}}}}, nil
+	case <-ctx.Done():
+		s.cancelledRead <- struct{}{}
+		return nil, ctx.Err()
+	}
+}
+
+func groupedRefsForTest(featureViewNames ...string) map[string]*onlineserving.GroupedFeaturesPerEntitySet {
+	groupedRefs := make(map[string]*onlineserving.GroupedFeaturesPerEntitySet)
+	for _, featureViewName := range featureViewNames {
+		groupedRefs[featureViewName] = &onlineserving.GroupedFeaturesPerEntitySet{
+			FeatureNames:     []string{"feature"},
+			FeatureViewNames: []string{featureViewName},
+		}
+	}
+	return groupedRefs
+}
+
+func TestReadGroupsFromOnlineStoreConcurrently(t *testing.T) {
+	store := &concurrentReadStore{release: make(chan struct{})}
+	fs := &FeatureStore{config: &registry.RepoConfig{Project: "feature_repo"}, onlineStore: store, onlineReadConcurrency: 2}
+
+	go func() {
+		for i := 0; i < 4; i++ {
+			// Wait until two reads run at the same time before releasing one
+			for {
+				store.mu.Lock()
+				running := store.running
+				store.mu.Unlock()
+				if running == 2 || i >= 3 {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			store.release <- struct{}{}
+		}
+	}()
+	groupRefs, featureData, err := fs.readGroupsFromOnlineStore(context.Background(), groupedRefsForTest("d", "b", "a", "c"))
+	require.Nil(t, err)
+	assert.Equal(t, 2, store.maxRunning)
+	require.Len(t, groupRefs, 4)
+	for index, featureViewName := range []string{"a", "b", "c", "d"} {
+		assert.Equal(t, []string{featureViewName}, groupRefs[index].FeatureViewNames)
+		assert.Equal(t, featureViewName, featureData[index][0][0].Reference.FeatureViewName)
+	}
+}
+
+func TestReadGroupsFromOnlineStoreCancelsReadsAfterFailure(t *testing.T) {
+	store := &concurrentReadStore{release: make(chan struct{}), cancelledRead: make(chan struct{}, 1)}
+	fs := &FeatureStore{config: &registry.RepoConfig{Project: "feature_repo"}, onlineStore: store, onlineReadConcurrency: 2}
+
+	_, _, err := fs.readGroupsFromOnlineStore(context.Background(), groupedRefsForTest("a", "failing"))
+	assert.EqualError(t, err, "read failed")
+	select {
+	case <-store.cancelledRead:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the other read was not cancelled")
+	}
+}
+
+func TestWrittenOnDemandFeaturesAreOnlyTransformedWhenMissing(t *testing.T) {
+	newView := func(name string, written bool) *model.OnDemandFeatureView {
+		return model.NewOnDemandFeatureViewFromProto(&core.OnDemandFeatureView{Spec: &core.OnDemandFeatureViewSpec{
+			Name:               name,
+			Features:           []*core.FeatureSpecV2{{Name: name + "_value", ValueType: types.ValueType_INT64}},
+			WriteToOnlineStore: written,
+		}})
+	}
+	newVector := func(name string, statuses ...serving.FieldStatus) *onlineserving.FeatureVector {
+		values := make([]*types.Value, len(statuses))
+		for index, status := range statuses {
+			if status == serving.FieldStatus_PRESENT {
+				values[index] = &types.Value{Val: &types.Value_Int64Val{Int64Val: int64(index + 1)}}
+			}
+		}
+		arrowValues, err := gotypes.ProtoValuesToArrowArray(values, memory.DefaultAllocator, len(statuses))
+		require.Nil(t, err)
+		return &onlineserving.FeatureVector{Name: name, Values: arrowValues, Statuses: statuses, Timestamps: make([]*timestamppb.Timestamp, len(statuses))}
+	}
+	present, notFound := serving.FieldStatus_PRESENT, serving.FieldStatus_NOT_FOUND
+	transformedView, foundView, missingView := newView("transformed", false), newView("found", true), newView("missing", true)
+	features := []*onlineserving.FeatureVector{
+		newVector("found__found_value", present, present),
+		newVector("missing__missing_value", present, notFound),
+	}
+
+	transformed, fallback := splitWrittenOnDemandFeatureViews(
+		[]*model.OnDemandFeatureView{transformedView, foundView, missingView}, features, true)
+	assert.Equal(t, []*model.OnDemandFeatureView{transformedView}, transformed)
+	assert.Equal(t, []*model.OnDemandFeatureView{missingView}, fallback)
+
+	// The transformation service returns the feature names without the view name
+	err := fillMissingOnDemandFeatures(features, fallback, []*onlineserving.FeatureVector{newVector("missing_value", present, present)},
+		memory.DefaultAllocator, 2, true)
+	require.Nil(t, err)
+	assert.Equal(t, []serving.FieldStatus{present, present}, features[1].Statuses)
+	assert.Equal(t, []int64{1, 2}, features[1].Values.(*array.Int64).Int64Values())
+}
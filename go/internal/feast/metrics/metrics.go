@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "feast"
+
+// Registry holds all metrics of the feature server, together with the Go runtime and process metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HttpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by handler and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"handler", "code"})
+
+	GrpcRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Latency of gRPC requests by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	OnlineFeaturesDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "online_features_duration_seconds",
+		Help:      "Latency of online feature retrieval by project and feature service. Requests for feature references have an empty feature service.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"project", "feature_service"})
+
+	EntityRows = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "online_features_entity_rows",
+		Help:      "Number of entity rows per online feature request by project and feature service.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+	}, []string{"project", "feature_service"})
+
+	OnlineReadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "online_read_duration_seconds",
+		Help:      "Latency of online store reads by online store type and feature view. Feature views read together share a read.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"online_store", "feature_view"})
+
+	OnlineReadFailedBatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "online_read_failed_batches_total",
+		Help:      "Number of failed batches of online store reads by online store type, whether or not the reads returned partial results.",
+	}, []string{"online_store"})
+
+	OnlineStoreCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "online_store_cache_lookups_total",
+		Help:      "Number of entity rows looked up in the online store cache by result, hit or miss.",
+	}, []string{"result"})
+
+	FeatureStatuses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feature_status_total",
+		Help:      "Number of returned feature values by feature view and field status, e.g. NOT_FOUND or OUTSIDE_MAX_AGE.",
+	}, []string{"feature_view", "status"})
+
+	DroppedLogs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logger_dropped_logs_total",
+		Help:      "Number of feature logs dropped because the logger channel was full.",
+	}, []string{"feature_service"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HttpRequestDuration,
+		GrpcRequestDuration,
+		OnlineFeaturesDuration,
+		EntityRows,
+		OnlineReadDuration,
+		OnlineReadFailedBatches,
+		OnlineStoreCacheLookups,
+		FeatureStatuses,
+		DroppedLogs,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// statusRecorder captures the status code written by an HTTP handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// InstrumentHandler records the latency of the requests served by the handler under the given handler name.
+func InstrumentHandler(handlerName string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			HttpRequestDuration.WithLabelValues(handlerName, strconv.Itoa(recorder.status)).Observe(time.Since(start).Seconds())
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// UnaryServerInterceptor records the latency of unary gRPC requests.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	GrpcRequestDuration.WithLabelValues(info.FullMethod, status.Code(err).String()).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// StreamServerInterceptor records the duration of streaming gRPC requests.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	GrpcRequestDuration.WithLabelValues(info.FullMethod, status.Code(err).String()).Observe(time.Since(start).Seconds())
+	return err
+}
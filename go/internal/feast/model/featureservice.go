@@ -13,6 +13,7 @@ type FeatureService struct {
 	LastUpdatedTimestamp *timestamppb.Timestamp
 	Projections          []*FeatureViewProjection
 	LoggingConfig        *FeatureServiceLoggingConfig
+	Tags                 map[string]string
 }
 
 type FeatureServiceLoggingConfig struct {
@@ -36,5 +37,6 @@ func NewFeatureServiceFromProto(proto *core.FeatureService) *FeatureService {
 		LastUpdatedTimestamp: proto.Meta.LastUpdatedTimestamp,
 		Projections:          projections,
 		LoggingConfig:        loggingConfig,
+		Tags:                 proto.Spec.Tags,
 	}
 }
@@ -20,11 +20,18 @@ type FeatureView struct {
 	Ttl           *durationpb.Duration
 	EntityNames   []string
 	EntityColumns []*Field
+	// Name of the push source feeding this view, empty if the view has no push source
+	PushSourceName string
+	// Event timestamp and created timestamp columns of the view's batch source
+	TimestampField         string
+	CreatedTimestampColumn string
+	Tags                   map[string]string
 }
 
 func NewFeatureViewFromProto(proto *core.FeatureView) *FeatureView {
 	featureView := &FeatureView{Base: NewBaseFeatureView(proto.Spec.Name, proto.Spec.Features),
-		Ttl: proto.Spec.Ttl,
+		Ttl:  proto.Spec.Ttl,
+		Tags: proto.Spec.Tags,
 	}
 	if len(proto.Spec.Entities) == 0 {
 		featureView.EntityNames = []string{DUMMY_ENTITY_NAME}
@@ -36,12 +43,14 @@ func NewFeatureViewFromProto(proto *core.FeatureView) *FeatureView {
 		entityColumns[i] = NewFieldFromProto(entityColumn)
 	}
 	featureView.EntityColumns = entityColumns
+	featureView.setSources(proto.Spec.BatchSource, proto.Spec.StreamSource)
 	return featureView
 }
 
 func NewFeatureViewFromStreamFeatureViewProto(proto *core.StreamFeatureView) *FeatureView {
 	featureView := &FeatureView{Base: NewBaseFeatureView(proto.Spec.Name, proto.Spec.Features),
-		Ttl: proto.Spec.Ttl,
+		Ttl:  proto.Spec.Ttl,
+		Tags: proto.Spec.Tags,
 	}
 	if len(proto.Spec.Entities) == 0 {
 		featureView.EntityNames = []string{DUMMY_ENTITY_NAME}
@@ -53,14 +62,30 @@ func NewFeatureViewFromStreamFeatureViewProto(proto *core.StreamFeatureView) *Fe
 		entityColumns[i] = NewFieldFromProto(entityColumn)
 	}
 	featureView.EntityColumns = entityColumns
+	featureView.setSources(proto.Spec.BatchSource, proto.Spec.StreamSource)
 	return featureView
 }
 
+func (fv *FeatureView) setSources(batchSource *core.DataSource, streamSource *core.DataSource) {
+	if batchSource != nil {
+		fv.TimestampField = batchSource.TimestampField
+		fv.CreatedTimestampColumn = batchSource.CreatedTimestampColumn
+	}
+	if streamSource != nil && streamSource.Type == core.DataSource_PUSH_SOURCE {
+		fv.PushSourceName = streamSource.Name
+	}
+}
+
 func (fv *FeatureView) NewFeatureViewFromBase(base *BaseFeatureView) *FeatureView {
 	ttl := durationpb.Duration{Seconds: fv.Ttl.Seconds, Nanos: fv.Ttl.Nanos}
 	featureView := &FeatureView{Base: base,
-		Ttl:         &ttl,
-		EntityNames: fv.EntityNames,
+		Ttl:                    &ttl,
+		EntityNames:            fv.EntityNames,
+		EntityColumns:          fv.EntityColumns,
+		PushSourceName:         fv.PushSourceName,
+		TimestampField:         fv.TimestampField,
+		CreatedTimestampColumn: fv.CreatedTimestampColumn,
+		Tags:                   fv.Tags,
 	}
 	return featureView
 }
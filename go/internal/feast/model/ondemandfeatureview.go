@@ -1,6 +1,8 @@
 package model
 
 import (
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+
 	"github.com/feast-dev/feast/go/protos/feast/core"
 	"github.com/feast-dev/feast/go/protos/feast/types"
 )
@@ -9,12 +11,31 @@ type OnDemandFeatureView struct {
 	Base                         *BaseFeatureView
 	SourceFeatureViewProjections map[string]*FeatureViewProjection
 	SourceRequestDataSources     map[string]*core.DataSource_RequestDataOptions
+	Tags                         map[string]string
+	// SubstraitPlan is the serialized Substrait plan of the transformation, if it has one
+	SubstraitPlan []byte
+	// Whether the transformed features are written to the online store, keyed by the entities of the view
+	WriteToOnlineStore bool
+	EntityNames        []string
+	EntityColumns      []*Field
 }
 
 func NewOnDemandFeatureViewFromProto(proto *core.OnDemandFeatureView) *OnDemandFeatureView {
 	onDemandFeatureView := &OnDemandFeatureView{Base: NewBaseFeatureView(proto.Spec.Name, proto.Spec.Features),
 		SourceFeatureViewProjections: make(map[string]*FeatureViewProjection),
 		SourceRequestDataSources:     make(map[string]*core.DataSource_RequestDataOptions),
+		Tags:                         proto.Spec.Tags,
+		SubstraitPlan:                proto.Spec.GetFeatureTransformation().GetSubstraitTransformation().GetSubstraitPlan(),
+		WriteToOnlineStore:           proto.Spec.WriteToOnlineStore,
+	}
+	if len(proto.Spec.Entities) == 0 {
+		onDemandFeatureView.EntityNames = []string{DUMMY_ENTITY_NAME}
+	} else {
+		onDemandFeatureView.EntityNames = proto.Spec.Entities
+	}
+	onDemandFeatureView.EntityColumns = make([]*Field, len(proto.Spec.EntityColumns))
+	for i, entityColumn := range proto.Spec.EntityColumns {
+		onDemandFeatureView.EntityColumns[i] = NewFieldFromProto(entityColumn)
 	}
 	for sourceName, onDemandSource := range proto.Spec.Sources {
 		if onDemandSourceFeatureView, ok := onDemandSource.Source.(*core.OnDemandSource_FeatureView); ok {
@@ -44,10 +65,27 @@ func (fs *OnDemandFeatureView) NewWithProjection(projection *FeatureViewProjecti
 		Base:                         projectedBase,
 		SourceFeatureViewProjections: fs.SourceFeatureViewProjections,
 		SourceRequestDataSources:     fs.SourceRequestDataSources,
+		Tags:                         fs.Tags,
+		SubstraitPlan:                fs.SubstraitPlan,
+		WriteToOnlineStore:           fs.WriteToOnlineStore,
+		EntityNames:                  fs.EntityNames,
+		EntityColumns:                fs.EntityColumns,
 	}
 	return featureView, nil
 }
 
+// AsFeatureView returns a feature view that reads the features of a view written to the online store. The
+// written features don't expire.
+func (fs *OnDemandFeatureView) AsFeatureView() *FeatureView {
+	return &FeatureView{
+		Base:          fs.Base,
+		Ttl:           &durationpb.Duration{},
+		EntityNames:   fs.EntityNames,
+		EntityColumns: fs.EntityColumns,
+		Tags:          fs.Tags,
+	}
+}
+
 func NewOnDemandFeatureViewFromBase(base *BaseFeatureView) *OnDemandFeatureView {
 	featureView := &OnDemandFeatureView{
 		Base:                         base,
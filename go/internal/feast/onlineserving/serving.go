@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
 	"github.com/feast-dev/feast/go/internal/feast/model"
 	"github.com/feast-dev/feast/go/internal/feast/onlinestore"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
@@ -64,12 +66,14 @@ Return
 	(1) requested feature views and features grouped per View
 	(2) requested on demand feature views
 
-existed in the registry
+existed in the registry. On demand feature views that are written to the online store are read like feature
+views, and are only returned as on demand feature views too if transformationFallback is set.
 */
 func GetFeatureViewsToUseByService(
 	featureService *model.FeatureService,
 	featureViews map[string]*model.FeatureView,
-	onDemandFeatureViews map[string]*model.OnDemandFeatureView) ([]*FeatureViewAndRefs, []*model.OnDemandFeatureView, error) {
+	onDemandFeatureViews map[string]*model.OnDemandFeatureView,
+	transformationFallback bool) ([]*FeatureViewAndRefs, []*model.OnDemandFeatureView, error) {
 
 	viewNameToViewAndRefs := make(map[string]*FeatureViewAndRefs)
 	odFvsToUse := make([]*model.OnDemandFeatureView, 0)
@@ -101,6 +105,12 @@ func GetFeatureViewsToUseByService(
 			if err != nil {
 				return nil, nil, err
 			}
+			if projectedOdFv.WriteToOnlineStore {
+				addWrittenOdFv(projectedOdFv, viewNameToViewAndRefs)
+				if !transformationFallback {
+					continue
+				}
+			}
 			odFvsToUse = append(odFvsToUse, projectedOdFv)
 			err = extractOdFvDependencies(
 				projectedOdFv,
@@ -130,12 +140,14 @@ Return
 	(1) requested feature views and features grouped per View
 	(2) requested on demand feature views
 
-existed in the registry
+existed in the registry. On demand feature views that are written to the online store are read like feature
+views, and are only returned as on demand feature views too if transformationFallback is set.
 */
 func GetFeatureViewsToUseByFeatureRefs(
 	features []string,
 	featureViews map[string]*model.FeatureView,
-	onDemandFeatureViews map[string]*model.OnDemandFeatureView) ([]*FeatureViewAndRefs, []*model.OnDemandFeatureView, error) {
+	onDemandFeatureViews map[string]*model.OnDemandFeatureView,
+	transformationFallback bool) ([]*FeatureViewAndRefs, []*model.OnDemandFeatureView, error) {
 	viewNameToViewAndRefs := make(map[string]*FeatureViewAndRefs)
 	odFvToFeatures := make(map[string][]string)
 
@@ -173,6 +185,12 @@ func GetFeatureViewsToUseByFeatureRefs(
 		if err != nil {
 			return nil, nil, err
 		}
+		if projectedOdFv.WriteToOnlineStore {
+			addWrittenOdFv(projectedOdFv, viewNameToViewAndRefs)
+			if !transformationFallback {
+				continue
+			}
+		}
 
 		err = extractOdFvDependencies(
 			projectedOdFv,
@@ -192,6 +210,21 @@ func GetFeatureViewsToUseByFeatureRefs(
 	return fvsToUse, odFvsToUse, nil
 }
 
+// addWrittenOdFv adds an on demand feature view that is written to the online store to the views that are read
+// from the online store.
+func addWrittenOdFv(odFv *model.OnDemandFeatureView, requestedFeatures map[string]*FeatureViewAndRefs) {
+	nameToUse := odFv.Base.Projection.NameToUse()
+	if _, ok := requestedFeatures[nameToUse]; !ok {
+		requestedFeatures[nameToUse] = &FeatureViewAndRefs{
+			View:        odFv.AsFeatureView(),
+			FeatureRefs: []string{},
+		}
+	}
+	for _, feature := range odFv.Base.Projection.Features {
+		requestedFeatures[nameToUse].FeatureRefs = addStringIfNotContains(requestedFeatures[nameToUse].FeatureRefs, feature.Name)
+	}
+}
+
 func extractOdFvDependencies(
 	odFv *model.OnDemandFeatureView,
 	sourceFvs map[string]*model.FeatureView,
@@ -342,6 +375,11 @@ func ValidateFeatureRefs(requestedFeatures []*FeatureViewAndRefs, fullFeatureNam
 	return nil
 }
 
+type featureViewStatus struct {
+	featureViewName string
+	status          serving.FieldStatus
+}
+
 func TransposeFeatureRowsIntoColumns(featureData2D [][]onlinestore.FeatureData,
 	groupRef *GroupedFeaturesPerEntitySet,
 	requestedFeatureViews []*FeatureViewAndRefs,
@@ -362,6 +400,8 @@ func TransposeFeatureRowsIntoColumns(featureData2D [][]onlinestore.FeatureData,
 	var featureViewName string
 
 	vectors := make([]*FeatureVector, 0)
+	// Number of returned values per feature view and status, added to the metrics at once
+	statusCounts := make(map[featureViewStatus]int)
 
 	for featureIndex := 0; featureIndex < numFeatures; featureIndex++ {
 		currentVector := &FeatureVector{
@@ -398,6 +438,7 @@ func TransposeFeatureRowsIntoColumns(featureData2D [][]onlinestore.FeatureData,
 				currentVector.Statuses[rowIndex] = status
 				currentVector.Timestamps[rowIndex] = eventTimeStamp
 			}
+			statusCounts[featureViewStatus{groupRef.FeatureViewNames[featureIndex], status}] += len(outputIndexes)
 		}
 		arrowValues, err := types.ProtoValuesToArrowArray(protoValues, arrowAllocator, numRows)
 		if err != nil {
@@ -406,6 +447,9 @@ func TransposeFeatureRowsIntoColumns(featureData2D [][]onlinestore.FeatureData,
 		currentVector.Values = arrowValues
 	}
 
+	for key, count := range statusCounts {
+		metrics.FeatureStatuses.WithLabelValues(key.featureViewName, key.status.String()).Add(float64(count))
+	}
 	return vectors, nil
 
 }
@@ -415,35 +459,92 @@ func KeepOnlyRequestedFeatures(
 	requestedFeatureRefs []string,
 	featureService *model.FeatureService,
 	fullFeatureNames bool) ([]*FeatureVector, error) {
-	vectorsByName := make(map[string]*FeatureVector)
-	expectedVectors := make([]*FeatureVector, 0)
-
-	usedVectors := make(map[string]bool)
-
-	for _, vector := range vectors {
-		vectorsByName[vector.Name] = vector
+	featureNames, err := RequestedFeatureNames(requestedFeatureRefs, featureService, fullFeatureNames)
+	if err != nil {
+		return nil, err
 	}
+	return KeepOnlyFeatures(vectors, featureNames)
+}
 
+// RequestedFeatureNames returns the names of the requested features in the order they are returned in.
+func RequestedFeatureNames(
+	requestedFeatureRefs []string,
+	featureService *model.FeatureService,
+	fullFeatureNames bool) ([]string, error) {
+	featureRefs := requestedFeatureRefs
 	if featureService != nil {
+		featureRefs = make([]string, 0, len(requestedFeatureRefs))
+		featureRefs = append(featureRefs, requestedFeatureRefs...)
 		for _, projection := range featureService.Projections {
 			for _, f := range projection.Features {
-				requestedFeatureRefs = append(requestedFeatureRefs,
+				featureRefs = append(featureRefs,
 					fmt.Sprintf("%s:%s", projection.NameToUse(), f.Name))
 			}
 		}
 	}
 
-	for _, featureRef := range requestedFeatureRefs {
+	featureNames := make([]string, len(featureRefs))
+	for index, featureRef := range featureRefs {
 		viewName, featureName, err := ParseFeatureReference(featureRef)
 		if err != nil {
 			return nil, err
 		}
-		qualifiedName := getQualifiedFeatureName(viewName, featureName, fullFeatureNames)
-		if _, ok := vectorsByName[qualifiedName]; !ok {
-			return nil, fmt.Errorf("requested feature %s can't be retrieved", featureRef)
+		featureNames[index] = getQualifiedFeatureName(viewName, featureName, fullFeatureNames)
+	}
+	return featureNames, nil
+}
+
+// FillMissingValues replaces the values of the rows that weren't found with the values of the fallback vector,
+// along with their statuses and timestamps.
+func FillMissingValues(vector *FeatureVector, fallback *FeatureVector, arrowAllocator memory.Allocator, numRows int) error {
+	if !slices.Contains(vector.Statuses, serving.FieldStatus_NOT_FOUND) {
+		return nil
+	}
+	values := make([]*prototypes.Value, numRows)
+	if vector.Values.DataType().ID() != arrow.NULL {
+		var err error
+		values, err = types.ArrowValuesToProtoValues(vector.Values)
+		if err != nil {
+			return err
+		}
+	}
+	fallbackValues, err := types.ArrowValuesToProtoValues(fallback.Values)
+	if err != nil {
+		return err
+	}
+	for row := 0; row < numRows; row++ {
+		if vector.Statuses[row] == serving.FieldStatus_NOT_FOUND {
+			values[row] = fallbackValues[row]
+			vector.Statuses[row] = fallback.Statuses[row]
+			vector.Timestamps[row] = fallback.Timestamps[row]
+		}
+	}
+	arrowValues, err := types.ProtoValuesToArrowArray(values, arrowAllocator, numRows)
+	if err != nil {
+		return err
+	}
+	vector.Values.Release()
+	vector.Values = arrowValues
+	return nil
+}
+
+// KeepOnlyFeatures returns the vectors of the named features in the order of the names and releases the other vectors.
+func KeepOnlyFeatures(vectors []*FeatureVector, featureNames []string) ([]*FeatureVector, error) {
+	vectorsByName := make(map[string]*FeatureVector)
+	expectedVectors := make([]*FeatureVector, 0, len(featureNames))
+
+	usedVectors := make(map[string]bool)
+
+	for _, vector := range vectors {
+		vectorsByName[vector.Name] = vector
+	}
+
+	for _, featureName := range featureNames {
+		if _, ok := vectorsByName[featureName]; !ok {
+			return nil, fmt.Errorf("requested feature %s can't be retrieved", featureName)
 		}
-		expectedVectors = append(expectedVectors, vectorsByName[qualifiedName])
-		usedVectors[qualifiedName] = true
+		expectedVectors = append(expectedVectors, vectorsByName[featureName])
+		usedVectors[featureName] = true
 	}
 
 	// Free arrow arrays for vectors that were not used.
@@ -586,7 +687,8 @@ func GroupFeatureRefs(requestedFeatureViews []*FeatureViewAndRefs,
 			}
 
 			groups[groupKey] = &GroupedFeaturesPerEntitySet{
-				FeatureNames:        featureNames,
+				// Copied because more features may be appended to it, and the requested views can be shared
+				FeatureNames:        slices.Clone(featureNames),
 				FeatureViewNames:    featureViewNames,
 				AliasedFeatureNames: aliasedFeatureNames,
 				Indices:             mappingIndices,
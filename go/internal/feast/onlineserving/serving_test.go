@@ -2,14 +2,24 @@ package onlineserving
 
 import (
 	"testing"
+	"time"
 
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
 	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/internal/feast/onlinestore"
 	"github.com/feast-dev/feast/go/protos/feast/core"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
 	"github.com/feast-dev/feast/go/protos/feast/types"
+	gotypes "github.com/feast-dev/feast/go/types"
 )
 
 func TestGroupingFeatureRefs(t *testing.T) {
@@ -276,7 +286,8 @@ func TestUnpackFeatureService(t *testing.T) {
 	fvs, odfvs, err := GetFeatureViewsToUseByService(
 		fs,
 		map[string]*model.FeatureView{"viewA": viewA, "viewB": viewB, "viewC": viewC},
-		map[string]*model.OnDemandFeatureView{"odfv": onDemandView})
+		map[string]*model.OnDemandFeatureView{"odfv": onDemandView},
+		false)
 
 	assertCorrectUnpacking(t, fvs, odfvs, err)
 }
@@ -328,7 +339,116 @@ func TestUnpackFeatureViewsByReferences(t *testing.T) {
 			"odfv:featG",
 		},
 		map[string]*model.FeatureView{"viewA": viewA, "viewB": viewB, "viewC": viewC},
-		map[string]*model.OnDemandFeatureView{"odfv": onDemandView})
+		map[string]*model.OnDemandFeatureView{"odfv": onDemandView},
+		false)
 
 	assertCorrectUnpacking(t, fvs, odfvs, err)
 }
+
+func TestUnpackWrittenOnDemandFeatureViews(t *testing.T) {
+	featCSpec := createFeature("featC", types.ValueType_INT32)
+	onDemandFeature1 := createFeature("featF", types.ValueType_FLOAT)
+	onDemandFeature2 := createFeature("featG", types.ValueType_FLOAT)
+
+	viewB := createFeatureView("viewB", []string{"entity"}, featCSpec)
+	onDemandView := createOnDemandFeatureView(
+		"odfv",
+		map[string][]*core.FeatureSpecV2{"viewB": {featCSpec}},
+		onDemandFeature1, onDemandFeature2)
+	onDemandView.WriteToOnlineStore = true
+	onDemandView.EntityNames = []string{"entity"}
+	featureViews := map[string]*model.FeatureView{"viewB": viewB}
+	onDemandFeatureViews := map[string]*model.OnDemandFeatureView{"odfv": onDemandView}
+
+	// The written view is read from the online store without its sources
+	fvs, odfvs, err := GetFeatureViewsToUseByFeatureRefs([]string{"odfv:featG"}, featureViews, onDemandFeatureViews, false)
+	assert.Nil(t, err)
+	assert.Empty(t, odfvs)
+	assert.Len(t, fvs, 1)
+	assert.Equal(t, "odfv", fvs[0].View.Base.Name)
+	assert.Equal(t, []string{"entity"}, fvs[0].View.EntityNames)
+	assert.Equal(t, []string{"featG"}, fvs[0].FeatureRefs)
+
+	fs := createFeatureService(map[string][]*core.FeatureSpecV2{"odfv": {onDemandFeature2}})
+	fvs, odfvs, err = GetFeatureViewsToUseByService(fs, featureViews, onDemandFeatureViews, false)
+	assert.Nil(t, err)
+	assert.Empty(t, odfvs)
+	assert.Len(t, fvs, 1)
+	assert.Equal(t, []string{"featG"}, fvs[0].FeatureRefs)
+
+	// With the transformation fallback the view is also transformed, which needs its sources
+	fvs, odfvs, err = GetFeatureViewsToUseByFeatureRefs([]string{"odfv:featG"}, featureViews, onDemandFeatureViews, true)
+	assert.Nil(t, err)
+	assert.Len(t, odfvs, 1)
+	assert.Len(t, fvs, 2)
+
+	fvs, odfvs, err = GetFeatureViewsToUseByService(fs, featureViews, onDemandFeatureViews, true)
+	assert.Nil(t, err)
+	assert.Len(t, odfvs, 1)
+	assert.Len(t, fvs, 2)
+}
+
+func TestTransposeFeatureRowsIntoColumnsCountsStatuses(t *testing.T) {
+	view := &model.FeatureView{Base: &model.BaseFeatureView{Name: "statusView"}, Ttl: &durationpb.Duration{Seconds: 3600}}
+	groupRef := &GroupedFeaturesPerEntitySet{
+		FeatureNames:        []string{"featA"},
+		FeatureViewNames:    []string{"statusView"},
+		AliasedFeatureNames: []string{"statusView__featA"},
+		// The first entity was requested twice
+		Indices: [][]int{{0, 1}, {2}, {3}},
+	}
+	featureData := [][]onlinestore.FeatureData{
+		{{Reference: serving.FeatureReferenceV2{FeatureViewName: "statusView", FeatureName: "featA"}, Timestamp: timestamp.Timestamp{Seconds: time.Now().Unix()}, Value: types.Value{Val: &types.Value_Int32Val{Int32Val: 1}}}},
+		{{Reference: serving.FeatureReferenceV2{FeatureViewName: "statusView", FeatureName: "featA"}, Timestamp: timestamp.Timestamp{Seconds: time.Now().Add(-2 * time.Hour).Unix()}, Value: types.Value{Val: &types.Value_Int32Val{Int32Val: 2}}}},
+		nil,
+	}
+
+	present := testutil.ToFloat64(metrics.FeatureStatuses.WithLabelValues("statusView", "PRESENT"))
+	outsideMaxAge := testutil.ToFloat64(metrics.FeatureStatuses.WithLabelValues("statusView", "OUTSIDE_MAX_AGE"))
+	notFound := testutil.ToFloat64(metrics.FeatureStatuses.WithLabelValues("statusView", "NOT_FOUND"))
+
+	vectors, err := TransposeFeatureRowsIntoColumns(featureData, groupRef, []*FeatureViewAndRefs{{View: view}}, memory.NewGoAllocator(), 4)
+	require.Nil(t, err)
+	require.Len(t, vectors, 1)
+	assert.Equal(t, []serving.FieldStatus{serving.FieldStatus_PRESENT, serving.FieldStatus_PRESENT, serving.FieldStatus_OUTSIDE_MAX_AGE, serving.FieldStatus_NOT_FOUND}, vectors[0].Statuses)
+
+	assert.Equal(t, present+2, testutil.ToFloat64(metrics.FeatureStatuses.WithLabelValues("statusView", "PRESENT")))
+	assert.Equal(t, outsideMaxAge+1, testutil.ToFloat64(metrics.FeatureStatuses.WithLabelValues("statusView", "OUTSIDE_MAX_AGE")))
+	assert.Equal(t, notFound+1, testutil.ToFloat64(metrics.FeatureStatuses.WithLabelValues("statusView", "NOT_FOUND")))
+}
+
+func TestFillMissingValues(t *testing.T) {
+	read, err := gotypes.ProtoValuesToArrowArray([]*types.Value{{Val: &types.Value_DoubleVal{DoubleVal: 1}}, nil, nil}, memory.DefaultAllocator, 3)
+	require.Nil(t, err)
+	readTimestamp := timestamppb.New(time.Now().Add(-time.Hour))
+	vector := &FeatureVector{
+		Name:       "featG",
+		Values:     read,
+		Statuses:   []serving.FieldStatus{serving.FieldStatus_PRESENT, serving.FieldStatus_NOT_FOUND, serving.FieldStatus_NOT_FOUND},
+		Timestamps: []*timestamppb.Timestamp{readTimestamp, {}, {}},
+	}
+	transformed, err := gotypes.ProtoValuesToArrowArray([]*types.Value{{Val: &types.Value_DoubleVal{DoubleVal: 10}}, {Val: &types.Value_DoubleVal{DoubleVal: 20}}, {Val: &types.Value_DoubleVal{DoubleVal: 30}}}, memory.DefaultAllocator, 3)
+	require.Nil(t, err)
+	now := timestamppb.Now()
+	fallback := &FeatureVector{
+		Name:       "featG",
+		Values:     transformed,
+		Statuses:   []serving.FieldStatus{serving.FieldStatus_PRESENT, serving.FieldStatus_PRESENT, serving.FieldStatus_PRESENT},
+		Timestamps: []*timestamppb.Timestamp{now, now, now},
+	}
+
+	require.Nil(t, FillMissingValues(vector, fallback, memory.DefaultAllocator, 3))
+	assert.Equal(t, []float64{1, 20, 30}, vector.Values.(*array.Float64).Float64Values())
+	assert.Equal(t, []serving.FieldStatus{serving.FieldStatus_PRESENT, serving.FieldStatus_PRESENT, serving.FieldStatus_PRESENT}, vector.Statuses)
+	assert.Equal(t, []*timestamppb.Timestamp{readTimestamp, now, now}, vector.Timestamps)
+
+	// Values that weren't found for any row are read as a null array
+	vector = &FeatureVector{
+		Name:       "featG",
+		Values:     array.NewNull(3),
+		Statuses:   []serving.FieldStatus{serving.FieldStatus_NOT_FOUND, serving.FieldStatus_NOT_FOUND, serving.FieldStatus_NOT_FOUND},
+		Timestamps: []*timestamppb.Timestamp{{}, {}, {}},
+	}
+	require.Nil(t, FillMissingValues(vector, fallback, memory.DefaultAllocator, 3))
+	assert.Equal(t, []float64{10, 20, 30}, vector.Values.(*array.Float64).Float64Values())
+}
@@ -0,0 +1,174 @@
+package onlineserving
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/internal/feast/onlinestore"
+	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
+	"github.com/feast-dev/feast/go/types"
+)
+
+// ErrInvalidFeatureData is wrapped by the errors GetFeatureRowsToWrite returns for data that doesn't match the
+// feature view, so that callers can tell bad input apart from other failures.
+var ErrInvalidFeatureData = errors.New("invalid feature data")
+
+func invalidFeatureData(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidFeatureData, fmt.Sprintf(format, args...))
+}
+
+/*
+GetFeatureRowsToWrite converts columnar data (as received by the push and write-to-online-store endpoints) into
+rows that can be passed to OnlineWrite. Columns are matched by name against the view's join keys, features and
+timestamp fields; other columns are ignored. Values are converted into the declared types of the view's fields,
+so numbers decoded from JSON and strings received through gRPC are stored the same way the Python SDK stores them.
+Rows without an event timestamp get the given current time.
+*/
+func GetFeatureRowsToWrite(
+	featureView *model.FeatureView,
+	entities []*model.Entity,
+	columns map[string]*prototypes.RepeatedValue,
+	now time.Time) ([]*onlinestore.FeatureRow, error) {
+
+	numRows := -1
+	for name, column := range columns {
+		if numRows < 0 {
+			numRows = len(column.Val)
+		} else if len(column.Val) != numRows {
+			return nil, invalidFeatureData("column %s has %d values, but expected %d", name, len(column.Val), numRows)
+		}
+	}
+	if numRows < 0 {
+		return nil, invalidFeatureData("no data provided for feature view %s", featureView.Base.Name)
+	}
+
+	joinKeys, err := getJoinKeyFields(featureView, entities)
+	if err != nil {
+		return nil, err
+	}
+	joinKeyNames := make([]string, len(joinKeys))
+	joinKeyColumns := make([][]*prototypes.Value, len(joinKeys))
+	for idx, joinKey := range joinKeys {
+		joinKeyNames[idx] = joinKey.Name
+		if featureView.HasEntity(model.DUMMY_ENTITY_NAME) {
+			joinKeyColumns[idx] = make([]*prototypes.Value, numRows)
+			for rowIdx := range joinKeyColumns[idx] {
+				joinKeyColumns[idx][rowIdx] = &model.DUMMY_ENTITY_VALUE
+			}
+			continue
+		}
+		column, ok := columns[joinKey.Name]
+		if !ok {
+			return nil, invalidFeatureData("missing join key %s of feature view %s", joinKey.Name, featureView.Base.Name)
+		}
+		if joinKeyColumns[idx], err = convertColumn(joinKey, column); err != nil {
+			return nil, err
+		}
+	}
+
+	featureColumns := make(map[string][]*prototypes.Value)
+	for _, feature := range featureView.Base.Features {
+		if column, ok := columns[feature.Name]; ok {
+			if featureColumns[feature.Name], err = convertColumn(feature, column); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(featureColumns) == 0 {
+		return nil, invalidFeatureData("no features of feature view %s provided", featureView.Base.Name)
+	}
+
+	eventTimestamps, err := getTimestampColumn(featureView.TimestampField, columns)
+	if err != nil {
+		return nil, err
+	}
+	createdTimestamps, err := getTimestampColumn(featureView.CreatedTimestampColumn, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*onlinestore.FeatureRow, numRows)
+	for rowIdx := 0; rowIdx < numRows; rowIdx++ {
+		entityValues := make([]*prototypes.Value, len(joinKeys))
+		for idx := range joinKeys {
+			entityValues[idx] = joinKeyColumns[idx][rowIdx]
+		}
+		values := make(map[string]*prototypes.Value, len(featureColumns))
+		for name, column := range featureColumns {
+			values[name] = column[rowIdx]
+		}
+		row := &onlinestore.FeatureRow{
+			EntityKey:      &prototypes.EntityKey{JoinKeys: joinKeyNames, EntityValues: entityValues},
+			Values:         values,
+			EventTimestamp: now,
+		}
+		if eventTimestamps != nil && eventTimestamps[rowIdx] != nil {
+			row.EventTimestamp = *eventTimestamps[rowIdx]
+		}
+		if createdTimestamps != nil && createdTimestamps[rowIdx] != nil {
+			row.CreatedTimestamp = *createdTimestamps[rowIdx]
+		}
+		rows[rowIdx] = row
+	}
+	return rows, nil
+}
+
+// getJoinKeyFields returns the join keys of a feature view. Views registered by older SDK versions have no entity
+// columns, in which case the join keys and value types of its entities are used.
+func getJoinKeyFields(featureView *model.FeatureView, entities []*model.Entity) ([]*model.Field, error) {
+	if featureView.HasEntity(model.DUMMY_ENTITY_NAME) {
+		return []*model.Field{{Name: model.DUMMY_ENTITY_ID, Dtype: prototypes.ValueType_STRING}}, nil
+	}
+	if len(featureView.EntityColumns) > 0 {
+		return featureView.EntityColumns, nil
+	}
+	entitiesByName := make(map[string]*model.Entity)
+	for _, entity := range entities {
+		entitiesByName[entity.Name] = entity
+	}
+	joinKeys := make([]*model.Field, len(featureView.EntityNames))
+	for idx, entityName := range featureView.EntityNames {
+		entity, ok := entitiesByName[entityName]
+		if !ok {
+			return nil, invalidFeatureData("entity %s of feature view %s not found", entityName, featureView.Base.Name)
+		}
+		joinKeys[idx] = &model.Field{Name: entity.JoinKey, Dtype: entity.ValueType}
+	}
+	return joinKeys, nil
+}
+
+func convertColumn(field *model.Field, column *prototypes.RepeatedValue) ([]*prototypes.Value, error) {
+	if field.Dtype == prototypes.ValueType_INVALID {
+		return column.Val, nil
+	}
+	values := make([]*prototypes.Value, len(column.Val))
+	for idx, value := range column.Val {
+		converted, err := types.ConvertProtoValue(value, field.Dtype)
+		if err != nil {
+			return nil, invalidFeatureData("invalid value for %s: %v", field.Name, err)
+		}
+		values[idx] = converted
+	}
+	return values, nil
+}
+
+func getTimestampColumn(name string, columns map[string]*prototypes.RepeatedValue) ([]*time.Time, error) {
+	column, ok := columns[name]
+	if name == "" || !ok {
+		return nil, nil
+	}
+	values, err := convertColumn(&model.Field{Name: name, Dtype: prototypes.ValueType_UNIX_TIMESTAMP}, column)
+	if err != nil {
+		return nil, err
+	}
+	timestamps := make([]*time.Time, len(values))
+	for idx, value := range values {
+		if seconds, ok := value.Val.(*prototypes.Value_UnixTimestampVal); ok {
+			timestamp := time.Unix(seconds.UnixTimestampVal, 0).UTC()
+			timestamps[idx] = &timestamp
+		}
+	}
+	return timestamps, nil
+}
@@ -0,0 +1,104 @@
+package onlineserving
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+func TestGetFeatureRowsToWrite(t *testing.T) {
+	view := &model.FeatureView{
+		Base: &model.BaseFeatureView{
+			Name: "driver_stats",
+			Features: []*model.Field{
+				{Name: "conv_rate", Dtype: types.ValueType_FLOAT},
+				{Name: "trips", Dtype: types.ValueType_INT32},
+			},
+		},
+		EntityNames:    []string{"driver"},
+		EntityColumns:  []*model.Field{{Name: "driver_id", Dtype: types.ValueType_INT64}},
+		TimestampField: "event_timestamp",
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := map[string]*types.RepeatedValue{
+		"driver_id": {Val: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: 1001}}, {Val: &types.Value_StringVal{StringVal: "1002"}}}},
+		"conv_rate": {Val: []*types.Value{{Val: &types.Value_DoubleVal{DoubleVal: 0.5}}, {Val: &types.Value_StringVal{StringVal: "0.25"}}}},
+		"trips":     {Val: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: 3}}, {}}},
+		"event_timestamp": {Val: []*types.Value{
+			{Val: &types.Value_StringVal{StringVal: "2024-01-02T03:04:05Z"}},
+			{Val: &types.Value_NullVal{NullVal: types.Null_NULL}},
+		}},
+		"unrelated": {Val: []*types.Value{{}, {}}},
+	}
+
+	rows, err := GetFeatureRowsToWrite(view, nil, columns, now)
+	require.Nil(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, []string{"driver_id"}, rows[0].EntityKey.JoinKeys)
+	assert.Equal(t, int64(1001), rows[0].EntityKey.EntityValues[0].GetInt64Val())
+	assert.Equal(t, int64(1002), rows[1].EntityKey.EntityValues[0].GetInt64Val())
+	assert.Equal(t, float32(0.5), rows[0].Values["conv_rate"].GetFloatVal())
+	assert.Equal(t, float32(0.25), rows[1].Values["conv_rate"].GetFloatVal())
+	assert.Equal(t, int32(3), rows[0].Values["trips"].GetInt32Val())
+	assert.Nil(t, rows[1].Values["trips"].Val)
+	assert.Len(t, rows[0].Values, 2)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), rows[0].EventTimestamp)
+	assert.Equal(t, now, rows[1].EventTimestamp)
+	assert.True(t, rows[0].CreatedTimestamp.IsZero())
+}
+
+func TestGetFeatureRowsToWriteUsesEntityJoinKeys(t *testing.T) {
+	view := &model.FeatureView{
+		Base:        &model.BaseFeatureView{Name: "driver_stats", Features: []*model.Field{{Name: "conv_rate", Dtype: types.ValueType_FLOAT}}},
+		EntityNames: []string{"driver"},
+	}
+	entities := []*model.Entity{{Name: "driver", JoinKey: "driver_id", ValueType: types.ValueType_INT64}}
+	columns := map[string]*types.RepeatedValue{
+		"driver_id": {Val: []*types.Value{{Val: &types.Value_StringVal{StringVal: "1001"}}}},
+		"conv_rate": {Val: []*types.Value{{Val: &types.Value_DoubleVal{DoubleVal: 0.5}}}},
+	}
+	rows, err := GetFeatureRowsToWrite(view, entities, columns, time.Now())
+	require.Nil(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, []string{"driver_id"}, rows[0].EntityKey.JoinKeys)
+	assert.Equal(t, int64(1001), rows[0].EntityKey.EntityValues[0].GetInt64Val())
+
+	delete(columns, "driver_id")
+	_, err = GetFeatureRowsToWrite(view, entities, columns, time.Now())
+	assert.NotNil(t, err)
+}
+
+func TestGetFeatureRowsToWriteWithoutEntities(t *testing.T) {
+	view := &model.FeatureView{
+		Base:        &model.BaseFeatureView{Name: "global_stats", Features: []*model.Field{{Name: "total", Dtype: types.ValueType_INT64}}},
+		EntityNames: []string{model.DUMMY_ENTITY_NAME},
+	}
+	columns := map[string]*types.RepeatedValue{
+		"total": {Val: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: 10}}}},
+	}
+	rows, err := GetFeatureRowsToWrite(view, nil, columns, time.Now())
+	require.Nil(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, []string{model.DUMMY_ENTITY_ID}, rows[0].EntityKey.JoinKeys)
+	assert.Equal(t, model.DUMMY_ENTITY_VAL, rows[0].EntityKey.EntityValues[0].GetStringVal())
+}
+
+func TestGetFeatureRowsToWriteWithMismatchedColumns(t *testing.T) {
+	view := &model.FeatureView{
+		Base:          &model.BaseFeatureView{Name: "driver_stats", Features: []*model.Field{{Name: "conv_rate", Dtype: types.ValueType_FLOAT}}},
+		EntityNames:   []string{"driver"},
+		EntityColumns: []*model.Field{{Name: "driver_id", Dtype: types.ValueType_INT64}},
+	}
+	columns := map[string]*types.RepeatedValue{
+		"driver_id": {Val: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: 1001}}}},
+		"conv_rate": {Val: []*types.Value{{Val: &types.Value_DoubleVal{DoubleVal: 0.5}}, {Val: &types.Value_DoubleVal{DoubleVal: 0.5}}}},
+	}
+	_, err := GetFeatureRowsToWrite(view, nil, columns, time.Now())
+	assert.NotNil(t, err)
+}
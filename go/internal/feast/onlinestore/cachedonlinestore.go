@@ -0,0 +1,405 @@
+package onlinestore
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+const (
+	defaultCacheTtl        = time.Minute
+	defaultCacheMaxEntries = 100000
+	// Estimated memory used by an entry besides its key and values
+	cacheEntryOverheadBytes = 200
+)
+
+// CacheConfig configures the cache in front of the online store, set by online_store_cache in the
+// feature_server section.
+type CacheConfig struct {
+	// How long values are cached, unless the feature view's TTL expires them earlier (ttl_seconds)
+	Ttl time.Duration
+	// Cache TTLs of feature views that override Ttl (feature_view_ttl_seconds)
+	FeatureViewTtls map[string]time.Duration
+	// Maximum number of cached entity rows of feature views (max_entries)
+	MaxEntries int
+	// Maximum estimated size of the cached values, unlimited if 0 (max_size_bytes)
+	MaxSizeBytes int64
+}
+
+// GetCacheConfig returns the configuration of the online store cache, or nil if the cache isn't enabled.
+func GetCacheConfig(config *registry.RepoConfig) (*CacheConfig, error) {
+	v, ok := config.FeatureServer["online_store_cache"]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	options, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T for feature_server online_store_cache", v)
+	}
+	if enabled, ok := options["enabled"]; ok && enabled != true {
+		return nil, nil
+	}
+	cacheConfig := CacheConfig{Ttl: defaultCacheTtl, MaxEntries: defaultCacheMaxEntries, FeatureViewTtls: make(map[string]time.Duration)}
+	for key, value := range options {
+		switch key {
+		case "ttl_seconds":
+			seconds, err := getIntConfigValue(key, value)
+			if err != nil {
+				return nil, err
+			}
+			cacheConfig.Ttl = time.Duration(seconds) * time.Second
+		case "max_entries":
+			maxEntries, err := getIntConfigValue(key, value)
+			if err != nil {
+				return nil, err
+			}
+			cacheConfig.MaxEntries = int(maxEntries)
+		case "max_size_bytes":
+			maxSizeBytes, err := getIntConfigValue(key, value)
+			if err != nil {
+				return nil, err
+			}
+			cacheConfig.MaxSizeBytes = maxSizeBytes
+		case "feature_view_ttl_seconds":
+			featureViewTtls, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("failed to convert feature_view_ttl_seconds to map: %+v", value)
+			}
+			for featureViewName, ttl := range featureViewTtls {
+				seconds, err := getIntConfigValue(featureViewName, ttl)
+				if err != nil {
+					return nil, err
+				}
+				cacheConfig.FeatureViewTtls[featureViewName] = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	if cacheConfig.MaxEntries < 1 {
+		return nil, fmt.Errorf("feature_server online_store_cache max_entries must be at least 1, got %d", cacheConfig.MaxEntries)
+	}
+	return &cacheConfig, nil
+}
+
+// CacheStats are the counters of a CachedOnlineStore. Hits and misses count the entity rows of reads.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+	SizeBytes int64
+}
+
+// FeatureViewTtlFunc returns the TTL of a feature view, or false if the feature view doesn't exist.
+type FeatureViewTtlFunc func(featureViewName string) (time.Duration, bool)
+
+type cachedFeature struct {
+	data      *FeatureData
+	expiresAt time.Time
+}
+
+// A cacheEntry holds the values of one entity in one feature view that were read so far.
+type cacheEntry struct {
+	key       string
+	features  map[string]cachedFeature
+	sizeBytes int64
+}
+
+// CachedOnlineStore is a read-through cache in front of an online store. Values are cached per entity and feature
+// view, for the cache TTL of the view, and are never returned once the view's TTL marks them outside max age.
+// Entities that the online store doesn't have are not cached. The least recently used entries are evicted to stay
+// within the size limits.
+type CachedOnlineStore struct {
+	store          OnlineStore
+	config         *CacheConfig
+	featureViewTtl FeatureViewTtlFunc
+	// Returns the current time, replaced in tests
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*list.Element
+	lru       *list.List
+	sizeBytes int64
+	hits      int64
+	misses    int64
+	evictions int64
+	// Keys written while reads of the online store are in flight, with the sequence number of their last write,
+	// so that those reads don't cache values that the writes replaced. Cleared when no reads are in flight.
+	writeSeq     int64
+	pendingReads int
+	writtenKeys  map[string]int64
+
+	entityKeySerializationVersion int64
+}
+
+// NewCachedOnlineStore wraps the online store in a cache. featureViewTtl bounds how long values are served.
+func NewCachedOnlineStore(store OnlineStore, repoConfig *registry.RepoConfig, config *CacheConfig, featureViewTtl FeatureViewTtlFunc) *CachedOnlineStore {
+	return &CachedOnlineStore{
+		store:                         store,
+		config:                        config,
+		featureViewTtl:                featureViewTtl,
+		now:                           time.Now,
+		entries:                       make(map[string]*list.Element),
+		lru:                           list.New(),
+		writtenKeys:                   make(map[string]int64),
+		entityKeySerializationVersion: repoConfig.EntityKeySerializationVersion,
+	}
+}
+
+func cacheKey(featureViewName string, serializedEntityKey []byte) string {
+	return featureViewName + "\x00" + string(serializedEntityKey)
+}
+
+// OnlineRead returns the rows of the entities whose requested features are all cached, and reads the other
+// entities from the online store with a single read.
+func (c *CachedOnlineStore) OnlineRead(ctx context.Context, entityKeys []*types.EntityKey, featureViewNames []string, featureNames []string) ([][]FeatureData, error) {
+	viewToFeatureIndices := make(map[string][]int)
+	viewOrder := make([]string, 0)
+	for i, featureViewName := range featureViewNames {
+		if _, ok := viewToFeatureIndices[featureViewName]; !ok {
+			viewOrder = append(viewOrder, featureViewName)
+		}
+		viewToFeatureIndices[featureViewName] = append(viewToFeatureIndices[featureViewName], i)
+	}
+
+	serializedKeys := make([][]byte, len(entityKeys))
+	for i, entityKey := range entityKeys {
+		serializedKey, err := serializeEntityKey(entityKey, c.entityKeySerializationVersion)
+		if err != nil {
+			return nil, err
+		}
+		serializedKeys[i] = *serializedKey
+	}
+
+	// A view's TTL is resolved once per read, views that aren't in the registry are never cached
+	viewTtls := make(map[string]time.Duration, len(viewOrder))
+	for _, featureViewName := range viewOrder {
+		if viewTtl, ok := c.featureViewTtl(featureViewName); ok {
+			viewTtls[featureViewName] = viewTtl
+		}
+	}
+
+	now := c.now()
+	results := make([][]FeatureData, len(entityKeys))
+	missedIndices := make([]int, 0)
+	c.mu.Lock()
+	for i, serializedKey := range serializedKeys {
+		row := make([]FeatureData, len(featureNames))
+		hit := true
+		for _, featureViewName := range viewOrder {
+			viewTtl, ok := viewTtls[featureViewName]
+			if !ok || !c.getLocked(cacheKey(featureViewName, serializedKey), viewTtl, viewToFeatureIndices[featureViewName], featureNames, row, now) {
+				hit = false
+				break
+			}
+		}
+		if hit {
+			results[i] = row
+		} else {
+			missedIndices = append(missedIndices, i)
+		}
+	}
+	hits := len(entityKeys) - len(missedIndices)
+	c.hits += int64(hits)
+	c.misses += int64(len(missedIndices))
+	readSeq := c.writeSeq
+	if len(missedIndices) > 0 {
+		c.pendingReads++
+	}
+	c.mu.Unlock()
+	metrics.OnlineStoreCacheLookups.WithLabelValues("hit").Add(float64(hits))
+	metrics.OnlineStoreCacheLookups.WithLabelValues("miss").Add(float64(len(missedIndices)))
+	if len(missedIndices) == 0 {
+		return results, nil
+	}
+
+	missedEntityKeys := make([]*types.EntityKey, len(missedIndices))
+	for i, entityIndex := range missedIndices {
+		missedEntityKeys[i] = entityKeys[entityIndex]
+	}
+	missedResults, err := c.store.OnlineRead(ctx, missedEntityKeys, featureViewNames, featureNames)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.endReadLocked()
+	if err != nil {
+		return nil, err
+	}
+	for i, entityIndex := range missedIndices {
+		results[entityIndex] = missedResults[i]
+		if missedResults[i] == nil {
+			continue
+		}
+		for _, featureViewName := range viewOrder {
+			key := cacheKey(featureViewName, serializedKeys[entityIndex])
+			// The values may predate a write that happened during the read
+			if c.writtenKeys[key] > readSeq {
+				continue
+			}
+			if viewTtl, ok := viewTtls[featureViewName]; ok {
+				c.putLocked(key, featureViewName, viewTtl, viewToFeatureIndices[featureViewName], featureNames, missedResults[i], now)
+			}
+		}
+	}
+	return results, nil
+}
+
+// endReadLocked ends a read of the online store, and forgets the written keys once no reads are in flight.
+func (c *CachedOnlineStore) endReadLocked() {
+	c.pendingReads--
+	if c.pendingReads == 0 && len(c.writtenKeys) > 0 {
+		c.writtenKeys = make(map[string]int64)
+	}
+}
+
+// getLocked copies the cached features of a feature view into the row, if all of them are cached and not expired.
+func (c *CachedOnlineStore) getLocked(key string, viewTtl time.Duration, featureIndices []int, featureNames []string, row []FeatureData, now time.Time) bool {
+	element, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	entry := element.Value.(*cacheEntry)
+	for _, featureIndex := range featureIndices {
+		feature, ok := entry.features[featureNames[featureIndex]]
+		if !ok || !now.Before(feature.expiresAt) || outsideTtl(feature.data, viewTtl, now) {
+			return false
+		}
+		row[featureIndex] = copyFeatureData(feature.data)
+	}
+	c.lru.MoveToFront(element)
+	return true
+}
+
+// outsideTtl reports whether the serving layer would mark the value outside max age at the given time. Missing
+// values are never outside max age.
+func outsideTtl(data *FeatureData, viewTtl time.Duration, now time.Time) bool {
+	if _, ok := data.Value.Val.(*types.Value_NullVal); ok {
+		return false
+	}
+	ttlSeconds := int64(viewTtl / time.Second)
+	if ttlSeconds == 0 {
+		return false
+	}
+	return now.Unix()-data.Timestamp.GetSeconds() > ttlSeconds
+}
+
+// putLocked caches the features of a feature view read from the online store. Values that are already outside
+// the view's TTL aren't cached, and cached values expire no later than the view's TTL.
+func (c *CachedOnlineStore) putLocked(key string, featureViewName string, viewTtl time.Duration, featureIndices []int, featureNames []string, row []FeatureData, now time.Time) {
+	ttl := c.config.Ttl
+	if featureViewCacheTtl, ok := c.config.FeatureViewTtls[featureViewName]; ok {
+		ttl = featureViewCacheTtl
+	}
+	if viewTtl > 0 && viewTtl < ttl {
+		ttl = viewTtl
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	var entry *cacheEntry
+	if element, ok := c.entries[key]; ok {
+		entry = element.Value.(*cacheEntry)
+		c.lru.MoveToFront(element)
+	} else {
+		entry = &cacheEntry{key: key, features: make(map[string]cachedFeature), sizeBytes: int64(len(key)) + cacheEntryOverheadBytes}
+		c.entries[key] = c.lru.PushFront(entry)
+		c.sizeBytes += entry.sizeBytes
+	}
+	for _, featureIndex := range featureIndices {
+		data := &row[featureIndex]
+		if outsideTtl(data, viewTtl, now) {
+			continue
+		}
+		featureName := featureNames[featureIndex]
+		if previous, ok := entry.features[featureName]; ok {
+			entry.sizeBytes -= cachedFeatureSize(featureName, previous.data)
+			c.sizeBytes -= cachedFeatureSize(featureName, previous.data)
+		}
+		cached := copyFeatureData(data)
+		entry.features[featureName] = cachedFeature{data: &cached, expiresAt: now.Add(ttl)}
+		entry.sizeBytes += cachedFeatureSize(featureName, data)
+		c.sizeBytes += cachedFeatureSize(featureName, data)
+	}
+	if len(entry.features) == 0 {
+		c.removeLocked(c.entries[key])
+		return
+	}
+	c.evictLocked()
+}
+
+func cachedFeatureSize(featureName string, data *FeatureData) int64 {
+	return int64(len(featureName) + len(data.Reference.FeatureViewName) + proto.Size(&data.Value))
+}
+
+// copyFeatureData copies a value into or out of the cache, so that cached values don't share the rows of reads.
+func copyFeatureData(data *FeatureData) FeatureData {
+	return FeatureData{
+		Reference: serving.FeatureReferenceV2{FeatureViewName: data.Reference.FeatureViewName, FeatureName: data.Reference.FeatureName},
+		Timestamp: timestamp.Timestamp{Seconds: data.Timestamp.Seconds, Nanos: data.Timestamp.Nanos},
+		Value:     types.Value{Val: data.Value.Val},
+	}
+}
+
+// evictLocked removes the least recently used entries until the cache is within its size limits.
+func (c *CachedOnlineStore) evictLocked() {
+	for c.lru.Len() > c.config.MaxEntries || (c.config.MaxSizeBytes > 0 && c.sizeBytes > c.config.MaxSizeBytes && c.lru.Len() > 0) {
+		c.removeLocked(c.lru.Back())
+		c.evictions++
+	}
+}
+
+func (c *CachedOnlineStore) removeLocked(element *list.Element) {
+	entry := c.lru.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.sizeBytes -= entry.sizeBytes
+}
+
+// OnlineWrite writes the rows to the online store and drops the cached values of their entities. Reads of the
+// online store that are in flight don't cache the values of the written entities.
+func (c *CachedOnlineStore) OnlineWrite(ctx context.Context, featureViewName string, rows []*FeatureRow) error {
+	err := c.store.OnlineWrite(ctx, featureViewName, rows)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeSeq++
+	for _, row := range rows {
+		serializedKey, serializeErr := serializeEntityKey(row.EntityKey, c.entityKeySerializationVersion)
+		if serializeErr != nil {
+			continue
+		}
+		key := cacheKey(featureViewName, *serializedKey)
+		if element, ok := c.entries[key]; ok {
+			c.removeLocked(element)
+		}
+		if c.pendingReads > 0 {
+			c.writtenKeys[key] = c.writeSeq
+		}
+	}
+	return err
+}
+
+// Stats returns the counters of the cache.
+func (c *CachedOnlineStore) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   c.lru.Len(),
+		SizeBytes: c.sizeBytes,
+	}
+}
+
+func (c *CachedOnlineStore) Destruct() {
+	c.store.Destruct()
+}
@@ -0,0 +1,234 @@
+package onlinestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+// countingStore returns the driver id times 10 for every feature of the drivers it has, with the event timestamp
+// eventTs, and counts the entity keys read. afterRead, if set, is called before a read returns.
+type countingStore struct {
+	eventTs    time.Time
+	drivers    map[int64]bool
+	readKeys   int
+	writeCalls int
+	afterRead  func()
+}
+
+func (s *countingStore) OnlineRead(ctx context.Context, entityKeys []*types.EntityKey, featureViewNames []string, featureNames []string) ([][]FeatureData, error) {
+	s.readKeys += len(entityKeys)
+	results := make([][]FeatureData, len(entityKeys))
+	for i, entityKey := range entityKeys {
+		driverId := entityKey.EntityValues[0].GetInt64Val()
+		if !s.drivers[driverId] {
+			continue
+		}
+		results[i] = make([]FeatureData, len(featureNames))
+		for j := range featureNames {
+			results[i][j] = FeatureData{
+				Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewNames[j], FeatureName: featureNames[j]},
+				Timestamp: timestamp.Timestamp{Seconds: s.eventTs.Unix()},
+				Value:     types.Value{Val: &types.Value_Int64Val{Int64Val: driverId * 10}},
+			}
+		}
+	}
+	if s.afterRead != nil {
+		s.afterRead()
+	}
+	return results, nil
+}
+
+func (s *countingStore) OnlineWrite(ctx context.Context, featureViewName string, rows []*FeatureRow) error {
+	s.writeCalls++
+	return nil
+}
+
+func (s *countingStore) Destruct() {}
+
+func newCachedStoreForTest(config *CacheConfig, viewTtls map[string]time.Duration, now time.Time, drivers ...int64) (*CachedOnlineStore, *countingStore, *time.Time) {
+	store := &countingStore{eventTs: now, drivers: make(map[int64]bool)}
+	for _, driverId := range drivers {
+		store.drivers[driverId] = true
+	}
+	cached := NewCachedOnlineStore(store, &registry.RepoConfig{EntityKeySerializationVersion: 2}, config, func(featureViewName string) (time.Duration, bool) {
+		ttl, ok := viewTtls[featureViewName]
+		return ttl, ok
+	})
+	clock := now
+	cached.now = func() time.Time { return clock }
+	return cached, store, &clock
+}
+
+func readTrips(t *testing.T, store OnlineStore, driverIds ...int64) []int64 {
+	results, err := store.OnlineRead(context.Background(), driverEntityKeys(driverIds...), []string{"driver_stats"}, []string{"trips"})
+	require.Nil(t, err)
+	trips := make([]int64, len(results))
+	for i, row := range results {
+		if row != nil {
+			trips[i] = row[0].Value.GetInt64Val()
+		}
+	}
+	return trips
+}
+
+func TestCachedOnlineStoreServesHits(t *testing.T) {
+	now := time.Unix(1704164645, 0)
+	cached, store, _ := newCachedStoreForTest(&CacheConfig{Ttl: time.Minute, MaxEntries: 10}, map[string]time.Duration{"driver_stats": time.Hour}, now, 1, 2)
+
+	assert.Equal(t, []int64{10, 20, 0}, readTrips(t, cached, 1, 2, 3))
+	assert.Equal(t, 3, store.readKeys)
+	assert.Equal(t, []int64{20, 10, 0}, readTrips(t, cached, 2, 1, 3))
+	// Only the driver that the online store doesn't have is read again
+	assert.Equal(t, 4, store.readKeys)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 4, Entries: 2, SizeBytes: cached.Stats().SizeBytes}, cached.Stats())
+
+	// Features that weren't read before are read from the online store
+	results, err := cached.OnlineRead(context.Background(), driverEntityKeys(1), []string{"driver_stats", "driver_stats"}, []string{"trips", "rating"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(10), results[0][1].Value.GetInt64Val())
+	assert.Equal(t, 5, store.readKeys)
+}
+
+func TestCachedOnlineStoreExpiresValues(t *testing.T) {
+	now := time.Unix(1704164645, 0)
+	viewTtls := map[string]time.Duration{"driver_stats": time.Hour, "fast_stats": 10 * time.Second, "unknown": 0}
+	config := &CacheConfig{Ttl: time.Minute, MaxEntries: 10, FeatureViewTtls: map[string]time.Duration{"driver_stats": 30 * time.Second}}
+	cached, store, clock := newCachedStoreForTest(config, viewTtls, now, 1)
+
+	readTrips(t, cached, 1)
+	*clock = now.Add(29 * time.Second)
+	readTrips(t, cached, 1)
+	assert.Equal(t, 1, store.readKeys)
+	// The cache TTL of the view has passed
+	*clock = now.Add(30 * time.Second)
+	readTrips(t, cached, 1)
+	assert.Equal(t, 2, store.readKeys)
+
+	// The view's TTL is shorter than the cache TTL and expires the values
+	fastStats := func() {
+		_, err := cached.OnlineRead(context.Background(), driverEntityKeys(1), []string{"fast_stats"}, []string{"trips"})
+		require.Nil(t, err)
+	}
+	store.eventTs = *clock
+	fastStats()
+	*clock = clock.Add(9 * time.Second)
+	fastStats()
+	assert.Equal(t, 3, store.readKeys)
+	*clock = clock.Add(time.Second)
+	fastStats()
+	assert.Equal(t, 4, store.readKeys)
+}
+
+func TestCachedOnlineStoreDoesNotServeValuesOutsideTtl(t *testing.T) {
+	now := time.Unix(1704164645, 0)
+	cached, store, clock := newCachedStoreForTest(&CacheConfig{Ttl: time.Hour, MaxEntries: 10}, map[string]time.Duration{"driver_stats": time.Minute}, now, 1)
+
+	// The value is cached for up to the cache TTL, but is only served until the view's TTL passes since its event
+	store.eventTs = now.Add(-50 * time.Second)
+	readTrips(t, cached, 1)
+	*clock = now.Add(10 * time.Second)
+	readTrips(t, cached, 1)
+	assert.Equal(t, 1, store.readKeys)
+	*clock = now.Add(11 * time.Second)
+	readTrips(t, cached, 1)
+	assert.Equal(t, 2, store.readKeys)
+
+	// Values that are already outside the view's TTL are not cached
+	readTrips(t, cached, 1)
+	assert.Equal(t, 3, store.readKeys)
+}
+
+func TestCachedOnlineStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Unix(1704164645, 0)
+	cached, store, _ := newCachedStoreForTest(&CacheConfig{Ttl: time.Minute, MaxEntries: 2}, map[string]time.Duration{"driver_stats": 0}, now, 1, 2, 3)
+
+	readTrips(t, cached, 1, 2)
+	readTrips(t, cached, 1)
+	readTrips(t, cached, 3)
+	assert.Equal(t, int64(1), cached.Stats().Evictions)
+	assert.Equal(t, 2, cached.Stats().Entries)
+	store.readKeys = 0
+	readTrips(t, cached, 1, 3)
+	assert.Equal(t, 0, store.readKeys)
+	readTrips(t, cached, 2)
+	assert.Equal(t, 1, store.readKeys)
+
+	sizeLimited, _, _ := newCachedStoreForTest(&CacheConfig{Ttl: time.Minute, MaxEntries: 10}, map[string]time.Duration{"driver_stats": 0}, now, 1, 2, 3)
+	readTrips(t, sizeLimited, 1)
+	sizeLimited.config.MaxSizeBytes = sizeLimited.Stats().SizeBytes * 2
+	readTrips(t, sizeLimited, 2, 3)
+	assert.Equal(t, 2, sizeLimited.Stats().Entries)
+	assert.LessOrEqual(t, sizeLimited.Stats().SizeBytes, sizeLimited.config.MaxSizeBytes)
+}
+
+func TestCachedOnlineStoreWriteDropsCachedValues(t *testing.T) {
+	now := time.Unix(1704164645, 0)
+	cached, store, _ := newCachedStoreForTest(&CacheConfig{Ttl: time.Minute, MaxEntries: 10}, map[string]time.Duration{"driver_stats": 0}, now, 1, 2)
+
+	readTrips(t, cached, 1, 2)
+	require.Nil(t, cached.OnlineWrite(context.Background(), "driver_stats", []*FeatureRow{{EntityKey: driverEntityKeys(1)[0]}}))
+	assert.Equal(t, 1, store.writeCalls)
+	readTrips(t, cached, 1, 2)
+	assert.Equal(t, 3, store.readKeys)
+}
+
+func TestCachedOnlineStoreDoesNotCacheReadsOverlappingWrites(t *testing.T) {
+	now := time.Unix(1704164645, 0)
+	cached, store, _ := newCachedStoreForTest(&CacheConfig{Ttl: time.Minute, MaxEntries: 10}, map[string]time.Duration{"driver_stats": 0}, now, 1, 2)
+
+	// Driver 1 is written after the online store returned its old values, but before they are cached
+	store.afterRead = func() {
+		store.afterRead = nil
+		require.Nil(t, cached.OnlineWrite(context.Background(), "driver_stats", []*FeatureRow{{EntityKey: driverEntityKeys(1)[0]}}))
+	}
+	assert.Equal(t, []int64{10, 20}, readTrips(t, cached, 1, 2))
+	assert.Equal(t, 1, cached.Stats().Entries)
+	assert.Empty(t, cached.writtenKeys)
+
+	readTrips(t, cached, 1, 2)
+	assert.Equal(t, 3, store.readKeys)
+	// Reads that start after the write cache its values
+	readTrips(t, cached, 1, 2)
+	assert.Equal(t, 3, store.readKeys)
+}
+
+func TestGetCacheConfig(t *testing.T) {
+	config, err := GetCacheConfig(&registry.RepoConfig{})
+	require.Nil(t, err)
+	assert.Nil(t, config)
+
+	config, err = GetCacheConfig(&registry.RepoConfig{FeatureServer: map[string]interface{}{
+		"online_store_cache": map[string]interface{}{"enabled": false},
+	}})
+	require.Nil(t, err)
+	assert.Nil(t, config)
+
+	repoConfig, err := registry.NewRepoConfigFromJSON(t.TempDir(), `{"feature_server": {"online_store_cache": {
+		"ttl_seconds": 5, "max_entries": 1000, "max_size_bytes": 1048576, "feature_view_ttl_seconds": {"driver_stats": 1}}}}`)
+	require.Nil(t, err)
+	config, err = GetCacheConfig(repoConfig)
+	require.Nil(t, err)
+	assert.Equal(t, &CacheConfig{
+		Ttl:             5 * time.Second,
+		FeatureViewTtls: map[string]time.Duration{"driver_stats": time.Second},
+		MaxEntries:      1000,
+		MaxSizeBytes:    1048576,
+	}, config)
+
+	config, err = GetCacheConfig(&registry.RepoConfig{FeatureServer: map[string]interface{}{"online_store_cache": map[string]interface{}{}}})
+	require.Nil(t, err)
+	assert.Equal(t, defaultCacheTtl, config.Ttl)
+	assert.Equal(t, defaultCacheMaxEntries, config.MaxEntries)
+
+	_, err = GetCacheConfig(&registry.RepoConfig{FeatureServer: map[string]interface{}{"online_store_cache": map[string]interface{}{"max_entries": 0}}})
+	assert.Error(t, err)
+}
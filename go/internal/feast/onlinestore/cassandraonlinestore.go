@@ -0,0 +1,331 @@
+package onlinestore
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+const (
+	defaultCassandraPort        = 9042
+	defaultCassandraKeyspace    = "feast_keyspace"
+	defaultCassandraConcurrency = 100
+	defaultCassandraLocalDC     = "datacenter1"
+	// The Python driver's default consistency level
+	defaultCassandraConsistency = gocql.LocalOne
+
+	cassandraSelectCql = `SELECT feature_name, value, event_ts FROM %s WHERE entity_key = ?`
+	cassandraInsertCql = `INSERT INTO %s (feature_name, value, entity_key, event_ts) VALUES (?, ?, ?, ?)`
+)
+
+type CassandraOnlineStore struct {
+	// Feast project name
+	project string
+
+	session *gocql.Session
+
+	// Keyspace of the feature view tables (keyspace)
+	keyspace string
+
+	// Number of entity rows read at the same time (read_concurrency)
+	readConcurrency int
+
+	// Number of feature rows written at the same time (write_concurrency)
+	writeConcurrency int
+
+	config *registry.RepoConfig
+}
+
+// NewCassandraOnlineStore creates an online store that reads the tables written by the Python CassandraOnlineStore
+// from Cassandra or ScyllaDB. Besides the keys of the Python store (hosts, port, keyspace, username, password,
+// protocol_version, request_timeout, load_balancing, read_concurrency and write_concurrency), onlineStoreConfig
+// accepts consistency, e.g. LOCAL_QUORUM. Queries are prepared once per host by the driver and routed to a
+// replica of the entity unless the load balancing policy is DCAwareRoundRobinPolicy.
+func NewCassandraOnlineStore(project string, config *registry.RepoConfig, onlineStoreConfig map[string]interface{}) (*CassandraOnlineStore, error) {
+	store := CassandraOnlineStore{project: project, config: config}
+	cluster, err := store.parseCassandraConfig(onlineStoreConfig)
+	if err != nil {
+		return nil, err
+	}
+	store.session, err = cluster.CreateSession()
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Using Cassandra: hosts %v, keyspace %s", cluster.Hosts, store.keyspace)
+	return &store, nil
+}
+
+// parseCassandraConfig sets the options of the store and returns the configuration of the cluster to connect to.
+func (c *CassandraOnlineStore) parseCassandraConfig(onlineStoreConfig map[string]interface{}) (*gocql.ClusterConfig, error) {
+	c.keyspace = defaultCassandraKeyspace
+	c.readConcurrency = defaultCassandraConcurrency
+	c.writeConcurrency = defaultCassandraConcurrency
+
+	if bundle, ok := onlineStoreConfig["secure_bundle_path"]; ok && bundle != nil {
+		return nil, errors.New("secure_bundle_path is not supported by the Go feature server, use hosts instead")
+	}
+	hosts, err := getStringListConfigValue("hosts", onlineStoreConfig["hosts"])
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) == 0 {
+		return nil, errors.New("hosts must be set in the Cassandra online store configuration")
+	}
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Port = defaultCassandraPort
+	cluster.Consistency = defaultCassandraConsistency
+
+	var username, password string
+	var loadBalancing map[string]interface{}
+	for key, value := range onlineStoreConfig {
+		if value == nil {
+			continue
+		}
+		switch key {
+		case "keyspace", "username", "password", "consistency":
+			stringValue, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("failed to convert %s to string: %+v", key, value)
+			}
+			switch key {
+			case "keyspace":
+				c.keyspace = stringValue
+			case "username":
+				username = stringValue
+			case "password":
+				password = stringValue
+			case "consistency":
+				consistency, err := gocql.ParseConsistencyWrapper(stringValue)
+				if err != nil {
+					return nil, err
+				}
+				cluster.Consistency = consistency
+			}
+		case "port", "protocol_version", "read_concurrency", "write_concurrency":
+			intValue, err := getIntConfigValue(key, value)
+			if err != nil {
+				return nil, err
+			}
+			switch key {
+			case "port":
+				cluster.Port = int(intValue)
+			case "protocol_version":
+				cluster.ProtoVersion = int(intValue)
+			case "read_concurrency", "write_concurrency":
+				if intValue < 1 {
+					return nil, fmt.Errorf("%s must be at least 1, got %d", key, intValue)
+				}
+				if key == "read_concurrency" {
+					c.readConcurrency = int(intValue)
+				} else {
+					c.writeConcurrency = int(intValue)
+				}
+			}
+		case "request_timeout":
+			seconds, ok := value.(float64)
+			if !ok {
+				intValue, err := getIntConfigValue(key, value)
+				if err != nil {
+					return nil, err
+				}
+				seconds = float64(intValue)
+			}
+			cluster.Timeout = time.Duration(seconds * float64(time.Second))
+		case "load_balancing":
+			mapValue, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("failed to convert load_balancing to map: %+v", value)
+			}
+			loadBalancing = mapValue
+		}
+	}
+	cluster.Keyspace = c.keyspace
+
+	if (username == "") != (password == "") {
+		return nil, errors.New("username and password must be set together in the Cassandra online store configuration")
+	}
+	if username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{Username: username, Password: password}
+	}
+
+	cluster.PoolConfig.HostSelectionPolicy, err = cassandraHostSelectionPolicy(loadBalancing)
+	if err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// cassandraHostSelectionPolicy returns the policy of the load_balancing configuration. Without one, queries are
+// routed to the replicas of the entity with round robin as the fallback.
+func cassandraHostSelectionPolicy(loadBalancing map[string]interface{}) (gocql.HostSelectionPolicy, error) {
+	if loadBalancing == nil {
+		return gocql.TokenAwareHostPolicy(gocql.RoundRobinHostPolicy()), nil
+	}
+	localDC := defaultCassandraLocalDC
+	if value, ok := loadBalancing["local_dc"].(string); ok {
+		localDC = value
+	}
+	switch policy := loadBalancing["load_balancing_policy"]; policy {
+	case "DCAwareRoundRobinPolicy":
+		return gocql.DCAwareRoundRobinPolicy(localDC), nil
+	case "TokenAwarePolicy(DCAwareRoundRobinPolicy)":
+		return gocql.TokenAwareHostPolicy(gocql.DCAwareRoundRobinPolicy(localDC)), nil
+	default:
+		return nil, fmt.Errorf("unknown load_balancing_policy %v in Cassandra online store configuration", policy)
+	}
+}
+
+func getStringListConfigValue(key string, v interface{}) ([]string, error) {
+	switch value := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{value}, nil
+	case []string:
+		return value, nil
+	case []interface{}:
+		values := make([]string, len(value))
+		for i, element := range value {
+			stringValue, ok := element.(string)
+			if !ok {
+				return nil, fmt.Errorf("failed to convert %s to list of strings: %+v", key, v)
+			}
+			values[i] = stringValue
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("failed to convert %s to list of strings: %+v", key, v)
+	}
+}
+
+// tableName returns the quoted name of the table of a feature view, like _fq_table_name in Python.
+func (c *CassandraOnlineStore) tableName(featureViewName string) string {
+	return cassandraIdentifier(c.keyspace) + "." + cassandraIdentifier(c.project+"_"+featureViewName)
+}
+
+// cassandraEntityKey returns the partition key of an entity, the hex of its serialized key.
+func cassandraEntityKey(entityKey *types.EntityKey, entityKeySerializationVersion int64) (string, error) {
+	serKey, err := serializeEntityKey(entityKey, entityKeySerializationVersion)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(*serKey), nil
+}
+
+// OnlineRead returns a FeatureData 2D array where each row corresponds to one entity key and each column to one
+// of the requested features. The partition of each entity is read once per feature view, with at most
+// read_concurrency queries in flight. Rows of entities without any stored values are left nil.
+func (c *CassandraOnlineStore) OnlineRead(ctx context.Context, entityKeys []*types.EntityKey, featureViewNames []string, featureNames []string) ([][]FeatureData, error) {
+	results := make([][]FeatureData, len(entityKeys))
+
+	serializedKeys := make([]string, len(entityKeys))
+	for i, entityKey := range entityKeys {
+		serializedKey, err := cassandraEntityKey(entityKey, c.config.EntityKeySerializationVersion)
+		if err != nil {
+			return nil, err
+		}
+		serializedKeys[i] = serializedKey
+	}
+
+	// Features are grouped per view, so each partition is read once per view.
+	viewToFeatureIndices := make(map[string]map[string]int)
+	viewOrder := make([]string, 0)
+	for i, featureViewName := range featureViewNames {
+		if _, ok := viewToFeatureIndices[featureViewName]; !ok {
+			viewToFeatureIndices[featureViewName] = make(map[string]int)
+			viewOrder = append(viewOrder, featureViewName)
+		}
+		viewToFeatureIndices[featureViewName][featureNames[i]] = i
+	}
+
+	var mu sync.Mutex
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(c.readConcurrency)
+	for _, featureViewName := range viewOrder {
+		cql := fmt.Sprintf(cassandraSelectCql, c.tableName(featureViewName))
+		featureIndices := viewToFeatureIndices[featureViewName]
+		for rowIdx, serializedKey := range serializedKeys {
+			group.Go(func() error {
+				iter := c.session.Query(cql, serializedKey).WithContext(groupCtx).Iter()
+				var featureName string
+				var valueBytes []byte
+				var eventTs time.Time
+				for iter.Scan(&featureName, &valueBytes, &eventTs) {
+					featureIdx, ok := featureIndices[featureName]
+					if !ok {
+						continue
+					}
+					var value types.Value
+					if err := proto.Unmarshal(valueBytes, &value); err != nil {
+						_ = iter.Close()
+						return errors.New("error converting parsed value to types.Value")
+					}
+					mu.Lock()
+					if results[rowIdx] == nil {
+						results[rowIdx] = newNullFeatureRow(featureViewNames, featureNames)
+					}
+					results[rowIdx][featureIdx] = FeatureData{
+						Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewName, FeatureName: featureName},
+						Timestamp: *timestamppb.New(eventTs),
+						Value:     types.Value{Val: value.Val},
+					}
+					mu.Unlock()
+				}
+				return iter.Close()
+			})
+		}
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// OnlineWrite inserts a row per feature value into the table of featureViewName, with at most write_concurrency
+// queries in flight.
+func (c *CassandraOnlineStore) OnlineWrite(ctx context.Context, featureViewName string, rows []*FeatureRow) error {
+	cql := fmt.Sprintf(cassandraInsertCql, c.tableName(featureViewName))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(c.writeConcurrency)
+	for _, row := range rows {
+		serializedKey, err := cassandraEntityKey(row.EntityKey, c.config.EntityKeySerializationVersion)
+		if err != nil {
+			_ = group.Wait()
+			return err
+		}
+		for featureName, value := range row.Values {
+			valueBytes, err := proto.Marshal(value)
+			if err != nil {
+				_ = group.Wait()
+				return err
+			}
+			group.Go(func() error {
+				return c.session.Query(cql, featureName, valueBytes, serializedKey, row.EventTimestamp).WithContext(groupCtx).Exec()
+			})
+		}
+	}
+	return group.Wait()
+}
+
+func (c *CassandraOnlineStore) Destruct() {
+	c.session.Close()
+}
+
+// cassandraIdentifier quotes a name for CQL.
+func cassandraIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
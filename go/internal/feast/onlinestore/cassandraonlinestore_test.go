@@ -0,0 +1,143 @@
+package onlinestore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+func TestParseCassandraConfig(t *testing.T) {
+	store := CassandraOnlineStore{project: "feature_repo"}
+	cluster, err := store.parseCassandraConfig(map[string]interface{}{
+		"type":  "cassandra",
+		"hosts": []interface{}{"cassandra-1", "cassandra-2"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"cassandra-1", "cassandra-2"}, cluster.Hosts)
+	assert.Equal(t, 9042, cluster.Port)
+	assert.Equal(t, "feast_keyspace", cluster.Keyspace)
+	assert.Equal(t, gocql.LocalOne, cluster.Consistency)
+	assert.Nil(t, cluster.Authenticator)
+	assert.Equal(t, 100, store.readConcurrency)
+	assert.Equal(t, 100, store.writeConcurrency)
+	assert.Equal(t, `"feast_keyspace"."feature_repo_driver_stats"`, store.tableName("driver_stats"))
+
+	cluster, err = store.parseCassandraConfig(map[string]interface{}{
+		"hosts":             []interface{}{"scylla"},
+		"port":              float64(19042),
+		"keyspace":          "features",
+		"username":          "feast",
+		"password":          "secret",
+		"protocol_version":  float64(4),
+		"request_timeout":   2.5,
+		"consistency":       "LOCAL_QUORUM",
+		"read_concurrency":  float64(10),
+		"write_concurrency": float64(20),
+		"load_balancing": map[string]interface{}{
+			"load_balancing_policy": "TokenAwarePolicy(DCAwareRoundRobinPolicy)",
+			"local_dc":              "eu-west",
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 19042, cluster.Port)
+	assert.Equal(t, "features", cluster.Keyspace)
+	assert.Equal(t, gocql.PasswordAuthenticator{Username: "feast", Password: "secret"}, cluster.Authenticator)
+	assert.Equal(t, 4, cluster.ProtoVersion)
+	assert.Equal(t, 2500*time.Millisecond, cluster.Timeout)
+	assert.Equal(t, gocql.LocalQuorum, cluster.Consistency)
+	assert.Equal(t, 10, store.readConcurrency)
+	assert.Equal(t, 20, store.writeConcurrency)
+	assert.Equal(t, `"features"."feature_repo_driver_stats"`, store.tableName("driver_stats"))
+}
+
+func TestParseCassandraConfigErrors(t *testing.T) {
+	for name, config := range map[string]map[string]interface{}{
+		"no hosts":            {},
+		"secure bundle":       {"secure_bundle_path": "/bundle.zip"},
+		"username only":       {"hosts": []interface{}{"cassandra"}, "username": "feast"},
+		"unknown policy":      {"hosts": []interface{}{"cassandra"}, "load_balancing": map[string]interface{}{"load_balancing_policy": "RoundRobin"}},
+		"unknown consistency": {"hosts": []interface{}{"cassandra"}, "consistency": "MOST"},
+		"no concurrency":      {"hosts": []interface{}{"cassandra"}, "read_concurrency": 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := CassandraOnlineStore{}
+			_, err := store.parseCassandraConfig(config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCassandraEntityKey(t *testing.T) {
+	entityKey, err := cassandraEntityKey(driverEntityKeys(1001)[0], 2)
+	require.Nil(t, err)
+	// serialize_entity_key(EntityKey(join_keys=["driver_id"], entity_values=[Value(int64_val=1001)]), 2).hex()
+	assert.Equal(t, "020000006472697665725f69640400000008000000e903000000000000", entityKey)
+}
+
+// TestCassandraOnlineStore runs against a local Cassandra or ScyllaDB, e.g. docker run -p 9042:9042 cassandra,
+// when FEAST_CASSANDRA_HOSTS is set to a comma separated list of its hosts.
+func TestCassandraOnlineStore(t *testing.T) {
+	hosts := os.Getenv("FEAST_CASSANDRA_HOSTS")
+	if hosts == "" {
+		t.Skip("FEAST_CASSANDRA_HOSTS is not set")
+	}
+	keyspace := fmt.Sprintf("feast_test_%d", time.Now().UnixNano())
+	cluster := gocql.NewCluster(strings.Split(hosts, ",")...)
+	session, err := cluster.CreateSession()
+	require.Nil(t, err)
+	defer session.Close()
+	require.Nil(t, session.Query(fmt.Sprintf(
+		`CREATE KEYSPACE %s WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1}`, keyspace)).Exec())
+	t.Cleanup(func() { _ = session.Query("DROP KEYSPACE " + keyspace).Exec() })
+
+	config := map[string]interface{}{
+		"hosts":       strings.Split(hosts, ","),
+		"keyspace":    keyspace,
+		"consistency": "ONE",
+	}
+	store, err := NewCassandraOnlineStore("feature_repo", &registry.RepoConfig{EntityKeySerializationVersion: 2}, config)
+	require.Nil(t, err)
+	defer store.Destruct()
+	require.Nil(t, session.Query(fmt.Sprintf(`CREATE TABLE %s (
+		entity_key TEXT,
+		feature_name TEXT,
+		value BLOB,
+		event_ts TIMESTAMP,
+		created_ts TIMESTAMP,
+		PRIMARY KEY ((entity_key), feature_name)
+	) WITH CLUSTERING ORDER BY (feature_name ASC)`, store.tableName("driver_stats"))).Exec())
+
+	eventTs := time.Unix(1704164645, 0)
+	rows := make([]*FeatureRow, 0)
+	for _, entityKey := range driverEntityKeys(1, 2) {
+		rows = append(rows, &FeatureRow{
+			EntityKey: entityKey,
+			Values: map[string]*types.Value{
+				"trips":     {Val: &types.Value_Int64Val{Int64Val: entityKey.EntityValues[0].GetInt64Val() * 10}},
+				"conv_rate": {Val: &types.Value_DoubleVal{DoubleVal: 0.5}},
+			},
+			EventTimestamp: eventTs,
+		})
+	}
+	ctx := context.Background()
+	require.Nil(t, store.OnlineWrite(ctx, "driver_stats", rows))
+
+	results, err := store.OnlineRead(ctx, driverEntityKeys(2, 3, 1), []string{"driver_stats", "driver_stats"}, []string{"trips", "acc_rate"})
+	require.Nil(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, int64(20), results[0][0].Value.GetInt64Val())
+	assert.Equal(t, eventTs.Unix(), results[0][0].Timestamp.Seconds)
+	assert.Equal(t, &types.Value_NullVal{NullVal: types.Null_NULL}, results[0][1].Value.Val)
+	assert.Nil(t, results[1])
+	assert.Equal(t, int64(10), results[2][0].Value.GetInt64Val())
+}
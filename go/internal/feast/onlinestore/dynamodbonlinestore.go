@@ -0,0 +1,383 @@
+package onlinestore
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog/log"
+	"github.com/spaolacci/murmur3"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+const (
+	defaultDynamoDBBatchSize         = 40
+	defaultDynamoDBTableNameTemplate = "{project}.{table_name}"
+	// BatchGetItem and BatchWriteItem limits
+	maxDynamoDBReadBatchSize  = 100
+	maxDynamoDBWriteBatchSize = 25
+	// Batches of a read sent at the same time
+	dynamoDBReadConcurrency = 8
+	// Retries of the keys that DynamoDB left unprocessed, e.g. because the table's throughput was exceeded
+	dynamoDBUnprocessedRetries      = 5
+	dynamoDBUnprocessedRetryBackoff = 50 * time.Millisecond
+)
+
+// Python writes event timestamps with str(datetime), e.g. "2024-01-02 03:04:05.123456+00:00". When parsing,
+// Go accepts the fractional seconds without them being in the layout.
+var dynamoDBTimestampLayouts = []string{"2006-01-02 15:04:05-07:00", "2006-01-02T15:04:05-07:00", "2006-01-02 15:04:05"}
+
+type DynamoDBOnlineStore struct {
+	// Feast project name
+	project string
+
+	client *dynamodb.Client
+
+	// Name of the table of a feature view, with {project} and {table_name} placeholders (table_name_template)
+	tableNameTemplate string
+
+	// Number of items retrieved with one BatchGetItem call (batch_size)
+	batchSize int
+
+	// Whether reads are strongly consistent (consistent_reads)
+	consistentReads bool
+
+	config *registry.RepoConfig
+}
+
+// NewDynamoDBOnlineStore creates a DynamoDB online store that reads the tables written by the Python
+// DynamoDBOnlineStore. onlineStoreConfig accepts the same keys as the Python store: region, endpoint_url,
+// table_name_template, batch_size and consistent_reads. Credentials are resolved like in other AWS clients,
+// e.g. from the environment or the instance role.
+func NewDynamoDBOnlineStore(project string, config *registry.RepoConfig, onlineStoreConfig map[string]interface{}) (*DynamoDBOnlineStore, error) {
+	store := DynamoDBOnlineStore{
+		project:           project,
+		config:            config,
+		tableNameTemplate: defaultDynamoDBTableNameTemplate,
+		batchSize:         defaultDynamoDBBatchSize,
+	}
+
+	var region, endpointUrl string
+	for key, value := range onlineStoreConfig {
+		if value == nil {
+			continue
+		}
+		switch key {
+		case "region", "endpoint_url", "table_name_template":
+			stringValue, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("failed to convert %s to string: %+v", key, value)
+			}
+			switch key {
+			case "region":
+				region = stringValue
+			case "endpoint_url":
+				endpointUrl = stringValue
+			case "table_name_template":
+				store.tableNameTemplate = stringValue
+			}
+		case "batch_size":
+			batchSize, err := getIntConfigValue(key, value)
+			if err != nil {
+				return nil, err
+			}
+			if batchSize < 1 || batchSize > maxDynamoDBReadBatchSize {
+				return nil, fmt.Errorf("batch_size must be between 1 and %d, got %d", maxDynamoDBReadBatchSize, batchSize)
+			}
+			store.batchSize = int(batchSize)
+		case "consistent_reads":
+			consistentReads, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("failed to convert consistent_reads to bool: %+v", value)
+			}
+			store.consistentReads = consistentReads
+		}
+	}
+
+	options := make([]func(*awsconfig.LoadOptions) error, 0)
+	if region != "" {
+		options = append(options, awsconfig.WithRegion(region))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+	store.client = dynamodb.NewFromConfig(awsConfig, func(o *dynamodb.Options) {
+		if endpointUrl != "" {
+			// E.g. DynamoDB Local at http://localhost:8000
+			o.BaseEndpoint = aws.String(endpointUrl)
+		}
+	})
+	log.Info().Msgf("Using DynamoDB: region %s", awsConfig.Region)
+	return &store, nil
+}
+
+func (d *DynamoDBOnlineStore) tableName(featureViewName string) string {
+	return strings.NewReplacer("{project}", d.project, "{table_name}", featureViewName).Replace(d.tableNameTemplate)
+}
+
+// dynamoDBEntityId returns the partition key of an entity, matching compute_entity_id in the Python SDK: the hex
+// of the 128-bit murmur3 hash of the serialized entity key.
+func dynamoDBEntityId(entityKey *types.EntityKey, entityKeySerializationVersion int64) (string, error) {
+	serKey, err := serializeEntityKey(entityKey, entityKeySerializationVersion)
+	if err != nil {
+		return "", err
+	}
+	h1, h2 := murmur3.Sum128(*serKey)
+	hash := make([]byte, 16)
+	binary.LittleEndian.PutUint64(hash[:8], h1)
+	binary.LittleEndian.PutUint64(hash[8:], h2)
+	return hex.EncodeToString(hash), nil
+}
+
+func parseDynamoDBTimestamp(value string) (time.Time, error) {
+	for _, layout := range dynamoDBTimestampLayouts {
+		if timestamp, err := time.Parse(layout, value); err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid event_ts %q", value)
+}
+
+// formatDynamoDBTimestamp formats a timestamp like str(datetime) in Python does for timezone aware datetimes.
+func formatDynamoDBTimestamp(timestamp time.Time) string {
+	timestamp = timestamp.UTC()
+	if timestamp.Nanosecond()/1000 == 0 {
+		return timestamp.Format("2006-01-02 15:04:05-07:00")
+	}
+	return timestamp.Format("2006-01-02 15:04:05.000000-07:00")
+}
+
+// OnlineRead returns a FeatureData 2D array where each row corresponds to one entity key and each column to one
+// of the requested features. The items of each feature view are read with BatchGetItem calls of at most
+// batch_size keys. Rows of entities without any stored values are left nil.
+func (d *DynamoDBOnlineStore) OnlineRead(ctx context.Context, entityKeys []*types.EntityKey, featureViewNames []string, featureNames []string) ([][]FeatureData, error) {
+	results := make([][]FeatureData, len(entityKeys))
+
+	entityIds := make([]string, len(entityKeys))
+	entityIdToIndices := make(map[string][]int)
+	uniqueEntityIds := make([]string, 0, len(entityKeys))
+	for i, entityKey := range entityKeys {
+		entityId, err := dynamoDBEntityId(entityKey, d.config.EntityKeySerializationVersion)
+		if err != nil {
+			return nil, err
+		}
+		entityIds[i] = entityId
+		if _, ok := entityIdToIndices[entityId]; !ok {
+			uniqueEntityIds = append(uniqueEntityIds, entityId)
+		}
+		entityIdToIndices[entityId] = append(entityIdToIndices[entityId], i)
+	}
+
+	// Features are grouped per view, so each view table is read once.
+	viewToFeatureIndices := make(map[string][]int)
+	viewOrder := make([]string, 0)
+	for i, featureViewName := range featureViewNames {
+		if _, ok := viewToFeatureIndices[featureViewName]; !ok {
+			viewOrder = append(viewOrder, featureViewName)
+		}
+		viewToFeatureIndices[featureViewName] = append(viewToFeatureIndices[featureViewName], i)
+	}
+
+	var mu sync.Mutex
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(dynamoDBReadConcurrency)
+	for _, featureViewName := range viewOrder {
+		tableName := d.tableName(featureViewName)
+		for start := 0; start < len(uniqueEntityIds); start += d.batchSize {
+			batch := uniqueEntityIds[start:min(start+d.batchSize, len(uniqueEntityIds))]
+			group.Go(func() error {
+				items, err := d.batchGetItems(groupCtx, tableName, batch)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, item := range items {
+					if err := d.addItemToResults(results, item, featureViewName, viewToFeatureIndices[featureViewName], entityIdToIndices, featureViewNames, featureNames); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// batchGetItems reads the items of the entities from the table, retrying the keys that DynamoDB leaves
+// unprocessed with exponential backoff.
+func (d *DynamoDBOnlineStore) batchGetItems(ctx context.Context, tableName string, entityIds []string) ([]map[string]dynamodbtypes.AttributeValue, error) {
+	keys := make([]map[string]dynamodbtypes.AttributeValue, len(entityIds))
+	for i, entityId := range entityIds {
+		keys[i] = map[string]dynamodbtypes.AttributeValue{"entity_id": &dynamodbtypes.AttributeValueMemberS{Value: entityId}}
+	}
+	requestItems := map[string]dynamodbtypes.KeysAndAttributes{
+		tableName: {Keys: keys, ConsistentRead: aws.Bool(d.consistentReads)},
+	}
+
+	items := make([]map[string]dynamodbtypes.AttributeValue, 0, len(entityIds))
+	backoff := dynamoDBUnprocessedRetryBackoff
+	for attempt := 0; ; attempt++ {
+		output, err := d.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, output.Responses[tableName]...)
+		unprocessed, ok := output.UnprocessedKeys[tableName]
+		if !ok || len(unprocessed.Keys) == 0 {
+			return items, nil
+		}
+		if attempt == dynamoDBUnprocessedRetries {
+			return nil, fmt.Errorf("%d keys of table %s are still unprocessed after %d retries", len(unprocessed.Keys), tableName, attempt)
+		}
+		requestItems = map[string]dynamodbtypes.KeysAndAttributes{tableName: unprocessed}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// addItemToResults sets the requested features of a feature view from its item in the rows of the item's entity.
+func (d *DynamoDBOnlineStore) addItemToResults(
+	results [][]FeatureData,
+	item map[string]dynamodbtypes.AttributeValue,
+	featureViewName string,
+	featureIndices []int,
+	entityIdToIndices map[string][]int,
+	featureViewNames []string,
+	featureNames []string) error {
+	entityIdAttribute, ok := item["entity_id"].(*dynamodbtypes.AttributeValueMemberS)
+	if !ok {
+		return errors.New("error parsing entity_id from dynamodb")
+	}
+	eventTsAttribute, ok := item["event_ts"].(*dynamodbtypes.AttributeValueMemberS)
+	if !ok {
+		return errors.New("error parsing event_ts from dynamodb")
+	}
+	eventTs, err := parseDynamoDBTimestamp(eventTsAttribute.Value)
+	if err != nil {
+		return err
+	}
+	valuesAttribute, ok := item["values"].(*dynamodbtypes.AttributeValueMemberM)
+	if !ok {
+		return errors.New("error parsing values from dynamodb")
+	}
+
+	for _, rowIdx := range entityIdToIndices[entityIdAttribute.Value] {
+		for _, featureIdx := range featureIndices {
+			valueAttribute, ok := valuesAttribute.Value[featureNames[featureIdx]]
+			if !ok {
+				continue
+			}
+			valueBytes, ok := valueAttribute.(*dynamodbtypes.AttributeValueMemberB)
+			if !ok {
+				return errors.New("error parsing value from dynamodb")
+			}
+			var value types.Value
+			if err := proto.Unmarshal(valueBytes.Value, &value); err != nil {
+				return errors.New("error converting parsed value to types.Value")
+			}
+			if results[rowIdx] == nil {
+				results[rowIdx] = newNullFeatureRow(featureViewNames, featureNames)
+			}
+			results[rowIdx][featureIdx] = FeatureData{
+				Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewName, FeatureName: featureNames[featureIdx]},
+				Timestamp: *timestamppb.New(eventTs),
+				Value:     types.Value{Val: value.Val},
+			}
+		}
+	}
+	return nil
+}
+
+// OnlineWrite puts an item per row into the table of featureViewName with BatchWriteItem. Like in Python, an item
+// replaces the previous item of the entity, and only the latest row of each entity is written.
+func (d *DynamoDBOnlineStore) OnlineWrite(ctx context.Context, featureViewName string, rows []*FeatureRow) error {
+	tableName := d.tableName(featureViewName)
+	latestRows := make(map[string]*FeatureRow)
+	entityIds := make([]string, 0, len(rows))
+	for _, row := range rows {
+		entityId, err := dynamoDBEntityId(row.EntityKey, d.config.EntityKeySerializationVersion)
+		if err != nil {
+			return err
+		}
+		previous, ok := latestRows[entityId]
+		if !ok {
+			entityIds = append(entityIds, entityId)
+		}
+		if !ok || !row.EventTimestamp.Before(previous.EventTimestamp) {
+			latestRows[entityId] = row
+		}
+	}
+
+	writeRequests := make([]dynamodbtypes.WriteRequest, 0, len(entityIds))
+	for _, entityId := range entityIds {
+		row := latestRows[entityId]
+		values := make(map[string]dynamodbtypes.AttributeValue, len(row.Values))
+		for featureName, value := range row.Values {
+			valueBytes, err := proto.Marshal(value)
+			if err != nil {
+				return err
+			}
+			values[featureName] = &dynamodbtypes.AttributeValueMemberB{Value: valueBytes}
+		}
+		writeRequests = append(writeRequests, dynamodbtypes.WriteRequest{PutRequest: &dynamodbtypes.PutRequest{Item: map[string]dynamodbtypes.AttributeValue{
+			"entity_id": &dynamodbtypes.AttributeValueMemberS{Value: entityId},
+			"event_ts":  &dynamodbtypes.AttributeValueMemberS{Value: formatDynamoDBTimestamp(row.EventTimestamp)},
+			"values":    &dynamodbtypes.AttributeValueMemberM{Value: values},
+		}}})
+	}
+
+	for start := 0; start < len(writeRequests); start += maxDynamoDBWriteBatchSize {
+		requestItems := map[string][]dynamodbtypes.WriteRequest{
+			tableName: writeRequests[start:min(start+maxDynamoDBWriteBatchSize, len(writeRequests))],
+		}
+		backoff := dynamoDBUnprocessedRetryBackoff
+		for attempt := 0; len(requestItems[tableName]) > 0; attempt++ {
+			if attempt > dynamoDBUnprocessedRetries {
+				return fmt.Errorf("%d items of table %s are still unprocessed after %d retries", len(requestItems[tableName]), tableName, dynamoDBUnprocessedRetries)
+			}
+			if attempt > 0 {
+				select {
+				case <-time.After(backoff):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				backoff *= 2
+			}
+			output, err := d.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requestItems})
+			if err != nil {
+				return err
+			}
+			requestItems = map[string][]dynamodbtypes.WriteRequest{tableName: output.UnprocessedItems[tableName]}
+		}
+	}
+	return nil
+}
+
+// Dummy destruct function to conform with plugin OnlineStore interface
+func (d *DynamoDBOnlineStore) Destruct() {
+
+}
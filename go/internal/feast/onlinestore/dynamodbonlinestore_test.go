@@ -0,0 +1,280 @@
+package onlinestore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+// fakeDynamoDB serves BatchGetItem and BatchWriteItem of the DynamoDB JSON API from memory. The first
+// unprocessedCalls BatchGetItem calls leave all keys unprocessed, like DynamoDB does when the throughput of a
+// table is exceeded.
+type fakeDynamoDB struct {
+	mu               sync.Mutex
+	items            map[string]map[string]map[string]interface{}
+	unprocessedCalls int
+	batchGetCalls    int
+}
+
+func (f *fakeDynamoDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var request struct {
+		RequestItems map[string]json.RawMessage
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	response := make(map[string]interface{})
+	switch r.Header.Get("X-Amz-Target") {
+	case "DynamoDB_20120810.BatchGetItem":
+		f.batchGetCalls++
+		responses := make(map[string][]interface{})
+		unprocessed := make(map[string]interface{})
+		for table, raw := range request.RequestItems {
+			var keysAndAttributes struct {
+				Keys []map[string]map[string]string
+			}
+			_ = json.Unmarshal(raw, &keysAndAttributes)
+			keys := keysAndAttributes.Keys
+			if f.batchGetCalls <= f.unprocessedCalls {
+				unprocessed[table] = map[string]interface{}{"Keys": keys}
+				keys = nil
+			}
+			responses[table] = make([]interface{}, 0)
+			for _, key := range keys {
+				if item, ok := f.items[table][key["entity_id"]["S"]]; ok {
+					responses[table] = append(responses[table], item)
+				}
+			}
+		}
+		response["Responses"] = responses
+		response["UnprocessedKeys"] = unprocessed
+	case "DynamoDB_20120810.BatchWriteItem":
+		for table, raw := range request.RequestItems {
+			var writeRequests []struct {
+				PutRequest struct {
+					Item map[string]interface{}
+				}
+			}
+			_ = json.Unmarshal(raw, &writeRequests)
+			for _, writeRequest := range writeRequests {
+				f.putItem(table, writeRequest.PutRequest.Item)
+			}
+		}
+		response["UnprocessedItems"] = map[string]interface{}{}
+	default:
+		http.Error(w, "unsupported operation", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (f *fakeDynamoDB) putItem(table string, item map[string]interface{}) {
+	if f.items[table] == nil {
+		f.items[table] = make(map[string]map[string]interface{})
+	}
+	entityId := item["entity_id"].(map[string]interface{})["S"].(string)
+	f.items[table][entityId] = item
+}
+
+func newFakeDynamoDBOnlineStore(t *testing.T, config map[string]interface{}) (*DynamoDBOnlineStore, *fakeDynamoDB) {
+	fake := &fakeDynamoDB{items: make(map[string]map[string]map[string]interface{})}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	config["region"] = "us-west-2"
+	config["endpoint_url"] = server.URL
+	store, err := NewDynamoDBOnlineStore("feature_repo", &registry.RepoConfig{EntityKeySerializationVersion: 2}, config)
+	require.Nil(t, err)
+	return store, fake
+}
+
+func TestNewDynamoDBOnlineStore(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	store, err := NewDynamoDBOnlineStore("feature_repo", &registry.RepoConfig{}, map[string]interface{}{
+		"type":   "dynamodb",
+		"region": "us-west-2",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 40, store.batchSize)
+	assert.False(t, store.consistentReads)
+	assert.Equal(t, "feature_repo.driver_hourly_stats", store.tableName("driver_hourly_stats"))
+
+	store, err = NewDynamoDBOnlineStore("feature_repo", &registry.RepoConfig{}, map[string]interface{}{
+		"region":              "us-west-2",
+		"table_name_template": "feast-{project}-{table_name}",
+		"batch_size":          float64(100),
+		"consistent_reads":    true,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 100, store.batchSize)
+	assert.True(t, store.consistentReads)
+	assert.Equal(t, "feast-feature_repo-driver_hourly_stats", store.tableName("driver_hourly_stats"))
+
+	_, err = NewDynamoDBOnlineStore("feature_repo", &registry.RepoConfig{}, map[string]interface{}{
+		"region":     "us-west-2",
+		"batch_size": 101,
+	})
+	assert.Error(t, err)
+}
+
+func TestDynamoDBEntityIdMatchesPython(t *testing.T) {
+	// compute_entity_id(EntityKey(join_keys=["driver_id"], entity_values=[Value(int64_val=1001)]), 2)
+	entityId, err := dynamoDBEntityId(driverEntityKeys(1001)[0], 2)
+	require.Nil(t, err)
+	assert.Equal(t, "5b483ef27f6503dc22e8faf55eb4df4b", entityId)
+
+	otherEntityId, err := dynamoDBEntityId(driverEntityKeys(1002)[0], 2)
+	require.Nil(t, err)
+	assert.NotEqual(t, entityId, otherEntityId)
+}
+
+func TestDynamoDBTimestamps(t *testing.T) {
+	timestamp, err := parseDynamoDBTimestamp("2024-01-02 03:04:05+00:00")
+	require.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Unix(), timestamp.Unix())
+
+	timestamp, err = parseDynamoDBTimestamp("2024-01-02 03:04:05.123456+02:00")
+	require.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 1, 4, 5, 123456000, time.UTC), timestamp.UTC())
+
+	_, err = parseDynamoDBTimestamp("yesterday")
+	assert.Error(t, err)
+
+	assert.Equal(t, "2024-01-02 03:04:05+00:00", formatDynamoDBTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.Equal(t, "2024-01-02 03:04:05.000001+00:00", formatDynamoDBTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 1000, time.UTC)))
+}
+
+func TestDynamoDBOnlineReadItemsWrittenByPython(t *testing.T) {
+	store, fake := newFakeDynamoDBOnlineStore(t, map[string]interface{}{})
+	entityId, err := dynamoDBEntityId(driverEntityKeys(1001)[0], 2)
+	require.Nil(t, err)
+	value, err := proto.Marshal(&types.Value{Val: &types.Value_DoubleVal{DoubleVal: 0.5}})
+	require.Nil(t, err)
+	fake.putItem("feature_repo.driver_stats", map[string]interface{}{
+		"entity_id": map[string]interface{}{"S": entityId},
+		"event_ts":  map[string]interface{}{"S": "2024-01-02 03:04:05+00:00"},
+		"values": map[string]interface{}{"M": map[string]interface{}{
+			"conv_rate": map[string]interface{}{"B": base64.StdEncoding.EncodeToString(value)},
+		}},
+	})
+
+	results, err := store.OnlineRead(context.Background(), driverEntityKeys(1001, 1002),
+		[]string{"driver_stats", "driver_stats"}, []string{"conv_rate", "acc_rate"})
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	require.Len(t, results[0], 2)
+	assert.Equal(t, 0.5, results[0][0].Value.GetDoubleVal())
+	assert.Equal(t, int64(1704164645), results[0][0].Timestamp.Seconds)
+	assert.Equal(t, &types.Value_NullVal{NullVal: types.Null_NULL}, results[0][1].Value.Val)
+	assert.Nil(t, results[1])
+}
+
+func TestDynamoDBOnlineReadRetriesUnprocessedKeys(t *testing.T) {
+	store, fake := newFakeDynamoDBOnlineStore(t, map[string]interface{}{"batch_size": 2})
+	fake.unprocessedCalls = 2
+	rows := make([]*FeatureRow, 0)
+	for _, entityKey := range driverEntityKeys(1, 2, 3) {
+		rows = append(rows, &FeatureRow{
+			EntityKey:      entityKey,
+			Values:         map[string]*types.Value{"trips": {Val: &types.Value_Int64Val{Int64Val: entityKey.EntityValues[0].GetInt64Val() * 10}}},
+			EventTimestamp: time.Unix(1704164645, 0),
+		})
+	}
+	require.Nil(t, store.OnlineWrite(context.Background(), "driver_stats", rows))
+
+	results, err := store.OnlineRead(context.Background(), driverEntityKeys(3, 1, 2, 1), []string{"driver_stats"}, []string{"trips"})
+	require.Nil(t, err)
+	trips := make([]int64, len(results))
+	for i, row := range results {
+		require.NotNil(t, row)
+		trips[i] = row[0].Value.GetInt64Val()
+	}
+	assert.Equal(t, []int64{30, 10, 20, 10}, trips)
+	// Two batches, plus the retries of the unprocessed keys of the first two calls
+	assert.Equal(t, 4, fake.batchGetCalls)
+}
+
+func TestDynamoDBOnlineReadFailsWhenKeysStayUnprocessed(t *testing.T) {
+	store, fake := newFakeDynamoDBOnlineStore(t, map[string]interface{}{})
+	fake.unprocessedCalls = dynamoDBUnprocessedRetries + 1
+
+	_, err := store.OnlineRead(context.Background(), driverEntityKeys(1, 2), []string{"driver_stats"}, []string{"trips"})
+	assert.ErrorContains(t, err, "unprocessed")
+}
+
+// TestDynamoDBLocal runs against DynamoDB Local, e.g. docker run -p 8000:8000 amazon/dynamodb-local, when
+// FEAST_DYNAMODB_ENDPOINT is set to its URL.
+func TestDynamoDBLocal(t *testing.T) {
+	endpoint := os.Getenv("FEAST_DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("FEAST_DYNAMODB_ENDPOINT is not set")
+	}
+	if os.Getenv("AWS_ACCESS_KEY_ID") == "" {
+		t.Setenv("AWS_ACCESS_KEY_ID", "test")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	}
+	project := fmt.Sprintf("feast_test_%d", time.Now().UnixNano())
+	store, err := NewDynamoDBOnlineStore(project, &registry.RepoConfig{EntityKeySerializationVersion: 2}, map[string]interface{}{
+		"region":           "us-west-2",
+		"endpoint_url":     endpoint,
+		"consistent_reads": true,
+	})
+	require.Nil(t, err)
+
+	ctx := context.Background()
+	tableName := store.tableName("driver_stats")
+	_, err = store.client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            aws.String(tableName),
+		KeySchema:            []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("entity_id"), KeyType: dynamodbtypes.KeyTypeHash}},
+		AttributeDefinitions: []dynamodbtypes.AttributeDefinition{{AttributeName: aws.String("entity_id"), AttributeType: dynamodbtypes.ScalarAttributeTypeS}},
+		BillingMode:          dynamodbtypes.BillingModePayPerRequest,
+	})
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_, _ = store.client.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+	})
+
+	driverIds := make([]int64, 120)
+	rows := make([]*FeatureRow, len(driverIds))
+	for i := range driverIds {
+		driverIds[i] = int64(i)
+		rows[i] = &FeatureRow{
+			EntityKey:      driverEntityKeys(int64(i))[0],
+			Values:         map[string]*types.Value{"name": {Val: &types.Value_StringVal{StringVal: strings.Repeat("x", i)}}},
+			EventTimestamp: time.Unix(1704164645, 0),
+		}
+	}
+	require.Nil(t, store.OnlineWrite(ctx, "driver_stats", rows))
+
+	results, err := store.OnlineRead(ctx, driverEntityKeys(append(driverIds, 1000)...), []string{"driver_stats"}, []string{"name"})
+	require.Nil(t, err)
+	for i := range driverIds {
+		require.NotNil(t, results[i])
+		assert.Equal(t, strings.Repeat("x", i), results[i][0].Value.GetStringVal())
+	}
+	assert.Nil(t, results[len(driverIds)])
+}
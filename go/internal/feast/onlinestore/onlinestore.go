@@ -3,6 +3,7 @@ package onlinestore
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/feast-dev/feast/go/internal/feast/registry"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
@@ -16,6 +17,15 @@ type FeatureData struct {
 	Value     types.Value
 }
 
+// FeatureRow holds the feature values of a single entity of one feature view, as written by OnlineWrite.
+type FeatureRow struct {
+	EntityKey *types.EntityKey
+	// Values maps feature names to their values
+	Values           map[string]*types.Value
+	EventTimestamp   time.Time
+	CreatedTimestamp time.Time
+}
+
 type OnlineStore interface {
 	// OnlineRead reads multiple features (specified in featureReferences) for multiple
 	// entity keys (specified in entityKeys) and returns an array of array of features,
@@ -36,6 +46,9 @@ type OnlineStore interface {
 	// => allocate memory for each field once in OnlineRead
 	// and reuse them in GetOnlineFeaturesResponse?
 	OnlineRead(ctx context.Context, entityKeys []*types.EntityKey, featureViewNames []string, featureNames []string) ([][]FeatureData, error)
+	// OnlineWrite upserts the given rows into the table of featureViewName, using the same layout as the
+	// corresponding Python online store so that values written from Go can be read by either implementation.
+	OnlineWrite(ctx context.Context, featureViewName string, rows []*FeatureRow) error
 	// Destruct must be call once user is done using OnlineStore
 	// This is to comply with the Connector since we have to close the plugin
 	Destruct()
@@ -51,6 +64,12 @@ func getOnlineStoreType(onlineStoreConfig map[string]interface{}) (string, bool)
 	}
 }
 
+// GetOnlineStoreType returns the type of the online store configured in the repo config, e.g. redis.
+func GetOnlineStoreType(config *registry.RepoConfig) string {
+	onlineStoreType, _ := getOnlineStoreType(config.OnlineStore)
+	return onlineStoreType
+}
+
 func NewOnlineStore(config *registry.RepoConfig) (OnlineStore, error) {
 	onlineStoreType, ok := getOnlineStoreType(config.OnlineStore)
 	if !ok {
@@ -61,7 +80,16 @@ func NewOnlineStore(config *registry.RepoConfig) (OnlineStore, error) {
 	} else if onlineStoreType == "redis" {
 		onlineStore, err := NewRedisOnlineStore(config.Project, config, config.OnlineStore)
 		return onlineStore, err
+	} else if onlineStoreType == "postgres" {
+		onlineStore, err := NewPostgresOnlineStore(config.Project, config, config.OnlineStore)
+		return onlineStore, err
+	} else if onlineStoreType == "dynamodb" {
+		onlineStore, err := NewDynamoDBOnlineStore(config.Project, config, config.OnlineStore)
+		return onlineStore, err
+	} else if onlineStoreType == "cassandra" {
+		onlineStore, err := NewCassandraOnlineStore(config.Project, config, config.OnlineStore)
+		return onlineStore, err
 	} else {
-		return nil, fmt.Errorf("%s online store type is currently not supported; only redis and sqlite are supported", onlineStoreType)
+		return nil, fmt.Errorf("%s online store type is currently not supported; only redis, sqlite, postgres, dynamodb and cassandra are supported", onlineStoreType)
 	}
 }
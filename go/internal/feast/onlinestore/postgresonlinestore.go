@@ -0,0 +1,299 @@
+package onlinestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+const (
+	defaultPostgresPort     = 5432
+	defaultPostgresSchema   = "public"
+	defaultPostgresMinConns = 1
+	defaultPostgresMaxConns = 10
+)
+
+type PostgresOnlineStore struct {
+	// Feast project name
+	project string
+
+	// Schema the feature view tables live in (db_schema in feature_store.yaml)
+	schema string
+
+	// Postgres connection pool
+	pool *pgxpool.Pool
+
+	config *registry.RepoConfig
+}
+
+// NewPostgresOnlineStore creates a Postgres online store that reads the tables written by the Python
+// PostgreSQLOnlineStore. onlineStoreConfig accepts the same keys as the Python store: host, port, database,
+// db_schema, user, password, sslmode, sslkey_path, sslcert_path, sslrootcert_path, min_conn, max_conn and
+// keepalives_idle. The connection pool is created right away, but pgxpool opens its connections in the background,
+// so an unreachable server is only reported by the first read.
+func NewPostgresOnlineStore(project string, config *registry.RepoConfig, onlineStoreConfig map[string]interface{}) (*PostgresOnlineStore, error) {
+	store := PostgresOnlineStore{
+		project: project,
+		config:  config,
+		schema:  defaultPostgresSchema,
+	}
+
+	poolConfig, err := getPostgresPoolConfig(onlineStoreConfig)
+	if err != nil {
+		return nil, err
+	}
+	if schema, ok := poolConfig.ConnConfig.RuntimeParams["search_path"]; ok {
+		store.schema = schema
+	}
+
+	log.Info().Msgf("Using Postgres: %s:%d/%s", poolConfig.ConnConfig.Host, poolConfig.ConnConfig.Port, poolConfig.ConnConfig.Database)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, err
+	}
+	store.pool = pool
+
+	return &store, nil
+}
+
+func getPostgresPoolConfig(onlineStoreConfig map[string]interface{}) (*pgxpool.Config, error) {
+	params := map[string]string{
+		"port": fmt.Sprintf("%d", defaultPostgresPort),
+	}
+	minConns := int64(defaultPostgresMinConns)
+	maxConns := int64(defaultPostgresMaxConns)
+	schema := defaultPostgresSchema
+	var keepalivesIdle int64
+
+	for k, v := range onlineStoreConfig {
+		switch k {
+		case "type", "conn_type", "vector_enabled", "vector_len":
+			// conn_type only selects between a single connection and a pool in Python; Go always pools.
+			continue
+		case "host", "database", "user", "password", "sslmode", "sslkey_path", "sslcert_path", "sslrootcert_path":
+			value, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("failed to convert %s to string: %+v", k, v)
+			}
+			switch k {
+			case "database":
+				params["dbname"] = value
+			case "sslkey_path":
+				params["sslkey"] = value
+			case "sslcert_path":
+				params["sslcert"] = value
+			case "sslrootcert_path":
+				params["sslrootcert"] = value
+			default:
+				params[k] = value
+			}
+		case "db_schema":
+			value, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("failed to convert db_schema to string: %+v", v)
+			}
+			schema = value
+		case "port", "min_conn", "max_conn", "keepalives_idle":
+			value, err := getIntConfigValue(k, v)
+			if err != nil {
+				return nil, err
+			}
+			switch k {
+			case "min_conn":
+				minConns = value
+			case "max_conn":
+				maxConns = value
+			case "keepalives_idle":
+				// pgx sends unknown connection string keys to the server as runtime parameters, which Postgres
+				// rejects, so keepalives_idle is applied to the TCP connections instead
+				keepalivesIdle = value
+			default:
+				params[k] = fmt.Sprintf("%d", value)
+			}
+		}
+	}
+
+	for _, required := range []string{"host", "dbname", "user"} {
+		if _, ok := params[required]; !ok {
+			return nil, fmt.Errorf("postgres online store config is missing required option %s", required)
+		}
+	}
+
+	parts := make([]string, 0, len(params))
+	for k, v := range params {
+		parts = append(parts, fmt.Sprintf("%s='%s'", k, strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v)))
+	}
+	poolConfig, err := pgxpool.ParseConfig(strings.Join(parts, " "))
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.MinConns = int32(minConns)
+	poolConfig.MaxConns = int32(maxConns)
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema
+	if keepalivesIdle > 0 {
+		dialer := &net.Dialer{
+			Timeout:   poolConfig.ConnConfig.ConnectTimeout,
+			KeepAlive: time.Duration(keepalivesIdle) * time.Second,
+		}
+		poolConfig.ConnConfig.DialFunc = dialer.DialContext
+	}
+	return poolConfig, nil
+}
+
+// getIntConfigValue converts a numeric online store option, which is decoded as float64 from YAML/JSON.
+func getIntConfigValue(key string, v interface{}) (int64, error) {
+	switch value := v.(type) {
+	case float64:
+		return int64(value), nil
+	case int:
+		return int64(value), nil
+	case int32:
+		return int64(value), nil
+	case int64:
+		return value, nil
+	case string:
+		var result int64
+		if _, err := fmt.Sscanf(value, "%d", &result); err != nil {
+			return 0, fmt.Errorf("failed to convert %s to int: %+v", key, v)
+		}
+		return result, nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T for %s", v, key)
+	}
+}
+
+// OnlineRead returns a FeatureData 2D array where each row corresponds to one entity key and each column to one
+// of the requested features. Rows of entities without any stored values are left nil.
+func (p *PostgresOnlineStore) OnlineRead(ctx context.Context, entityKeys []*types.EntityKey, featureViewNames []string, featureNames []string) ([][]FeatureData, error) {
+	results := make([][]FeatureData, len(entityKeys))
+
+	serializedKeys := make([][]byte, len(entityKeys))
+	entityKeyToIndex := make(map[string]int)
+	for i, entityKey := range entityKeys {
+		serKey, err := serializeEntityKey(entityKey, p.config.EntityKeySerializationVersion)
+		if err != nil {
+			return nil, err
+		}
+		serializedKeys[i] = *serKey
+		entityKeyToIndex[string(*serKey)] = i
+	}
+
+	// Features are grouped per view, so each view table is queried once.
+	viewToFeatures := make(map[string][]string)
+	viewOrder := make([]string, 0)
+	featureToIndex := make(map[string]int)
+	for i, featureViewName := range featureViewNames {
+		if _, ok := viewToFeatures[featureViewName]; !ok {
+			viewOrder = append(viewOrder, featureViewName)
+		}
+		viewToFeatures[featureViewName] = append(viewToFeatures[featureViewName], featureNames[i])
+		featureToIndex[fmt.Sprintf("%s:%s", featureViewName, featureNames[i])] = i
+	}
+
+	for _, featureViewName := range viewOrder {
+		query := fmt.Sprintf(`SELECT entity_key, feature_name, value, event_ts FROM %s WHERE entity_key = ANY($1) AND feature_name = ANY($2)`,
+			pgx.Identifier{p.schema, tableId(p.project, featureViewName)}.Sanitize())
+		rows, err := p.pool.Query(ctx, query, serializedKeys, viewToFeatures[featureViewName])
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var entityKey []byte
+			var featureName string
+			var valueBytes []byte
+			var eventTs time.Time
+			if err := rows.Scan(&entityKey, &featureName, &valueBytes, &eventTs); err != nil {
+				rows.Close()
+				return nil, errors.New("error could not resolve row in query (entity key, feature name, value, event ts)")
+			}
+			var value types.Value
+			if err := proto.Unmarshal(valueBytes, &value); err != nil {
+				rows.Close()
+				return nil, errors.New("error converting parsed value to types.Value")
+			}
+			rowIdx, ok := entityKeyToIndex[string(entityKey)]
+			if !ok {
+				continue
+			}
+			featureIdx, ok := featureToIndex[fmt.Sprintf("%s:%s", featureViewName, featureName)]
+			if !ok {
+				continue
+			}
+			if results[rowIdx] == nil {
+				results[rowIdx] = newNullFeatureRow(featureViewNames, featureNames)
+			}
+			results[rowIdx][featureIdx] = FeatureData{
+				Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewName, FeatureName: featureName},
+				Timestamp: *timestamppb.New(eventTs),
+				Value:     types.Value{Val: value.Val},
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// OnlineWrite upserts the feature values of rows into the table of featureViewName in a single batch.
+func (p *PostgresOnlineStore) OnlineWrite(ctx context.Context, featureViewName string, rows []*FeatureRow) error {
+	query := fmt.Sprintf(`INSERT INTO %s (entity_key, feature_name, value, event_ts, created_ts) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (entity_key, feature_name) DO UPDATE SET
+		value = EXCLUDED.value, event_ts = EXCLUDED.event_ts, created_ts = EXCLUDED.created_ts`,
+		pgx.Identifier{p.schema, tableId(p.project, featureViewName)}.Sanitize())
+
+	batch := &pgx.Batch{}
+	for _, row := range rows {
+		serKey, err := serializeEntityKey(row.EntityKey, p.config.EntityKeySerializationVersion)
+		if err != nil {
+			return err
+		}
+		var createdTs *time.Time
+		if !row.CreatedTimestamp.IsZero() {
+			createdTs = &row.CreatedTimestamp
+		}
+		for featureName, value := range row.Values {
+			valueBytes, err := proto.Marshal(value)
+			if err != nil {
+				return err
+			}
+			batch.Queue(query, *serKey, featureName, valueBytes, row.EventTimestamp, createdTs)
+		}
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	return p.pool.SendBatch(ctx, batch).Close()
+}
+
+// newNullFeatureRow returns a row in which every requested feature is set to a null value, so that features
+// missing for an otherwise present entity are reported as NOT_FOUND.
+func newNullFeatureRow(featureViewNames []string, featureNames []string) []FeatureData {
+	row := make([]FeatureData, len(featureNames))
+	for i := range featureNames {
+		row[i] = FeatureData{
+			Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewNames[i], FeatureName: featureNames[i]},
+			Value:     types.Value{Val: &types.Value_NullVal{NullVal: types.Null_NULL}},
+		}
+	}
+	return row
+}
+
+func (p *PostgresOnlineStore) Destruct() {
+	p.pool.Close()
+}
@@ -0,0 +1,197 @@
+package onlinestore
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPostgresOnlineStore(t *testing.T) {
+	var config = map[string]interface{}{
+		"type":     "postgres",
+		"host":     "localhost",
+		"database": "feast",
+		"user":     "feast",
+		"password": "secret",
+	}
+	rc := &registry.RepoConfig{
+		OnlineStore:                   config,
+		EntityKeySerializationVersion: 2,
+	}
+	store, err := NewPostgresOnlineStore("test", rc, config)
+	assert.Nil(t, err)
+	defer store.Destruct()
+	var poolConfig = store.pool.Config()
+	assert.Equal(t, "localhost", poolConfig.ConnConfig.Host)
+	assert.Equal(t, uint16(5432), poolConfig.ConnConfig.Port)
+	assert.Equal(t, "feast", poolConfig.ConnConfig.Database)
+	assert.Equal(t, "feast", poolConfig.ConnConfig.User)
+	assert.Equal(t, "secret", poolConfig.ConnConfig.Password)
+	assert.Equal(t, int32(1), poolConfig.MinConns)
+	assert.Equal(t, int32(10), poolConfig.MaxConns)
+	assert.Equal(t, "public", store.schema)
+}
+
+func TestNewPostgresOnlineStoreWithPoolAndSchema(t *testing.T) {
+	var config = map[string]interface{}{
+		"type":      "postgres",
+		"host":      "db.example.com",
+		"port":      float64(6543),
+		"database":  "feast",
+		"db_schema": "features",
+		"user":      "feast",
+		"password":  "it's a secret",
+		"min_conn":  float64(2),
+		"max_conn":  float64(20),
+		"sslmode":   "disable",
+	}
+	rc := &registry.RepoConfig{
+		OnlineStore:                   config,
+		EntityKeySerializationVersion: 2,
+	}
+	store, err := NewPostgresOnlineStore("test", rc, config)
+	assert.Nil(t, err)
+	defer store.Destruct()
+	var poolConfig = store.pool.Config()
+	assert.Equal(t, "db.example.com", poolConfig.ConnConfig.Host)
+	assert.Equal(t, uint16(6543), poolConfig.ConnConfig.Port)
+	assert.Equal(t, "it's a secret", poolConfig.ConnConfig.Password)
+	assert.Equal(t, int32(2), poolConfig.MinConns)
+	assert.Equal(t, int32(20), poolConfig.MaxConns)
+	assert.Nil(t, poolConfig.ConnConfig.TLSConfig)
+	assert.Equal(t, "features", store.schema)
+	assert.Equal(t, "features", poolConfig.ConnConfig.RuntimeParams["search_path"])
+}
+
+func TestGetPostgresPoolConfigWithKeepalivesIdle(t *testing.T) {
+	var config = map[string]interface{}{
+		"type":            "postgres",
+		"host":            "localhost",
+		"database":        "feast",
+		"user":            "feast",
+		"keepalives_idle": float64(30),
+	}
+	poolConfig, err := getPostgresPoolConfig(config)
+	require.Nil(t, err)
+	assert.NotContains(t, poolConfig.ConnConfig.RuntimeParams, "keepalives_idle")
+	assert.NotNil(t, poolConfig.ConnConfig.DialFunc)
+}
+
+func TestNewPostgresOnlineStoreMissingHost(t *testing.T) {
+	var config = map[string]interface{}{
+		"type":     "postgres",
+		"database": "feast",
+		"user":     "feast",
+	}
+	rc := &registry.RepoConfig{
+		OnlineStore:                   config,
+		EntityKeySerializationVersion: 2,
+	}
+	_, err := NewPostgresOnlineStore("test", rc, config)
+	assert.ErrorContains(t, err, "missing required option host")
+}
+
+// TestPostgresOnlineStore runs against a local Postgres, e.g. docker run -p 5432:5432 -e POSTGRES_PASSWORD=secret
+// postgres, when FEAST_POSTGRES_DSN is set to its connection string. The table is created the way the Python
+// PostgreSQLOnlineStore creates it.
+func TestPostgresOnlineStore(t *testing.T) {
+	dsn := os.Getenv("FEAST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("FEAST_POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, dsn)
+	require.Nil(t, err)
+	defer conn.Close(ctx)
+	schema := fmt.Sprintf("feast_test_%d", time.Now().UnixNano())
+	_, err = conn.Exec(ctx, "CREATE SCHEMA "+schema)
+	require.Nil(t, err)
+	t.Cleanup(func() { _, _ = conn.Exec(context.Background(), fmt.Sprintf("DROP SCHEMA %s CASCADE", schema)) })
+
+	table := pgx.Identifier{schema, tableId("feature_repo", "driver_stats")}.Sanitize()
+	_, err = conn.Exec(ctx, fmt.Sprintf(`CREATE TABLE %s (
+		entity_key BYTEA,
+		feature_name TEXT,
+		value BYTEA,
+		vector_value BYTEA NULL,
+		event_ts TIMESTAMPTZ,
+		created_ts TIMESTAMPTZ,
+		PRIMARY KEY(entity_key, feature_name)
+	)`, table))
+	require.Nil(t, err)
+
+	// A row of driver 1001 written by the Python SDK, with the key from
+	// serialize_entity_key(EntityKey(join_keys=["driver_id"], entity_values=[Value(int64_val=1001)]), 2)
+	pythonKey, err := hex.DecodeString("020000006472697665725f69640400000008000000e903000000000000")
+	require.Nil(t, err)
+	pythonValue, err := proto.Marshal(&types.Value{Val: &types.Value_Int64Val{Int64Val: 10010}})
+	require.Nil(t, err)
+	pythonTs := time.Unix(1704160000, 0)
+	_, err = conn.Exec(ctx, fmt.Sprintf("INSERT INTO %s (entity_key, feature_name, value, event_ts, created_ts) VALUES ($1, 'trips', $2, $3, $3)", table),
+		pythonKey, pythonValue, pythonTs)
+	require.Nil(t, err)
+
+	connConfig := conn.Config()
+	config := map[string]interface{}{
+		"type":      "postgres",
+		"host":      connConfig.Host,
+		"port":      float64(connConfig.Port),
+		"database":  connConfig.Database,
+		"db_schema": schema,
+		"user":      connConfig.User,
+		"password":  connConfig.Password,
+	}
+	if connConfig.TLSConfig == nil {
+		config["sslmode"] = "disable"
+	}
+	store, err := NewPostgresOnlineStore("feature_repo", &registry.RepoConfig{EntityKeySerializationVersion: 2}, config)
+	require.Nil(t, err)
+	defer store.Destruct()
+
+	eventTs := time.Unix(1704164645, 0)
+	rows := make([]*FeatureRow, 0)
+	for _, entityKey := range driverEntityKeys(1, 2) {
+		rows = append(rows, &FeatureRow{
+			EntityKey:      entityKey,
+			Values:         map[string]*types.Value{"trips": {Val: &types.Value_Int64Val{Int64Val: entityKey.EntityValues[0].GetInt64Val() * 10}}},
+			EventTimestamp: eventTs,
+		})
+	}
+	require.Nil(t, store.OnlineWrite(ctx, "driver_stats", rows))
+	// Writing an entity again replaces its values
+	updatedTs := eventTs.Add(time.Minute)
+	require.Nil(t, store.OnlineWrite(ctx, "driver_stats", []*FeatureRow{{
+		EntityKey:        driverEntityKeys(1)[0],
+		Values:           map[string]*types.Value{"trips": {Val: &types.Value_Int64Val{Int64Val: 11}}},
+		EventTimestamp:   updatedTs,
+		CreatedTimestamp: updatedTs,
+	}}))
+
+	results, err := store.OnlineRead(ctx, driverEntityKeys(2, 3, 1, 1001), []string{"driver_stats", "driver_stats"}, []string{"trips", "acc_rate"})
+	require.Nil(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, int64(20), results[0][0].Value.GetInt64Val())
+	assert.Equal(t, eventTs.Unix(), results[0][0].Timestamp.Seconds)
+	assert.Equal(t, &types.Value_NullVal{NullVal: types.Null_NULL}, results[0][1].Value.Val)
+	assert.Nil(t, results[1])
+	assert.Equal(t, int64(11), results[2][0].Value.GetInt64Val())
+	assert.Equal(t, updatedTs.Unix(), results[2][0].Timestamp.Seconds)
+	assert.Equal(t, int64(10010), results[3][0].Value.GetInt64Val())
+	assert.Equal(t, pythonTs.Unix(), results[3][0].Timestamp.Seconds)
+
+	var count int
+	require.Nil(t, conn.QueryRow(ctx, "SELECT COUNT(*) FROM "+table).Scan(&count))
+	assert.Equal(t, 3, count)
+}
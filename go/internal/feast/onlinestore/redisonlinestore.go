@@ -1,21 +1,27 @@
 package onlinestore
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/binary"
 	"errors"
 	"fmt"
-	//"os"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
 	"github.com/feast-dev/feast/go/internal/feast/registry"
 	//"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/spaolacci/murmur3"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -28,8 +34,18 @@ import (
 type redisType int
 
 const (
-	redisNode    redisType = 0
-	redisCluster redisType = 1
+	redisNode     redisType = 0
+	redisCluster  redisType = 1
+	redisSentinel redisType = 2
+)
+
+const (
+	// Entity keys read with one pipeline unless read_batch_size is set
+	defaultReadBatchSize = 500
+	// Batches of a read sent at the same time unless read_batch_concurrency is set
+	defaultReadBatchConcurrency = 4
+	// Number of hash slots of a Redis cluster
+	redisClusterSlots = 16384
 )
 
 type RedisOnlineStore struct {
@@ -38,29 +54,47 @@ type RedisOnlineStore struct {
 	// TODO (woop): Should we remove project as state that is tracked at the store level?
 	project string
 
-	// Redis database type, either a single node server (RedisType.Redis) or a cluster (RedisType.RedisCluster)
+	// Redis database type, either a single node server (RedisType.Redis), a cluster (RedisType.RedisCluster)
+	// or a server monitored by Redis Sentinel (RedisType.RedisSentinel)
 	t redisType
 
-	// Redis client connector
+	// Redis client connector, also used for the master or replicas found through Sentinel
 	client *redis.Client
 
+	// Client of the master found through Sentinel when client reads from replicas (read_from_replicas),
+	// which writes go to since replicas are read-only
+	masterClient *redis.Client
+
 	// Redis cluster client connector
 	clusterClient *redis.ClusterClient
 
+	// Expiration applied to keys on write (key_ttl_seconds), zero if keys never expire
+	keyTtl time.Duration
+
+	// Maximum number of entity keys read with one pipeline (read_batch_size)
+	readBatchSize int
+
+	// Maximum number of batches of a read that are sent at the same time (read_batch_concurrency)
+	readBatchConcurrency int
+
+	// Timeout of each batch (read_batch_timeout_ms), zero if batches only end with the request
+	readBatchTimeout time.Duration
+
+	// Whether reads return the entities of the successful batches when other batches fail (allow_partial_reads).
+	// The entities of failed batches are then returned as not found.
+	allowPartialReads bool
+
 	config *registry.RepoConfig
 }
 
 func NewRedisOnlineStore(project string, config *registry.RepoConfig, onlineStoreConfig map[string]interface{}) (*RedisOnlineStore, error) {
 	store := RedisOnlineStore{
-		project: project,
-		config:  config,
+		project:              project,
+		config:               config,
+		readBatchSize:        defaultReadBatchSize,
+		readBatchConcurrency: defaultReadBatchConcurrency,
 	}
 
-	var address []string
-	var password string
-	var tlsConfig *tls.Config
-	var db int // Default to 0
-
 	// Parse redis_type and write it into conf.redisStoreType
 	redisStoreType, err := getRedisType(onlineStoreConfig)
 	if err != nil {
@@ -68,42 +102,38 @@ func NewRedisOnlineStore(project string, config *registry.RepoConfig, onlineStor
 	}
 	store.t = redisStoreType
 
-	// Parse connection_string and write it into conf.address, conf.password, and conf.ssl
+	// Parse connection_string into the addresses and connection options
 	redisConnJson, ok := onlineStoreConfig["connection_string"]
 	if !ok {
 		// Default to "localhost:6379"
 		redisConnJson = "localhost:6379"
 	}
-	if redisConnStr, ok := redisConnJson.(string); !ok {
+	redisConnStr, ok := redisConnJson.(string)
+	if !ok {
 		return nil, fmt.Errorf("failed to convert connection_string to string: %+v", redisConnJson)
-	} else {
-		parts := strings.Split(redisConnStr, ",")
-		for _, part := range parts {
-			if strings.Contains(part, ":") {
-				address = append(address, part)
-			} else if strings.Contains(part, "=") {
-				kv := strings.SplitN(part, "=", 2)
-				if kv[0] == "password" {
-					password = kv[1]
-				} else if kv[0] == "ssl" {
-					result, err := strconv.ParseBool(kv[1])
-					if err != nil {
-						return nil, err
-					} else if result {
-						tlsConfig = &tls.Config{}
-					}
-				} else if kv[0] == "db" {
-					db, err = strconv.Atoi(kv[1])
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, fmt.Errorf("unrecognized option in connection_string: %s. Must be one of 'password', 'ssl'", kv[0])
-				}
-			} else {
-				return nil, fmt.Errorf("unable to parse a part of connection_string: %s. Must contain either ':' (addresses) or '=' (options", part)
-			}
+	}
+	conn, err := parseRedisConnectionString(redisConnStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(conn.addresses) == 0 {
+		return nil, fmt.Errorf("connection_string has no addresses: %s", redisConnStr)
+	}
+	tlsConfig, err := conn.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if keyTtlJson, ok := onlineStoreConfig["key_ttl_seconds"]; ok && keyTtlJson != nil {
+		keyTtlSeconds, err := getIntConfigValue("key_ttl_seconds", keyTtlJson)
+		if err != nil {
+			return nil, err
 		}
+		store.keyTtl = time.Duration(keyTtlSeconds) * time.Second
+	}
+
+	if err := store.parseReadBatchOptions(onlineStoreConfig); err != nil {
+		return nil, err
 	}
 
 	// Metrics are not showing up when the service name is set to DD_SERVICE
@@ -113,32 +143,232 @@ func NewRedisOnlineStore(project string, config *registry.RepoConfig, onlineStor
 	//}
 
 	if redisStoreType == redisNode {
-		log.Info().Msgf("Using Redis: %s", address[0])
+		log.Info().Msgf("Using Redis: %s", conn.addresses[0])
 		store.client = redis.NewClient(&redis.Options{
-			Addr:      address[0],
-			Password:  password,
-			DB:        db,
-			TLSConfig: tlsConfig,
+			Addr:         conn.addresses[0],
+			Username:     conn.username,
+			Password:     conn.password,
+			DB:           conn.db,
+			TLSConfig:    tlsConfig,
+			PoolSize:     conn.poolSize,
+			DialTimeout:  conn.dialTimeout,
+			ReadTimeout:  conn.readTimeout,
+			WriteTimeout: conn.readTimeout,
 		})
 		//if strings.ToLower(os.Getenv("ENABLE_DATADOG_REDIS_TRACING")) == "true" {
 		//	redistrace.WrapClient(store.client, redistrace.WithServiceName(redisTraceServiceName))
 		//}
 	} else if redisStoreType == redisCluster {
-		log.Info().Msgf("Using Redis Cluster: %s", address)
+		log.Info().Msgf("Using Redis Cluster: %s", conn.addresses)
 		store.clusterClient = redis.NewClusterClient(&redis.ClusterOptions{
-			Addrs:     address,
-			Password:  password,
-			TLSConfig: tlsConfig,
-			ReadOnly:  true,
+			Addrs:        conn.addresses,
+			Username:     conn.username,
+			Password:     conn.password,
+			TLSConfig:    tlsConfig,
+			PoolSize:     conn.poolSize,
+			DialTimeout:  conn.dialTimeout,
+			ReadTimeout:  conn.readTimeout,
+			WriteTimeout: conn.readTimeout,
+			// Reads are served by replicas unless read_from_replicas is disabled
+			ReadOnly: conn.readFromReplicas == nil || *conn.readFromReplicas,
 		})
 		//if strings.ToLower(os.Getenv("ENABLE_DATADOG_REDIS_TRACING")) == "true" {
 		//	redistrace.WrapClient(store.clusterClient, redistrace.WithServiceName(redisTraceServiceName))
 		//}
+	} else if redisStoreType == redisSentinel {
+		masterName := "mymaster"
+		if masterNameJson, ok := onlineStoreConfig["sentinel_master"]; ok {
+			if masterName, ok = masterNameJson.(string); !ok {
+				return nil, fmt.Errorf("failed to convert sentinel_master to string: %+v", masterNameJson)
+			}
+		}
+		log.Info().Msgf("Using Redis Sentinel: %s, master %s", conn.addresses, masterName)
+		failoverOptions := redis.FailoverOptions{
+			MasterName:    masterName,
+			SentinelAddrs: conn.addresses,
+			// Sentinels are assumed to require the same credentials as the servers they monitor
+			SentinelUsername: conn.username,
+			SentinelPassword: conn.password,
+			Username:         conn.username,
+			Password:         conn.password,
+			DB:               conn.db,
+			TLSConfig:        tlsConfig,
+			PoolSize:         conn.poolSize,
+			DialTimeout:      conn.dialTimeout,
+			ReadTimeout:      conn.readTimeout,
+			WriteTimeout:     conn.readTimeout,
+		}
+		if conn.readFromReplicas != nil && *conn.readFromReplicas {
+			// Replicas reject writes, so writes keep going to the master through a client of their own.
+			// The clients keep a reference to their options, which must not be shared.
+			masterOptions := failoverOptions
+			store.masterClient = redis.NewFailoverClient(&masterOptions)
+			failoverOptions.ReplicaOnly = true
+		}
+		store.client = redis.NewFailoverClient(&failoverOptions)
 	}
 
 	return &store, nil
 }
 
+func (r *RedisOnlineStore) parseReadBatchOptions(onlineStoreConfig map[string]interface{}) error {
+	if v, ok := onlineStoreConfig["read_batch_size"]; ok && v != nil {
+		batchSize, err := getIntConfigValue("read_batch_size", v)
+		if err != nil {
+			return err
+		}
+		if batchSize < 1 {
+			return fmt.Errorf("read_batch_size must be at least 1, got %d", batchSize)
+		}
+		r.readBatchSize = int(batchSize)
+	}
+	if v, ok := onlineStoreConfig["read_batch_concurrency"]; ok && v != nil {
+		concurrency, err := getIntConfigValue("read_batch_concurrency", v)
+		if err != nil {
+			return err
+		}
+		if concurrency < 1 {
+			return fmt.Errorf("read_batch_concurrency must be at least 1, got %d", concurrency)
+		}
+		r.readBatchConcurrency = int(concurrency)
+	}
+	if v, ok := onlineStoreConfig["read_batch_timeout_ms"]; ok && v != nil {
+		timeoutMs, err := getIntConfigValue("read_batch_timeout_ms", v)
+		if err != nil {
+			return err
+		}
+		r.readBatchTimeout = time.Duration(timeoutMs) * time.Millisecond
+	}
+	if v, ok := onlineStoreConfig["allow_partial_reads"]; ok && v != nil {
+		allowPartialReads, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("failed to convert allow_partial_reads to bool: %+v", v)
+		}
+		r.allowPartialReads = allowPartialReads
+	}
+	return nil
+}
+
+// redisConnectionOptions are the addresses and options of a connection_string. The options have the names of
+// the redis-py client arguments, like in the Python Redis online store.
+type redisConnectionOptions struct {
+	// Addresses of the server, the cluster nodes or the sentinels
+	addresses []string
+	username  string
+	password  string
+	db        int
+	ssl       bool
+	// PEM files of the CA certificates to verify the server with, and of the client certificate and key
+	sslCaCerts  string
+	sslCertFile string
+	sslKeyFile  string
+	// Skips verifying the server certificate if "none"
+	sslCertReqs      string
+	poolSize         int
+	dialTimeout      time.Duration
+	readTimeout      time.Duration
+	readFromReplicas *bool
+}
+
+func parseRedisConnectionString(connectionString string) (*redisConnectionOptions, error) {
+	conn := &redisConnectionOptions{}
+	for _, part := range strings.Split(connectionString, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) == 1 {
+			if !strings.Contains(part, ":") {
+				return nil, fmt.Errorf("unable to parse a part of connection_string: %s. Must contain either ':' (addresses) or '=' (options", part)
+			}
+			conn.addresses = append(conn.addresses, part)
+			continue
+		}
+		key, value := kv[0], kv[1]
+		var err error
+		switch key {
+		case "username":
+			conn.username = value
+		case "password":
+			conn.password = value
+		case "db":
+			conn.db, err = strconv.Atoi(value)
+		case "ssl":
+			conn.ssl, err = strconv.ParseBool(value)
+		case "ssl_ca_certs":
+			conn.sslCaCerts = value
+		case "ssl_certfile":
+			conn.sslCertFile = value
+		case "ssl_keyfile":
+			conn.sslKeyFile = value
+		case "ssl_cert_reqs":
+			conn.sslCertReqs = strings.ToLower(value)
+		case "max_connections":
+			conn.poolSize, err = strconv.Atoi(value)
+		case "socket_connect_timeout":
+			conn.dialTimeout, err = parseSeconds(value)
+		case "socket_timeout":
+			conn.readTimeout, err = parseSeconds(value)
+		case "read_from_replicas":
+			var readFromReplicas bool
+			readFromReplicas, err = strconv.ParseBool(value)
+			conn.readFromReplicas = &readFromReplicas
+		case "skip_full_coverage_check":
+			// Only relevant to redis-py's cluster client
+		default:
+			return nil, fmt.Errorf("unrecognized option in connection_string: %s. Must be one of 'username', 'password', 'db', 'ssl', "+
+				"'ssl_ca_certs', 'ssl_certfile', 'ssl_keyfile', 'ssl_cert_reqs', 'max_connections', 'socket_connect_timeout', "+
+				"'socket_timeout', 'read_from_replicas'", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s in connection_string: %w", key, err)
+		}
+	}
+	return conn, nil
+}
+
+// parseSeconds parses a number of seconds like "0.5".
+func parseSeconds(value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// tlsConfig returns the TLS configuration of the connection, or nil if it doesn't use TLS. Setting any of the
+// certificate options enables TLS.
+func (c *redisConnectionOptions) tlsConfig() (*tls.Config, error) {
+	if !c.ssl && c.sslCaCerts == "" && c.sslCertFile == "" {
+		return nil, nil
+	}
+	config := &tls.Config{InsecureSkipVerify: c.sslCertReqs == "none"}
+	if c.sslCaCerts != "" {
+		caCerts, err := os.ReadFile(c.sslCaCerts)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("no certificates found in ssl_ca_certs %s", c.sslCaCerts)
+		}
+	}
+	if c.sslCertFile != "" {
+		keyFile := c.sslKeyFile
+		if keyFile == "" {
+			// Like in Python, the key can be in the certificate file
+			keyFile = c.sslCertFile
+		}
+		certificate, err := tls.LoadX509KeyPair(c.sslCertFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
 func getRedisType(onlineStoreConfig map[string]interface{}) (redisType, error) {
 	var t redisType
 
@@ -153,8 +383,10 @@ func getRedisType(onlineStoreConfig map[string]interface{}) (redisType, error) {
 			t = redisNode
 		} else if redisTypeStr == "redis_cluster" {
 			t = redisCluster
+		} else if redisTypeStr == "redis_sentinel" {
+			t = redisSentinel
 		} else {
-			return -1, fmt.Errorf("failed to convert redis_type to enum: %s. Must be one of 'redis', 'redis_cluster'", redisTypeStr)
+			return -1, fmt.Errorf("failed to convert redis_type to enum: %s. Must be one of 'redis', 'redis_cluster', 'redis_sentinel'", redisTypeStr)
 		}
 	}
 	return t, nil
@@ -211,6 +443,47 @@ func (r *RedisOnlineStore) buildRedisKeys(entityKeys []*types.EntityKey) ([]*[]b
 	return redisKeys, redisKeyToEntityIndex, nil
 }
 
+// BatchReadError reports the batches of an online read that failed.
+type BatchReadError struct {
+	FailedBatches    int
+	Batches          int
+	FailedEntityKeys int
+	// Error of the first failed batch
+	Err error
+}
+
+func (e *BatchReadError) Error() string {
+	return fmt.Sprintf("failed to read %d of %d batches (%d entity keys) from redis: %v", e.FailedBatches, e.Batches, e.FailedEntityKeys, e.Err)
+}
+
+func (e *BatchReadError) Unwrap() error {
+	return e.Err
+}
+
+// readBatches splits the entity indices of the keys into batches of at most readBatchSize keys. In cluster mode
+// keys are ordered by hash slot first, so that the keys of a batch are served by as few nodes as possible.
+func (r *RedisOnlineStore) readBatches(redisKeys []*[]byte) [][]int {
+	entityIndices := make([]int, len(redisKeys))
+	for i := range entityIndices {
+		entityIndices[i] = i
+	}
+	if r.t == redisCluster {
+		slots := make([]uint16, len(redisKeys))
+		for i, redisKey := range redisKeys {
+			slots[i] = clusterSlot(*redisKey)
+		}
+		sort.SliceStable(entityIndices, func(a, b int) bool {
+			return slots[entityIndices[a]] < slots[entityIndices[b]]
+		})
+	}
+	batchSize := max(r.readBatchSize, 1)
+	batches := make([][]int, 0, (len(entityIndices)+batchSize-1)/batchSize)
+	for start := 0; start < len(entityIndices); start += batchSize {
+		batches = append(batches, entityIndices[start:min(start+batchSize, len(entityIndices))])
+	}
+	return batches
+}
+
 func (r *RedisOnlineStore) OnlineRead(ctx context.Context, entityKeys []*types.EntityKey, featureViewNames []string, featureNames []string) ([][]FeatureData, error) {
 	//span, _ := tracer.StartSpanFromContext(ctx, "redis.OnlineRead")
 	//defer span.Finish()
@@ -218,112 +491,276 @@ func (r *RedisOnlineStore) OnlineRead(ctx context.Context, entityKeys []*types.E
 	featureCount := len(featureNames)
 	featureViewIndices, indicesFeatureView, index := r.buildFeatureViewIndices(featureViewNames, featureNames)
 	hsetKeys, featureNamesWithTimeStamps := r.buildRedisHashSetKeys(featureViewNames, featureNames, indicesFeatureView, index)
-	redisKeys, redisKeyToEntityIndex, err := r.buildRedisKeys(entityKeys)
+	redisKeys, _, err := r.buildRedisKeys(entityKeys)
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([][]FeatureData, len(entityKeys))
-	commands := map[string]*redis.SliceCmd{}
-
-	if r.t == redisNode {
-		pipe := r.client.Pipeline()
-		for _, redisKey := range redisKeys {
-			keyString := string(*redisKey)
-			commands[keyString] = pipe.HMGet(ctx, keyString, hsetKeys...)
+	readBatch := func(ctx context.Context, batch []int) error {
+		if r.readBatchTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, r.readBatchTimeout)
+			defer cancel()
 		}
-		_, err = pipe.Exec(ctx)
-		if err != nil {
-			return nil, err
+		pipe := r.pipeline()
+		commands := make([]*redis.SliceCmd, len(batch))
+		for i, entityIndex := range batch {
+			commands[i] = pipe.HMGet(ctx, string(*redisKeys[entityIndex]), hsetKeys...)
 		}
-	} else if r.t == redisCluster {
-		pipe := r.clusterClient.Pipeline()
-		for _, redisKey := range redisKeys {
-			keyString := string(*redisKey)
-			commands[keyString] = pipe.HMGet(ctx, keyString, hsetKeys...)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
 		}
-		_, err = pipe.Exec(ctx)
-		if err != nil {
-			return nil, err
+		for i, entityIndex := range batch {
+			res, err := commands[i].Result()
+			if err != nil {
+				return err
+			}
+			features, err := parseFeatureData(res, featureCount, featureViewNames, featureNamesWithTimeStamps, featureViewIndices)
+			if err != nil {
+				return err
+			}
+			results[entityIndex] = features
 		}
+		return nil
 	}
-	var entityIndex int
-	var resContainsNonNil bool
-	for redisKey, values := range commands {
 
-		entityIndex = redisKeyToEntityIndex[redisKey]
-		resContainsNonNil = false
+	batches := r.readBatches(redisKeys)
+	if len(batches) == 1 {
+		if err := readBatch(ctx, batches[0]); err != nil {
+			return r.batchReadFailed(results, []error{err}, [][]int{batches[0]}, 1)
+		}
+		return results, nil
+	}
 
-		results[entityIndex] = make([]FeatureData, featureCount)
-		res, err := values.Result()
-		if err != nil {
-			return nil, err
+	var mu sync.Mutex
+	var batchErrors []error
+	var failedBatches [][]int
+	group, groupCtx := errgroup.WithContext(ctx)
+	if r.allowPartialReads {
+		// Failed batches must not cancel the others
+		group = &errgroup.Group{}
+		groupCtx = ctx
+	}
+	group.SetLimit(max(r.readBatchConcurrency, 1))
+	for _, batch := range batches {
+		group.Go(func() error {
+			if err := readBatch(groupCtx, batch); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				batchErrors = append(batchErrors, err)
+				failedBatches = append(failedBatches, batch)
+				return err
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil || len(batchErrors) > 0 {
+		return r.batchReadFailed(results, batchErrors, failedBatches, len(batches))
+	}
+	return results, nil
+}
+
+// batchReadFailed reports the failed batches of a read. With partial reads allowed, the entities of the failed
+// batches are returned as not found as long as some batch succeeded, otherwise the read fails.
+func (r *RedisOnlineStore) batchReadFailed(results [][]FeatureData, batchErrors []error, failedBatches [][]int, batches int) ([][]FeatureData, error) {
+	failedEntityKeys := 0
+	for _, batch := range failedBatches {
+		failedEntityKeys += len(batch)
+	}
+	err := &BatchReadError{FailedBatches: len(failedBatches), Batches: batches, FailedEntityKeys: failedEntityKeys, Err: batchErrors[0]}
+	metrics.OnlineReadFailedBatches.WithLabelValues("redis").Add(float64(len(failedBatches)))
+	if !r.allowPartialReads || len(failedBatches) == batches {
+		return nil, err
+	}
+	log.Warn().Err(err).Msg("Returning partial results of online read")
+	for _, batch := range failedBatches {
+		for _, entityIndex := range batch {
+			results[entityIndex] = nil
+		}
+	}
+	return results, nil
+}
+
+// parseFeatureData converts the values of an entity's HMGET into feature data. It returns nil if the entity has
+// none of the features.
+func parseFeatureData(res []interface{}, featureCount int, featureViewNames []string, featureNamesWithTimeStamps []string, featureViewIndices map[string]int) ([]FeatureData, error) {
+	features := make([]FeatureData, featureCount)
+	resContainsNonNil := false
+	var timeStamp timestamppb.Timestamp
+
+	for featureIndex, resString := range res {
+		if featureIndex == featureCount {
+			break
 		}
 
-		var timeStamp timestamppb.Timestamp
+		if resString == nil {
+			// TODO (Ly): Can there be nil result within each feature or they will all be returned as string proto of types.Value_NullVal proto?
+			featureName := featureNamesWithTimeStamps[featureIndex]
+			featureViewName := featureViewNames[featureIndex]
+			timeStampIndex := featureViewIndices[featureViewName]
+			timeStampInterface := res[timeStampIndex]
+			if timeStampInterface != nil {
+				if timeStampString, ok := timeStampInterface.(string); !ok {
+					return nil, errors.New("error parsing value from redis")
+				} else {
+					if err := proto.Unmarshal([]byte(timeStampString), &timeStamp); err != nil {
+						return nil, errors.New("error converting parsed redis value to timestamppb.Timestamp")
+					}
+				}
+			}
 
-		for featureIndex, resString := range res {
-			if featureIndex == featureCount {
-				break
+			features[featureIndex] = FeatureData{Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewName, FeatureName: featureName},
+				Timestamp: timestamppb.Timestamp{Seconds: timeStamp.Seconds, Nanos: timeStamp.Nanos},
+				Value:     types.Value{Val: &types.Value_NullVal{NullVal: types.Null_NULL}},
 			}
 
-			if resString == nil {
-				// TODO (Ly): Can there be nil result within each feature or they will all be returned as string proto of types.Value_NullVal proto?
+		} else if valueString, ok := resString.(string); !ok {
+			return nil, errors.New("error parsing Value from redis")
+		} else {
+			resContainsNonNil = true
+			var value types.Value
+			if err := proto.Unmarshal([]byte(valueString), &value); err != nil {
+				return nil, errors.New("error converting parsed redis Value to types.Value")
+			} else {
 				featureName := featureNamesWithTimeStamps[featureIndex]
 				featureViewName := featureViewNames[featureIndex]
 				timeStampIndex := featureViewIndices[featureViewName]
 				timeStampInterface := res[timeStampIndex]
 				if timeStampInterface != nil {
 					if timeStampString, ok := timeStampInterface.(string); !ok {
-						return nil, errors.New("error parsing value from redis")
+						return nil, errors.New("error parsing Value from redis")
 					} else {
 						if err := proto.Unmarshal([]byte(timeStampString), &timeStamp); err != nil {
-							return nil, errors.New("error converting parsed redis value to timestamppb.Timestamp")
+							return nil, errors.New("error converting parsed redis Value to timestamppb.Timestamp")
 						}
 					}
 				}
-
-				results[entityIndex][featureIndex] = FeatureData{Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewName, FeatureName: featureName},
+				features[featureIndex] = FeatureData{Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewName, FeatureName: featureName},
 					Timestamp: timestamppb.Timestamp{Seconds: timeStamp.Seconds, Nanos: timeStamp.Nanos},
-					Value:     types.Value{Val: &types.Value_NullVal{NullVal: types.Null_NULL}},
+					Value:     types.Value{Val: value.Val},
 				}
+			}
+		}
+	}
 
-			} else if valueString, ok := resString.(string); !ok {
-				return nil, errors.New("error parsing Value from redis")
-			} else {
-				resContainsNonNil = true
-				var value types.Value
-				if err := proto.Unmarshal([]byte(valueString), &value); err != nil {
-					return nil, errors.New("error converting parsed redis Value to types.Value")
-				} else {
-					featureName := featureNamesWithTimeStamps[featureIndex]
-					featureViewName := featureViewNames[featureIndex]
-					timeStampIndex := featureViewIndices[featureViewName]
-					timeStampInterface := res[timeStampIndex]
-					if timeStampInterface != nil {
-						if timeStampString, ok := timeStampInterface.(string); !ok {
-							return nil, errors.New("error parsing Value from redis")
-						} else {
-							if err := proto.Unmarshal([]byte(timeStampString), &timeStamp); err != nil {
-								return nil, errors.New("error converting parsed redis Value to timestamppb.Timestamp")
-							}
-						}
-					}
-					results[entityIndex][featureIndex] = FeatureData{Reference: serving.FeatureReferenceV2{FeatureViewName: featureViewName, FeatureName: featureName},
-						Timestamp: timestamppb.Timestamp{Seconds: timeStamp.Seconds, Nanos: timeStamp.Nanos},
-						Value:     types.Value{Val: value.Val},
-					}
-				}
+	if !resContainsNonNil {
+		return nil, nil
+	}
+	return features, nil
+}
+
+// OnlineWrite stores every row in the hash of its entity key. Like in the Python Redis online store, rows that are
+// not newer than the values already stored for the feature view are skipped, and timestamps are stored in seconds.
+func (r *RedisOnlineStore) OnlineWrite(ctx context.Context, featureViewName string, rows []*FeatureRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	tsKey := fmt.Sprintf("_ts:%s", featureViewName)
+	entityKeys := make([]*types.EntityKey, len(rows))
+	for i, row := range rows {
+		entityKeys[i] = row.EntityKey
+	}
+	redisKeys, _, err := r.buildRedisKeys(entityKeys)
+	if err != nil {
+		return err
+	}
+
+	pipe := r.writePipeline()
+	prevTimestamps := make([]*redis.SliceCmd, len(rows))
+	for i, redisKey := range redisKeys {
+		prevTimestamps[i] = pipe.HMGet(ctx, string(*redisKey), tsKey)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	pipe = r.writePipeline()
+	for i, row := range rows {
+		eventTimeSeconds := row.EventTimestamp.Unix()
+		res, err := prevTimestamps[i].Result()
+		if err != nil {
+			return err
+		}
+		if prevTimestampString, ok := res[0].(string); ok {
+			var prevTimestamp timestamppb.Timestamp
+			if err := proto.Unmarshal([]byte(prevTimestampString), &prevTimestamp); err != nil {
+				return errors.New("error converting parsed redis value to timestamppb.Timestamp")
+			}
+			if prevTimestamp.Seconds != 0 && eventTimeSeconds <= prevTimestamp.Seconds {
+				continue
 			}
 		}
 
-		if !resContainsNonNil {
-			results[entityIndex] = nil
+		tsBytes, err := proto.Marshal(&timestamppb.Timestamp{Seconds: eventTimeSeconds})
+		if err != nil {
+			return err
+		}
+		entityHset := map[string]interface{}{tsKey: tsBytes}
+		for featureName, value := range row.Values {
+			valueBytes, err := proto.Marshal(value)
+			if err != nil {
+				return err
+			}
+			entityHset[murmur3HashKey(featureViewName, featureName)] = valueBytes
 		}
+		keyString := string(*redisKeys[i])
+		pipe.HSet(ctx, keyString, entityHset)
+		if r.keyTtl > 0 {
+			pipe.Expire(ctx, keyString, r.keyTtl)
+		}
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
 
+func (r *RedisOnlineStore) pipeline() redis.Pipeliner {
+	if r.t == redisCluster {
+		return r.clusterClient.Pipeline()
 	}
+	return r.client.Pipeline()
+}
 
-	return results, nil
+// writePipeline returns a pipeline to the master when reads are served by replicas found through Sentinel.
+func (r *RedisOnlineStore) writePipeline() redis.Pipeliner {
+	if r.masterClient != nil {
+		return r.masterClient.Pipeline()
+	}
+	return r.pipeline()
+}
+
+// clusterSlot returns the hash slot of a key in a Redis cluster: the CRC16 of the key, or of its hash tag if
+// it has one, modulo the number of slots.
+func clusterSlot(key []byte) uint16 {
+	if start := bytes.IndexByte(key, '{'); start >= 0 {
+		if end := bytes.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return crc16(key) % redisClusterSlots
+}
+
+// crc16 is the CRC16-CCITT (XMODEM) checksum that Redis cluster hashes keys with.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// murmur3HashKey returns the hash field under which a feature is stored, matching _mmh3 in the Python SDK.
+func murmur3HashKey(featureViewName string, featureName string) string {
+	byteBuffer := make([]byte, 4)
+	binary.LittleEndian.PutUint32(byteBuffer, murmur3.Sum32([]byte(fmt.Sprintf("%s:%s", featureViewName, featureName))))
+	return string(byteBuffer)
 }
 
 // Dummy destruct function to conform with plugin OnlineStore interface
@@ -1,12 +1,30 @@
 package onlinestore
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
 
 	"github.com/feast-dev/feast/go/internal/feast/registry"
 	"github.com/feast-dev/feast/go/protos/feast/types"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewRedisOnlineStore(t *testing.T) {
@@ -192,3 +210,310 @@ func TestBuildRedisKeys(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 }
+
+func TestNewRedisOnlineStoreWithKeyTtl(t *testing.T) {
+	var config = map[string]interface{}{
+		"connection_string": "redis://localhost:6379",
+		"key_ttl_seconds":   float64(3600),
+	}
+	rc := &registry.RepoConfig{
+		OnlineStore:                   config,
+		EntityKeySerializationVersion: 2,
+	}
+	store, err := NewRedisOnlineStore("test", rc, config)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Hour, store.keyTtl)
+}
+
+func TestMurmur3HashKeyMatchesReadKeys(t *testing.T) {
+	r := &RedisOnlineStore{}
+	hsetKeys, _ := r.buildRedisHashSetKeys([]string{"view1", "view1"}, []string{"feature1", "feature2"}, map[int]string{2: "view1"}, 3)
+	assert.Equal(t, hsetKeys[0], murmur3HashKey("view1", "feature1"))
+	assert.Equal(t, hsetKeys[1], murmur3HashKey("view1", "feature2"))
+}
+
+func TestNewRedisOnlineStoreWithReadBatchOptions(t *testing.T) {
+	var config = map[string]interface{}{
+		"connection_string":      "localhost:6379",
+		"read_batch_size":        float64(100),
+		"read_batch_concurrency": float64(2),
+		"read_batch_timeout_ms":  float64(250),
+		"allow_partial_reads":    true,
+	}
+	store, err := NewRedisOnlineStore("test", &registry.RepoConfig{OnlineStore: config}, config)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, store.readBatchSize)
+	assert.Equal(t, 2, store.readBatchConcurrency)
+	assert.Equal(t, 250*time.Millisecond, store.readBatchTimeout)
+	assert.True(t, store.allowPartialReads)
+
+	store, err = NewRedisOnlineStore("test", &registry.RepoConfig{}, map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultReadBatchSize, store.readBatchSize)
+	assert.Equal(t, defaultReadBatchConcurrency, store.readBatchConcurrency)
+	assert.Zero(t, store.readBatchTimeout)
+	assert.False(t, store.allowPartialReads)
+
+	_, err = NewRedisOnlineStore("test", &registry.RepoConfig{}, map[string]interface{}{"read_batch_size": float64(0)})
+	assert.Error(t, err)
+}
+
+func TestClusterSlot(t *testing.T) {
+	assert.Equal(t, uint16(0x31C3), crc16([]byte("123456789")))
+	assert.Equal(t, uint16(12182), clusterSlot([]byte("foo")))
+	assert.Equal(t, clusterSlot([]byte("{user1000}.following")), clusterSlot([]byte("{user1000}.followers")))
+	// Empty hash tags are not hash tags
+	assert.Equal(t, crc16([]byte("foo{}{bar}"))%redisClusterSlots, clusterSlot([]byte("foo{}{bar}")))
+}
+
+func TestReadBatches(t *testing.T) {
+	keys := make([]*[]byte, 5)
+	for i := range keys {
+		key := []byte(fmt.Sprintf("key%d", i))
+		keys[i] = &key
+	}
+
+	r := &RedisOnlineStore{t: redisNode, readBatchSize: 2}
+	assert.Equal(t, [][]int{{0, 1}, {2, 3}, {4}}, r.readBatches(keys))
+
+	r = &RedisOnlineStore{t: redisCluster, readBatchSize: 2}
+	batches := r.readBatches(keys)
+	assert.Len(t, batches, 3)
+	var previousSlot uint16
+	for _, batch := range batches {
+		for _, entityIndex := range batch {
+			slot := clusterSlot(*keys[entityIndex])
+			assert.GreaterOrEqual(t, slot, previousSlot)
+			previousSlot = slot
+		}
+	}
+}
+
+func newMiniredisOnlineStore(t *testing.T, config map[string]interface{}) (*RedisOnlineStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	config["connection_string"] = server.Addr()
+	store, err := NewRedisOnlineStore("test", &registry.RepoConfig{OnlineStore: config, EntityKeySerializationVersion: 2}, config)
+	require.Nil(t, err)
+	return store, server
+}
+
+func driverEntityKeys(driverIds ...int64) []*types.EntityKey {
+	entityKeys := make([]*types.EntityKey, len(driverIds))
+	for i, driverId := range driverIds {
+		entityKeys[i] = &types.EntityKey{
+			JoinKeys:     []string{"driver_id"},
+			EntityValues: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: driverId}}},
+		}
+	}
+	return entityKeys
+}
+
+func TestOnlineReadInBatches(t *testing.T) {
+	store, _ := newMiniredisOnlineStore(t, map[string]interface{}{"read_batch_size": 2, "read_batch_concurrency": 2})
+	ctx := context.Background()
+	entityKeys := driverEntityKeys(1, 2, 3, 4, 5)
+	rows := make([]*FeatureRow, 0)
+	for i, entityKey := range entityKeys[:4] {
+		rows = append(rows, &FeatureRow{
+			EntityKey:      entityKey,
+			Values:         map[string]*types.Value{"conv_rate": {Val: &types.Value_DoubleVal{DoubleVal: float64(i)}}},
+			EventTimestamp: time.Unix(1700000000, 0),
+		})
+	}
+	require.Nil(t, store.OnlineWrite(ctx, "driver_stats", rows))
+
+	results, err := store.OnlineRead(ctx, entityKeys, []string{"driver_stats"}, []string{"conv_rate"})
+	require.Nil(t, err)
+	require.Len(t, results, 5)
+	for i := 0; i < 4; i++ {
+		require.Len(t, results[i], 1)
+		assert.Equal(t, float64(i), results[i][0].Value.GetDoubleVal())
+		assert.Equal(t, int64(1700000000), results[i][0].Timestamp.Seconds)
+	}
+	assert.Nil(t, results[4])
+}
+
+func TestOnlineReadReportsFailedBatches(t *testing.T) {
+	store, server := newMiniredisOnlineStore(t, map[string]interface{}{"read_batch_size": 2})
+	server.SetError("LOADING Redis is loading the dataset in memory")
+
+	_, err := store.OnlineRead(context.Background(), driverEntityKeys(1, 2, 3), []string{"driver_stats"}, []string{"conv_rate"})
+	var batchReadError *BatchReadError
+	require.True(t, errors.As(err, &batchReadError))
+	assert.Equal(t, 2, batchReadError.Batches)
+	assert.Equal(t, 2, batchReadError.FailedBatches)
+	assert.Equal(t, 3, batchReadError.FailedEntityKeys)
+}
+
+func TestOnlineReadReturnsPartialResults(t *testing.T) {
+	store := &RedisOnlineStore{allowPartialReads: true}
+	results := [][]FeatureData{{{Value: types.Value{Val: &types.Value_Int64Val{Int64Val: 1}}}}, {{}}, {{}}}
+
+	partialResults, err := store.batchReadFailed(results, []error{errors.New("timeout")}, [][]int{{1, 2}}, 2)
+	assert.Nil(t, err)
+	assert.NotNil(t, partialResults[0])
+	assert.Nil(t, partialResults[1])
+	assert.Nil(t, partialResults[2])
+
+	store.allowPartialReads = false
+	_, err = store.batchReadFailed(results, []error{errors.New("timeout")}, [][]int{{1, 2}}, 2)
+	assert.EqualError(t, err, "failed to read 1 of 2 batches (2 entity keys) from redis: timeout")
+}
+
+func TestOnlineReadFailsWhenAllBatchesFailWithPartialReads(t *testing.T) {
+	store, server := newMiniredisOnlineStore(t, map[string]interface{}{"read_batch_size": 2, "allow_partial_reads": true})
+	server.SetError("LOADING Redis is loading the dataset in memory")
+
+	_, err := store.OnlineRead(context.Background(), driverEntityKeys(1, 2, 3), []string{"driver_stats"}, []string{"conv_rate"})
+	var batchReadError *BatchReadError
+	require.True(t, errors.As(err, &batchReadError))
+	assert.Equal(t, 2, batchReadError.FailedBatches)
+	assert.Equal(t, 2, batchReadError.Batches)
+
+	// A single batch that fails is a failure of the whole read too
+	_, err = store.OnlineRead(context.Background(), driverEntityKeys(1), []string{"driver_stats"}, []string{"conv_rate"})
+	assert.True(t, errors.As(err, &batchReadError))
+}
+
+func TestParseRedisConnectionString(t *testing.T) {
+	conn, err := parseRedisConnectionString("redis1:6379, redis2:6379,username=feast,password=a=b:c,db=2,max_connections=20," +
+		"socket_connect_timeout=0.5,socket_timeout=2,read_from_replicas=true,skip_full_coverage_check=true")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"redis1:6379", "redis2:6379"}, conn.addresses)
+	assert.Equal(t, "feast", conn.username)
+	assert.Equal(t, "a=b:c", conn.password)
+	assert.Equal(t, 2, conn.db)
+	assert.Equal(t, 20, conn.poolSize)
+	assert.Equal(t, 500*time.Millisecond, conn.dialTimeout)
+	assert.Equal(t, 2*time.Second, conn.readTimeout)
+	assert.True(t, *conn.readFromReplicas)
+
+	_, err = parseRedisConnectionString("localhost:6379,unknown=1")
+	assert.ErrorContains(t, err, "unrecognized option in connection_string: unknown")
+	_, err = parseRedisConnectionString("localhost:6379,db=first")
+	assert.ErrorContains(t, err, "invalid value of db")
+	_, err = parseRedisConnectionString("localhost")
+	assert.Error(t, err)
+}
+
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+	certFile, keyFile := filepath.Join(dir, "redis.crt"), filepath.Join(dir, "redis.key")
+	require.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestNewRedisOnlineStoreWithTlsCertificates(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+	var config = map[string]interface{}{
+		"connection_string": fmt.Sprintf("localhost:6379,ssl_ca_certs=%s,ssl_certfile=%s,ssl_keyfile=%s", certFile, certFile, keyFile),
+	}
+	store, err := NewRedisOnlineStore("test", &registry.RepoConfig{OnlineStore: config}, config)
+	require.Nil(t, err)
+	tlsConfig := store.client.Options().TLSConfig
+	require.NotNil(t, tlsConfig)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+
+	config["connection_string"] = "localhost:6379,ssl_ca_certs=" + keyFile
+	_, err = NewRedisOnlineStore("test", &registry.RepoConfig{OnlineStore: config}, config)
+	assert.Error(t, err)
+}
+
+func TestNewRedisOnlineStoreWithClusterOptions(t *testing.T) {
+	var config = map[string]interface{}{
+		"redis_type":        "redis_cluster",
+		"connection_string": "redis1:6379,redis2:6379,username=feast,max_connections=5,read_from_replicas=false",
+	}
+	store, err := NewRedisOnlineStore("test", &registry.RepoConfig{OnlineStore: config}, config)
+	require.Nil(t, err)
+	opts := store.clusterClient.Options()
+	assert.Equal(t, []string{"redis1:6379", "redis2:6379"}, opts.Addrs)
+	assert.Equal(t, "feast", opts.Username)
+	assert.Equal(t, 5, opts.PoolSize)
+	assert.False(t, opts.ReadOnly)
+}
+
+func TestNewRedisOnlineStoreWithSentinel(t *testing.T) {
+	var config = map[string]interface{}{
+		"redis_type":        "redis_sentinel",
+		"sentinel_master":   "feast-master",
+		"connection_string": "sentinel1:26379,sentinel2:26379,password=secret,db=1,socket_timeout=1.5",
+	}
+	store, err := NewRedisOnlineStore("test", &registry.RepoConfig{OnlineStore: config}, config)
+	require.Nil(t, err)
+	assert.Equal(t, redisSentinel, store.t)
+	assert.Nil(t, store.clusterClient)
+	opts := store.client.Options()
+	assert.Equal(t, "secret", opts.Password)
+	assert.Equal(t, 1, opts.DB)
+	assert.Equal(t, 1500*time.Millisecond, opts.ReadTimeout)
+
+	config["redis_type"] = "redis_unknown"
+	_, err = NewRedisOnlineStore("test", &registry.RepoConfig{OnlineStore: config}, config)
+	assert.Error(t, err)
+}
+
+// runFakeSentinel serves the Sentinel commands go-redis uses to find the master and replicas of feast-master.
+func runFakeSentinel(t *testing.T, master *miniredis.Miniredis, replica *miniredis.Miniredis) string {
+	sentinel, err := server.NewServer("127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(sentinel.Close)
+	require.Nil(t, sentinel.Register("SENTINEL", func(c *server.Peer, cmd string, args []string) {
+		switch strings.ToLower(args[0]) {
+		case "get-master-addr-by-name":
+			c.WriteStrings([]string{master.Host(), master.Port()})
+		case "replicas", "slaves":
+			c.WriteLen(1)
+			c.WriteStrings([]string{"name", replica.Addr(), "ip", replica.Host(), "port", replica.Port(), "flags", "slave"})
+		case "sentinels":
+			c.WriteLen(0)
+		default:
+			c.WriteError("ERR unknown sentinel subcommand")
+		}
+	}))
+	return sentinel.Addr().String()
+}
+
+func TestOnlineWriteGoesToSentinelMasterWhenReadingFromReplicas(t *testing.T) {
+	master := miniredis.RunT(t)
+	// The fake replica does not replicate the master, which shows which server each command went to
+	replica := miniredis.RunT(t)
+	config := map[string]interface{}{
+		"redis_type":        "redis_sentinel",
+		"sentinel_master":   "feast-master",
+		"connection_string": runFakeSentinel(t, master, replica) + ",read_from_replicas=true",
+	}
+	store, err := NewRedisOnlineStore("test", &registry.RepoConfig{OnlineStore: config, EntityKeySerializationVersion: 2}, config)
+	require.Nil(t, err)
+	ctx := context.Background()
+	rows := []*FeatureRow{{
+		EntityKey:      driverEntityKeys(1)[0],
+		Values:         map[string]*types.Value{"conv_rate": {Val: &types.Value_DoubleVal{DoubleVal: 0.5}}},
+		EventTimestamp: time.Unix(1700000000, 0),
+	}}
+
+	require.Nil(t, store.OnlineWrite(ctx, "driver_stats", rows))
+	assert.Len(t, master.Keys(), 1)
+	assert.Empty(t, replica.Keys())
+
+	// Reads are still served by the replica
+	results, err := store.OnlineRead(ctx, driverEntityKeys(1), []string{"driver_stats"}, []string{"conv_rate"})
+	require.Nil(t, err)
+	assert.Nil(t, results[0])
+}
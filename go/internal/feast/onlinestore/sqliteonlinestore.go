@@ -121,6 +121,56 @@ func (s *SqliteOnlineStore) OnlineRead(ctx context.Context, entityKeys []*types.
 	return results, nil
 }
 
+// Upserts every feature value of rows into the table of featureViewName. Timestamps are stored as naive UTC
+// strings, which is how the Python sqlite online store writes them.
+func (s *SqliteOnlineStore) OnlineWrite(ctx context.Context, featureViewName string, rows []*FeatureRow) error {
+	db, err := s.getConnection()
+	if err != nil {
+		return err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query_string := fmt.Sprintf(`INSERT INTO %s (entity_key, feature_name, value, event_ts, created_ts)
+								VALUES (?, ?, ?, ?, ?)
+								ON CONFLICT (entity_key, feature_name) DO UPDATE SET
+								value = excluded.value, event_ts = excluded.event_ts, created_ts = excluded.created_ts`, tableId(s.project, featureViewName))
+	stmt, err := tx.PrepareContext(ctx, query_string)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		serKey, err := serializeEntityKey(row.EntityKey, s.repoConfig.EntityKeySerializationVersion)
+		if err != nil {
+			return err
+		}
+		eventTs := toSqliteTimestamp(row.EventTimestamp)
+		var createdTs interface{}
+		if !row.CreatedTimestamp.IsZero() {
+			createdTs = toSqliteTimestamp(row.CreatedTimestamp)
+		}
+		for featureName, value := range row.Values {
+			valueBytes, err := proto.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if _, err = stmt.ExecContext(ctx, *serKey, featureName, valueBytes, eventTs, createdTs); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func toSqliteTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.000000")
+}
+
 // Gets a sqlite connection and sets it to the online store and also returns a pointer to the connection.
 func (s *SqliteOnlineStore) getConnection() (*sql.DB, error) {
 	s.db_mu.Lock()
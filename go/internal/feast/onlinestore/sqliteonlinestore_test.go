@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/feast-dev/feast/go/internal/feast/registry"
 
@@ -84,3 +85,34 @@ func TestSqliteOnlineRead(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(expectedFeatureValues, returnedFeatureValues))
 	assert.True(t, reflect.DeepEqual(expectedFeatureNames, returnedFeatureNames))
 }
+
+func TestSqliteOnlineWrite(t *testing.T) {
+	dir := t.TempDir()
+	config := &registry.RepoConfig{RepoPath: dir, EntityKeySerializationVersion: 2}
+	store, err := NewSqliteOnlineStore("my_project", config, map[string]interface{}{"path": "online_store.db"})
+	assert.Nil(t, err)
+	defer store.Destruct()
+	_, err = store.db.Exec(`CREATE TABLE my_project_driver_hourly_stats (entity_key BLOB, feature_name TEXT, value BLOB,
+		vector_value BLOB, event_ts timestamp, created_ts timestamp, PRIMARY KEY(entity_key, feature_name))`)
+	assert.Nil(t, err)
+
+	entityKey := &types.EntityKey{
+		JoinKeys:     []string{"driver_id"},
+		EntityValues: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: 1001}}},
+	}
+	eventTs := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, convRate := range []float32{0.5, 0.75} {
+		err = store.OnlineWrite(context.Background(), "driver_hourly_stats", []*FeatureRow{{
+			EntityKey:      entityKey,
+			Values:         map[string]*types.Value{"conv_rate": {Val: &types.Value_FloatVal{FloatVal: convRate}}},
+			EventTimestamp: eventTs,
+		}})
+		assert.Nil(t, err)
+	}
+
+	featureData, err := store.OnlineRead(context.Background(), []*types.EntityKey{entityKey}, []string{"driver_hourly_stats"}, []string{"conv_rate"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(featureData))
+	assert.Equal(t, float32(0.75), featureData[0][0].Value.GetFloatVal())
+	assert.Equal(t, eventTs.Unix(), featureData[0][0].Timestamp.Seconds)
+}
@@ -0,0 +1,166 @@
+package registry
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type RegistryObjectType string
+
+const (
+	EntityObject              RegistryObjectType = "Entity"
+	FeatureServiceObject      RegistryObjectType = "FeatureService"
+	FeatureViewObject         RegistryObjectType = "FeatureView"
+	StreamFeatureViewObject   RegistryObjectType = "StreamFeatureView"
+	OnDemandFeatureViewObject RegistryObjectType = "OnDemandFeatureView"
+)
+
+// A RegistryObject identifies an object in the registry.
+type RegistryObject struct {
+	Project string
+	Type    RegistryObjectType
+	Name    string
+}
+
+// A RegistryChange describes how the registry changed between two versions. Objects are considered modified
+// when their spec changed; metadata such as timestamps is ignored.
+type RegistryChange struct {
+	PreviousVersion int64
+	Version         int64
+	Added           []RegistryObject
+	Removed         []RegistryObject
+	Modified        []RegistryObject
+}
+
+func (c *RegistryChange) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// Objects returns all added, removed and modified objects.
+func (c *RegistryChange) Objects() []RegistryObject {
+	objects := make([]RegistryObject, 0, len(c.Added)+len(c.Removed)+len(c.Modified))
+	objects = append(objects, c.Added...)
+	objects = append(objects, c.Removed...)
+	return append(objects, c.Modified...)
+}
+
+// Version returns the version of the loaded registry. It starts at 0 before the registry is loaded and is
+// incremented every time a refresh changes any entity, feature view or feature service.
+func (r *Registry) Version() int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version
+}
+
+// OnChange registers a callback that is called after a refresh swapped in a changed registry. Callbacks are
+// called one at a time in the order of the changes, after lookups already return the new objects. The
+// returned function unsubscribes the callback.
+func (r *Registry) OnChange(callback func(*RegistryChange)) func() {
+	r.subscribersMu.Lock()
+	defer r.subscribersMu.Unlock()
+	if r.subscribers == nil {
+		r.subscribers = make(map[int]func(*RegistryChange))
+	}
+	id := r.nextSubscriberId
+	r.nextSubscriberId++
+	r.subscribers[id] = callback
+	return func() {
+		r.subscribersMu.Lock()
+		defer r.subscribersMu.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+func (r *Registry) notify(change *RegistryChange) {
+	r.subscribersMu.Lock()
+	ids := make([]int, 0, len(r.subscribers))
+	for id := range r.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	callbacks := make([]func(*RegistryChange), len(ids))
+	for index, id := range ids {
+		callbacks[index] = r.subscribers[id]
+	}
+	r.subscribersMu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(change)
+	}
+}
+
+// AffectedFeatureServices returns the names of the feature services in the project whose features may have
+// changed: feature services that were changed themselves and those that reference a changed view. A changed
+// entity affects every feature service since join keys are shared between views.
+func (r *Registry) AffectedFeatureServices(project string, change *RegistryChange) []string {
+	changedViews := make(map[string]bool)
+	changedServices := make(map[string]bool)
+	entitiesChanged := false
+	for _, object := range change.Objects() {
+		if object.Project != project {
+			continue
+		}
+		switch object.Type {
+		case EntityObject:
+			entitiesChanged = true
+		case FeatureServiceObject:
+			changedServices[object.Name] = true
+		default:
+			changedViews[object.Name] = true
+		}
+	}
+
+	r.mu.RLock()
+	for name, featureService := range r.cachedFeatureServices[project] {
+		if entitiesChanged {
+			changedServices[name] = true
+			continue
+		}
+		for _, projection := range featureService.Spec.Features {
+			if changedViews[projection.FeatureViewName] {
+				changedServices[name] = true
+				break
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	names := make([]string, 0, len(changedServices))
+	for name := range changedServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diffObjects adds the differences between the cached objects of one type and the newly loaded ones to the change.
+func diffObjects[T any](change *RegistryChange, objectType RegistryObjectType, previous, current map[string]map[string]T, spec func(T) proto.Message) {
+	for _, project := range sortedKeys(current) {
+		for _, name := range sortedKeys(current[project]) {
+			object := RegistryObject{Project: project, Type: objectType, Name: name}
+			previousObject, ok := previous[project][name]
+			if !ok {
+				change.Added = append(change.Added, object)
+			} else if !proto.Equal(spec(previousObject), spec(current[project][name])) {
+				change.Modified = append(change.Modified, object)
+			}
+		}
+	}
+	for _, project := range sortedKeys(previous) {
+		for _, name := range sortedKeys(previous[project]) {
+			if _, ok := current[project][name]; !ok {
+				change.Removed = append(change.Removed, RegistryObject{Project: project, Type: objectType, Name: name})
+			}
+		}
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+func testRegistryProto(hourlyDescription string) *core.Registry {
+	return &core.Registry{
+		RegistrySchemaVersion: REGISTRY_SCHEMA_VERSION,
+		Entities:              []*core.Entity{{Spec: &core.EntitySpecV2{Name: "driver", Project: "feature_repo", JoinKey: "driver_id"}}},
+		FeatureViews: []*core.FeatureView{
+			{Spec: &core.FeatureViewSpec{Name: "driver_stats", Project: "feature_repo", Entities: []string{"driver"}}},
+			{Spec: &core.FeatureViewSpec{Name: "driver_hourly", Project: "feature_repo", Entities: []string{"driver"}, Description: hourlyDescription}},
+		},
+		FeatureServices: []*core.FeatureService{
+			{Spec: &core.FeatureServiceSpec{Name: "stats_service", Project: "feature_repo", Features: []*core.FeatureViewProjection{{FeatureViewName: "driver_stats"}}}},
+			{Spec: &core.FeatureServiceSpec{Name: "hourly_service", Project: "feature_repo", Features: []*core.FeatureViewProjection{{FeatureViewName: "driver_hourly"}}}},
+		},
+	}
+}
+
+func TestRegistryOnChange(t *testing.T) {
+	registryConfig := &RegistryConfig{Path: filepath.Join(t.TempDir(), "registry.db")}
+	r, err := NewRegistry(registryConfig, "", "feature_repo")
+	require.Nil(t, err)
+	require.Nil(t, r.registryStore.UpdateRegistryProto(testRegistryProto("v1")))
+
+	var changes []*RegistryChange
+	unsubscribe := r.OnChange(func(change *RegistryChange) {
+		// The new registry is already visible to callbacks
+		_, err := r.GetFeatureView("feature_repo", "driver_hourly")
+		assert.Equal(t, len(change.Removed) == 0, err == nil)
+		changes = append(changes, change)
+	})
+
+	require.Nil(t, r.Refresh())
+	require.Len(t, changes, 1)
+	assert.Equal(t, int64(0), changes[0].PreviousVersion)
+	assert.Equal(t, int64(1), changes[0].Version)
+	assert.Len(t, changes[0].Added, 5)
+	assert.Empty(t, changes[0].Removed)
+	assert.Empty(t, changes[0].Modified)
+
+	// Rewriting the same registry bumps its metadata but doesn't change any object
+	require.Nil(t, r.registryStore.UpdateRegistryProto(testRegistryProto("v1")))
+	require.Nil(t, r.Refresh())
+	assert.Len(t, changes, 1)
+	assert.Equal(t, int64(1), r.Version())
+
+	require.Nil(t, r.registryStore.UpdateRegistryProto(testRegistryProto("v2")))
+	require.Nil(t, r.Refresh())
+	require.Len(t, changes, 2)
+	assert.Equal(t, int64(2), changes[1].Version)
+	assert.Equal(t, []RegistryObject{{Project: "feature_repo", Type: FeatureViewObject, Name: "driver_hourly"}}, changes[1].Modified)
+	assert.Equal(t, []string{"hourly_service"}, r.AffectedFeatureServices("feature_repo", changes[1]))
+	assert.Empty(t, r.AffectedFeatureServices("other_project", changes[1]))
+
+	registryProto := testRegistryProto("v2")
+	registryProto.FeatureViews = registryProto.FeatureViews[:1]
+	registryProto.FeatureServices = registryProto.FeatureServices[:1]
+	require.Nil(t, r.registryStore.UpdateRegistryProto(registryProto))
+	require.Nil(t, r.Refresh())
+	require.Len(t, changes, 3)
+	assert.ElementsMatch(t, []RegistryObject{
+		{Project: "feature_repo", Type: FeatureViewObject, Name: "driver_hourly"},
+		{Project: "feature_repo", Type: FeatureServiceObject, Name: "hourly_service"},
+	}, changes[2].Removed)
+	assert.Equal(t, []string{"hourly_service"}, r.AffectedFeatureServices("feature_repo", changes[2]))
+
+	unsubscribe()
+	require.Nil(t, r.registryStore.UpdateRegistryProto(testRegistryProto("v3")))
+	require.Nil(t, r.Refresh())
+	assert.Len(t, changes, 3)
+	assert.Equal(t, int64(4), r.Version())
+}
+
+func TestAffectedFeatureServicesWithChangedEntity(t *testing.T) {
+	registryConfig := &RegistryConfig{Path: filepath.Join(t.TempDir(), "registry.db")}
+	r, err := NewRegistry(registryConfig, "", "feature_repo")
+	require.Nil(t, err)
+	r.load(testRegistryProto("v1"))
+
+	change := &RegistryChange{Modified: []RegistryObject{{Project: "feature_repo", Type: EntityObject, Name: "driver"}}}
+	assert.Equal(t, []string{"hourly_service", "stats_service"}, r.AffectedFeatureServices("feature_repo", change))
+}
@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+// A GCSRegistryStore is a Google Cloud Storage object based implementation of the RegistryStore interface.
+type GCSRegistryStore struct {
+	filePath string
+	bucket   string
+	key      string
+	client   *storage.Client
+
+	// Generation and contents of the last registry read, used to skip downloading an unchanged registry
+	mu             sync.Mutex
+	generation     int64
+	cachedRegistry *core.Registry
+}
+
+// NewGCSRegistryStore creates a GCSRegistryStore for a registry path of the form gs://bucket/key.
+// Credentials are resolved with Application Default Credentials.
+func NewGCSRegistryStore(config *RegistryConfig, repoPath string) (*GCSRegistryStore, error) {
+	bucket, key, err := parseObjectStoragePath(config.Path, "gs")
+	if err != nil {
+		return nil, err
+	}
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &GCSRegistryStore{
+		filePath: config.Path,
+		bucket:   bucket,
+		key:      key,
+		client:   client,
+	}, nil
+}
+
+// GetRegistryProto downloads and parses the registry object. Only the object metadata is fetched if the
+// generation hasn't changed since the previous read, in which case the registry is returned from memory.
+func (r *GCSRegistryStore) GetRegistryProto() (*core.Registry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx := context.Background()
+	object := r.client.Bucket(r.bucket).Object(r.key)
+	attrs, err := object.Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, fmt.Errorf("%w at %s: %w", errRegistryNotFound, r.filePath, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry from %s: %w", r.filePath, err)
+	}
+	if r.cachedRegistry != nil && attrs.Generation == r.generation {
+		return r.cachedRegistry, nil
+	}
+
+	// Read the generation the metadata was fetched for, so that generation and contents always match.
+	reader, err := object.Generation(attrs.Generation).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry from %s: %w", r.filePath, err)
+	}
+	defer reader.Close()
+	in, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	registry := &core.Registry{}
+	if err := proto.Unmarshal(in, registry); err != nil {
+		return nil, err
+	}
+	r.generation = attrs.Generation
+	r.cachedRegistry = registry
+	return registry, nil
+}
+
+func (r *GCSRegistryStore) UpdateRegistryProto(rp *core.Registry) error {
+	rp.VersionId = uuid.New().String()
+	rp.LastUpdated = timestamppb.Now()
+	data, err := proto.Marshal(rp)
+	if err != nil {
+		return err
+	}
+	writer := r.client.Bucket(r.bucket).Object(r.key).NewWriter(context.Background())
+	if _, err := io.Copy(writer, bytes.NewReader(data)); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+func (r *GCSRegistryStore) Teardown() error {
+	return r.client.Bucket(r.bucket).Object(r.key).Delete(context.Background())
+}
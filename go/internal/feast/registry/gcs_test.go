@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGCS answers object metadata requests of the Cloud Storage JSON API with a fixed status and counts uploads.
+type fakeGCS struct {
+	mu         sync.Mutex
+	readStatus int
+	uploads    int
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if strings.HasPrefix(r.URL.Path, "/upload/") {
+		f.uploads++
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.readStatus)
+	w.Write([]byte(`{"error": {"code": 0, "message": "fake error"}}`))
+}
+
+func newFakeGCS(t *testing.T, readStatus int) *fakeGCS {
+	fake := &fakeGCS{readStatus: readStatus}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	t.Setenv("STORAGE_EMULATOR_HOST", strings.TrimPrefix(server.URL, "http://"))
+	return fake
+}
+
+func TestGCSRegistryStoreReportsMissingRegistry(t *testing.T) {
+	newFakeGCS(t, http.StatusNotFound)
+	store, err := NewGCSRegistryStore(&RegistryConfig{Path: "gs://feast-bucket/registry.db"}, "")
+	require.Nil(t, err)
+
+	_, err = store.GetRegistryProto()
+	assert.ErrorIs(t, err, errRegistryNotFound)
+}
+
+func TestInitializeRegistryDoesNotOverwriteGCSRegistryWhenReadFails(t *testing.T) {
+	fake := newFakeGCS(t, http.StatusForbidden)
+	r, err := NewRegistry(&RegistryConfig{Path: "gs://feast-bucket/registry.db", CacheTtlSeconds: 600}, "", "feature_repo")
+	require.Nil(t, err)
+
+	err = r.InitializeRegistry()
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errRegistryNotFound)
+	assert.Equal(t, 0, fake.uploads)
+}
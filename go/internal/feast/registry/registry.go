@@ -9,17 +9,26 @@ import (
 
 	"github.com/feast-dev/feast/go/internal/feast/model"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/feast-dev/feast/go/protos/feast/core"
 )
 
 var REGISTRY_SCHEMA_VERSION string = "1"
+
+// errRegistryNotFound is returned by object storage registry stores whose registry object doesn't exist yet
+var errRegistryNotFound = errors.New("registry not found")
+
 var REGISTRY_STORE_CLASS_FOR_SCHEME map[string]string = map[string]string{
 	"gs":   "GCSRegistryStore",
 	"s3":   "S3RegistryStore",
 	"file": "FileRegistryStore",
 	"":     "FileRegistryStore",
 }
+var REGISTRY_STORE_CLASS_FOR_TYPE map[string]string = map[string]string{
+	"remote": "RemoteRegistryStore",
+	"sql":    "SqlRegistryStore",
+}
 
 /*
 	Store protos of FeatureView, FeatureService, Entity, OnDemandFeatureView
@@ -37,11 +46,19 @@ type Registry struct {
 	cachedRegistry                 *core.Registry
 	cachedRegistryProtoLastUpdated time.Time
 	cachedRegistryProtoTtl         time.Duration
+	version                        int64
+	subscribers                    map[int]func(*RegistryChange)
+	nextSubscriberId               int
+	subscribersMu                  sync.Mutex
+	loadMu                         sync.Mutex
 	mu                             sync.RWMutex
 }
 
 func NewRegistry(registryConfig *RegistryConfig, repoPath string, project string) (*Registry, error) {
 	registryStoreType := registryConfig.RegistryStoreType
+	if storeClass, ok := REGISTRY_STORE_CLASS_FOR_TYPE[registryConfig.RegistryType]; ok {
+		registryStoreType = storeClass
+	}
 	registryPath := registryConfig.Path
 	r := &Registry{
 		project:                project,
@@ -68,12 +85,17 @@ func NewRegistry(registryConfig *RegistryConfig, repoPath string, project string
 func (r *Registry) InitializeRegistry() error {
 	_, err := r.getRegistryProto()
 	if err != nil {
-		if _, ok := r.registryStore.(*FileRegistryStore); ok {
+		// Only a registry object that doesn't exist is created. Any other error, like a transient object
+		// storage failure, must not overwrite the existing registry.
+		if !errors.Is(err, errRegistryNotFound) {
 			log.Error().Err(err).Msg("Registry Initialization Failed")
 			return err
 		}
 		registryProto := &core.Registry{RegistrySchemaVersion: REGISTRY_SCHEMA_VERSION}
-		r.registryStore.UpdateRegistryProto(registryProto)
+		if err := r.registryStore.UpdateRegistryProto(registryProto); err != nil {
+			log.Error().Err(err).Msg("Registry Initialization Failed")
+			return err
+		}
 	}
 	go r.RefreshRegistryOnInterval()
 	return nil
@@ -94,84 +116,180 @@ func (r *Registry) refresh() error {
 	return err
 }
 
+// Refresh reloads the registry from its store, regardless of whether the cached registry has expired.
+func (r *Registry) Refresh() error {
+	registryProto, err := r.registryStore.GetRegistryProto()
+	if err != nil {
+		return err
+	}
+	r.loadIfChanged(registryProto)
+	return nil
+}
+
 func (r *Registry) getRegistryProto() (*core.Registry, error) {
-	expired := r.cachedRegistry == nil || (r.cachedRegistryProtoTtl > 0 && time.Now().After(r.cachedRegistryProtoLastUpdated.Add(r.cachedRegistryProtoTtl)))
+	r.mu.RLock()
+	cachedRegistry := r.cachedRegistry
+	expired := cachedRegistry == nil || (r.cachedRegistryProtoTtl > 0 && time.Now().After(r.cachedRegistryProtoLastUpdated.Add(r.cachedRegistryProtoTtl)))
+	r.mu.RUnlock()
 	if !expired {
-		return r.cachedRegistry, nil
+		return cachedRegistry, nil
 	}
 	registryProto, err := r.registryStore.GetRegistryProto()
 	if err != nil {
 		return nil, err
 	}
-	r.load(registryProto)
+	r.loadIfChanged(registryProto)
 	return registryProto, nil
 }
 
+// loadIfChanged loads the registry unless the store returned the registry that is already cached, which
+// stores with conditional fetches do when the registry hasn't changed since the previous read.
+func (r *Registry) loadIfChanged(registry *core.Registry) {
+	r.mu.Lock()
+	unchanged := registry == r.cachedRegistry
+	if unchanged {
+		r.cachedRegistryProtoLastUpdated = time.Now()
+	}
+	r.mu.Unlock()
+	if !unchanged {
+		r.load(registry)
+	}
+}
+
+// load builds the caches for the new registry without blocking readers and then swaps them in at once, so
+// that lookups never observe a partially loaded registry. Subscribers are notified after the swap.
 func (r *Registry) load(registry *core.Registry) {
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	entities := loadEntities(r.project, registry)
+	featureServices := loadFeatureServices(r.project, registry)
+	featureViews := loadFeatureViews(r.project, registry)
+	streamFeatureViews := loadStreamFeatureViews(r.project, registry)
+	onDemandFeatureViews := loadOnDemandFeatureViews(r.project, registry)
+
+	change := &RegistryChange{}
+	diffObjects(change, EntityObject, r.cachedEntities, entities, func(e *core.Entity) proto.Message { return e.Spec })
+	diffObjects(change, FeatureServiceObject, r.cachedFeatureServices, featureServices, func(fs *core.FeatureService) proto.Message { return fs.Spec })
+	diffObjects(change, FeatureViewObject, r.cachedFeatureViews, featureViews, func(fv *core.FeatureView) proto.Message { return fv.Spec })
+	diffObjects(change, StreamFeatureViewObject, r.cachedStreamFeatureViews, streamFeatureViews, func(sfv *core.StreamFeatureView) proto.Message { return sfv.Spec })
+	diffObjects(change, OnDemandFeatureViewObject, r.cachedOnDemandFeatureViews, onDemandFeatureViews, func(odfv *core.OnDemandFeatureView) proto.Message { return odfv.Spec })
+
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	r.cachedRegistry = registry
-	r.cachedFeatureServices = make(map[string]map[string]*core.FeatureService)
-	r.cachedEntities = make(map[string]map[string]*core.Entity)
-	r.cachedFeatureViews = make(map[string]map[string]*core.FeatureView)
-	r.cachedStreamFeatureViews = make(map[string]map[string]*core.StreamFeatureView)
-	r.cachedOnDemandFeatureViews = make(map[string]map[string]*core.OnDemandFeatureView)
-	r.loadEntities(registry)
-	r.loadFeatureServices(registry)
-	r.loadFeatureViews(registry)
-	r.loadStreamFeatureViews(registry)
-	r.loadOnDemandFeatureViews(registry)
+	r.cachedEntities = entities
+	r.cachedFeatureServices = featureServices
+	r.cachedFeatureViews = featureViews
+	r.cachedStreamFeatureViews = streamFeatureViews
+	r.cachedOnDemandFeatureViews = onDemandFeatureViews
 	r.cachedRegistryProtoLastUpdated = time.Now()
+	change.PreviousVersion = r.version
+	if !change.IsEmpty() {
+		r.version++
+	}
+	change.Version = r.version
+	r.mu.Unlock()
+
+	if !change.IsEmpty() {
+		r.notify(change)
+	}
+}
+
+// getObjectProject returns the project of a registry object. Objects without a project belong to the default project.
+func getObjectProject(objectProject string, defaultProject string) string {
+	if objectProject == "" {
+		return defaultProject
+	}
+	return objectProject
 }
 
-func (r *Registry) loadEntities(registry *core.Registry) {
-	entities := registry.Entities
-	for _, entity := range entities {
-		if _, ok := r.cachedEntities[r.project]; !ok {
-			r.cachedEntities[r.project] = make(map[string]*core.Entity)
+func loadEntities(project string, registry *core.Registry) map[string]map[string]*core.Entity {
+	cachedEntities := make(map[string]map[string]*core.Entity)
+	for _, entity := range registry.Entities {
+		objectProject := getObjectProject(entity.Spec.Project, project)
+		if _, ok := cachedEntities[objectProject]; !ok {
+			cachedEntities[objectProject] = make(map[string]*core.Entity)
 		}
-		r.cachedEntities[r.project][entity.Spec.Name] = entity
+		cachedEntities[objectProject][entity.Spec.Name] = entity
 	}
+	return cachedEntities
 }
 
-func (r *Registry) loadFeatureServices(registry *core.Registry) {
-	featureServices := registry.FeatureServices
-	for _, featureService := range featureServices {
-		if _, ok := r.cachedFeatureServices[r.project]; !ok {
-			r.cachedFeatureServices[r.project] = make(map[string]*core.FeatureService)
+func loadFeatureServices(project string, registry *core.Registry) map[string]map[string]*core.FeatureService {
+	cachedFeatureServices := make(map[string]map[string]*core.FeatureService)
+	for _, featureService := range registry.FeatureServices {
+		objectProject := getObjectProject(featureService.Spec.Project, project)
+		if _, ok := cachedFeatureServices[objectProject]; !ok {
+			cachedFeatureServices[objectProject] = make(map[string]*core.FeatureService)
 		}
-		r.cachedFeatureServices[r.project][featureService.Spec.Name] = featureService
+		cachedFeatureServices[objectProject][featureService.Spec.Name] = featureService
 	}
+	return cachedFeatureServices
 }
 
-func (r *Registry) loadFeatureViews(registry *core.Registry) {
-	featureViews := registry.FeatureViews
-	for _, featureView := range featureViews {
-		if _, ok := r.cachedFeatureViews[r.project]; !ok {
-			r.cachedFeatureViews[r.project] = make(map[string]*core.FeatureView)
+func loadFeatureViews(project string, registry *core.Registry) map[string]map[string]*core.FeatureView {
+	cachedFeatureViews := make(map[string]map[string]*core.FeatureView)
+	for _, featureView := range registry.FeatureViews {
+		objectProject := getObjectProject(featureView.Spec.Project, project)
+		if _, ok := cachedFeatureViews[objectProject]; !ok {
+			cachedFeatureViews[objectProject] = make(map[string]*core.FeatureView)
 		}
-		r.cachedFeatureViews[r.project][featureView.Spec.Name] = featureView
+		cachedFeatureViews[objectProject][featureView.Spec.Name] = featureView
 	}
+	return cachedFeatureViews
 }
 
-func (r *Registry) loadStreamFeatureViews(registry *core.Registry) {
-	streamFeatureViews := registry.StreamFeatureViews
-	for _, streamFeatureView := range streamFeatureViews {
-		if _, ok := r.cachedStreamFeatureViews[r.project]; !ok {
-			r.cachedStreamFeatureViews[r.project] = make(map[string]*core.StreamFeatureView)
+func loadStreamFeatureViews(project string, registry *core.Registry) map[string]map[string]*core.StreamFeatureView {
+	cachedStreamFeatureViews := make(map[string]map[string]*core.StreamFeatureView)
+	for _, streamFeatureView := range registry.StreamFeatureViews {
+		objectProject := getObjectProject(streamFeatureView.Spec.Project, project)
+		if _, ok := cachedStreamFeatureViews[objectProject]; !ok {
+			cachedStreamFeatureViews[objectProject] = make(map[string]*core.StreamFeatureView)
 		}
-		r.cachedStreamFeatureViews[r.project][streamFeatureView.Spec.Name] = streamFeatureView
+		cachedStreamFeatureViews[objectProject][streamFeatureView.Spec.Name] = streamFeatureView
 	}
+	return cachedStreamFeatureViews
 }
 
-func (r *Registry) loadOnDemandFeatureViews(registry *core.Registry) {
-	onDemandFeatureViews := registry.OnDemandFeatureViews
-	for _, onDemandFeatureView := range onDemandFeatureViews {
-		if _, ok := r.cachedOnDemandFeatureViews[r.project]; !ok {
-			r.cachedOnDemandFeatureViews[r.project] = make(map[string]*core.OnDemandFeatureView)
+func loadOnDemandFeatureViews(project string, registry *core.Registry) map[string]map[string]*core.OnDemandFeatureView {
+	cachedOnDemandFeatureViews := make(map[string]map[string]*core.OnDemandFeatureView)
+	for _, onDemandFeatureView := range registry.OnDemandFeatureViews {
+		objectProject := getObjectProject(onDemandFeatureView.Spec.Project, project)
+		if _, ok := cachedOnDemandFeatureViews[objectProject]; !ok {
+			cachedOnDemandFeatureViews[objectProject] = make(map[string]*core.OnDemandFeatureView)
 		}
-		r.cachedOnDemandFeatureViews[r.project][onDemandFeatureView.Spec.Name] = onDemandFeatureView
+		cachedOnDemandFeatureViews[objectProject][onDemandFeatureView.Spec.Name] = onDemandFeatureView
+	}
+	return cachedOnDemandFeatureViews
+}
+
+// ListProjects returns the names of the projects in the registry, including projects that only have objects
+// but no project metadata, such as registries written by older versions of Feast.
+func (r *Registry) ListProjects() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	projects := make(map[string]bool)
+	if r.cachedRegistry != nil {
+		for _, project := range r.cachedRegistry.Projects {
+			projects[project.GetSpec().GetName()] = true
+		}
+	}
+	for project := range r.cachedEntities {
+		projects[project] = true
 	}
+	for project := range r.cachedFeatureServices {
+		projects[project] = true
+	}
+	for project := range r.cachedFeatureViews {
+		projects[project] = true
+	}
+	for project := range r.cachedStreamFeatureViews {
+		projects[project] = true
+	}
+	for project := range r.cachedOnDemandFeatureViews {
+		projects[project] = true
+	}
+	return sortedKeys(projects)
 }
 
 /*
@@ -349,6 +467,22 @@ func (r *Registry) GetOnDemandFeatureView(project, onDemandFeatureViewName strin
 	}
 }
 
+// ListPermissions returns the permissions of the project.
+func (r *Registry) ListPermissions(project string) []*core.Permission {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	permissions := make([]*core.Permission, 0)
+	if r.cachedRegistry == nil {
+		return permissions
+	}
+	for _, permission := range r.cachedRegistry.Permissions {
+		if getObjectProject(permission.GetSpec().GetProject(), r.project) == project {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
 func getRegistryStoreFromScheme(registryPath string, registryConfig *RegistryConfig, repoPath string, project string) (RegistryStore, error) {
 	uri, err := url.Parse(registryPath)
 	if err != nil {
@@ -364,6 +498,14 @@ func getRegistryStoreFromType(registryStoreType string, registryConfig *Registry
 	switch registryStoreType {
 	case "FileRegistryStore":
 		return NewFileRegistryStore(registryConfig, repoPath), nil
+	case "S3RegistryStore":
+		return NewS3RegistryStore(registryConfig, repoPath)
+	case "GCSRegistryStore":
+		return NewGCSRegistryStore(registryConfig, repoPath)
+	case "RemoteRegistryStore":
+		return NewRemoteRegistryStore(registryConfig)
+	case "SqlRegistryStore":
+		return NewSqlRegistryStore(registryConfig, repoPath)
 	}
-	return nil, errors.New("only FileRegistryStore as a RegistryStore is supported at this moment")
+	return nil, fmt.Errorf("%s is not a supported RegistryStore; only FileRegistryStore, S3RegistryStore, GCSRegistryStore, RemoteRegistryStore and SqlRegistryStore are supported", registryStoreType)
 }
@@ -0,0 +1,37 @@
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+func TestRegistryObjectsAreKeyedByProject(t *testing.T) {
+	registryConfig := &RegistryConfig{Path: filepath.Join(t.TempDir(), "registry.db")}
+	r, err := NewRegistry(registryConfig, "", "feature_repo")
+	require.Nil(t, err)
+	r.load(&core.Registry{
+		Projects: []*core.Project{{Spec: &core.ProjectSpec{Name: "empty_project"}}},
+		Entities: []*core.Entity{
+			{Spec: &core.EntitySpecV2{Name: "driver", Project: "feature_repo", JoinKey: "driver_id"}},
+			{Spec: &core.EntitySpecV2{Name: "driver", Project: "other_repo", JoinKey: "other_driver_id"}},
+			// Objects without a project belong to the default project
+			{Spec: &core.EntitySpecV2{Name: "customer", JoinKey: "customer_id"}},
+		},
+	})
+
+	assert.Equal(t, []string{"empty_project", "feature_repo", "other_repo"}, r.ListProjects())
+	entity, err := r.GetEntity("other_repo", "driver")
+	require.Nil(t, err)
+	assert.Equal(t, "other_driver_id", entity.JoinKey)
+	entities, err := r.ListEntities("feature_repo")
+	require.Nil(t, err)
+	assert.Len(t, entities, 2)
+	entities, err = r.ListEntities("empty_project")
+	require.Nil(t, err)
+	assert.Empty(t, entities)
+}
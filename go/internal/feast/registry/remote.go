@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+	registryServer "github.com/feast-dev/feast/go/protos/feast/registry"
+)
+
+const remoteRegistryTimeout = 30 * time.Second
+
+// A RemoteRegistryStore is a read-only implementation of the RegistryStore interface that fetches the registry
+// from a Feast registry server (registry_type: remote), e.g. one deployed by the Feast operator.
+type RemoteRegistryStore struct {
+	path   string
+	conn   *grpc.ClientConn
+	client registryServer.RegistryServerClient
+}
+
+// NewRemoteRegistryStore connects to the registry server at config.Path (host:port). Like the Python
+// RemoteRegistry, TLS is used if a CA certificate is configured with cert or if is_tls is set.
+func NewRemoteRegistryStore(config *RegistryConfig) (*RemoteRegistryStore, error) {
+	if config.Path == "" {
+		return nil, errors.New("remote registry requires the registry server address as path")
+	}
+	transportCredentials, err := getRemoteRegistryCredentials(config)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(config.Path, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteRegistryStore{
+		path:   config.Path,
+		conn:   conn,
+		client: registryServer.NewRegistryServerClient(conn),
+	}, nil
+}
+
+func getRemoteRegistryCredentials(config *RegistryConfig) (credentials.TransportCredentials, error) {
+	if config.Cert != "" {
+		pem, err := os.ReadFile(config.Cert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read registry server certificate: %w", err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", config.Cert)
+		}
+		return credentials.NewTLS(&tls.Config{RootCAs: certPool}), nil
+	}
+	if config.IsTls {
+		return credentials.NewTLS(&tls.Config{}), nil
+	}
+	return insecure.NewCredentials(), nil
+}
+
+// GetRegistryProto fetches the whole registry with the Proto RPC.
+func (r *RemoteRegistryStore) GetRegistryProto() (*core.Registry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRegistryTimeout)
+	defer cancel()
+	registry, err := r.client.Proto(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch registry from %s: %w", r.path, err)
+	}
+	return registry, nil
+}
+
+func (r *RemoteRegistryStore) UpdateRegistryProto(rp *core.Registry) error {
+	return errors.New("remote registry is read-only; apply changes through the registry server")
+}
+
+// Teardown closes the connection to the registry server; the registry itself is left untouched.
+func (r *RemoteRegistryStore) Teardown() error {
+	return r.conn.Close()
+}
@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+	registryServer "github.com/feast-dev/feast/go/protos/feast/registry"
+)
+
+type fakeRegistryServer struct {
+	registry *core.Registry
+	registryServer.UnimplementedRegistryServerServer
+}
+
+func (s *fakeRegistryServer) Proto(ctx context.Context, in *emptypb.Empty) (*core.Registry, error) {
+	return s.registry, nil
+}
+
+func startFakeRegistryServer(t *testing.T, opts ...grpc.ServerOption) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	server := grpc.NewServer(opts...)
+	registryServer.RegisterRegistryServerServer(server, &fakeRegistryServer{registry: &core.Registry{
+		RegistrySchemaVersion: REGISTRY_SCHEMA_VERSION,
+		Entities:              []*core.Entity{{Spec: &core.EntitySpecV2{Name: "driver", Project: "feature_repo", JoinKey: "driver_id"}}},
+	}})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func TestRemoteRegistryStore(t *testing.T) {
+	address := startFakeRegistryServer(t)
+	repoConfig := &RepoConfig{Registry: map[string]interface{}{"registry_type": "remote", "path": address}}
+	registryConfig, err := repoConfig.GetRegistryConfig()
+	require.Nil(t, err)
+
+	r, err := NewRegistry(registryConfig, "", "feature_repo")
+	require.Nil(t, err)
+	assert.IsType(t, &RemoteRegistryStore{}, r.registryStore)
+	defer r.registryStore.Teardown()
+
+	require.Nil(t, r.Refresh())
+	entity, err := r.GetEntity("feature_repo", "driver")
+	require.Nil(t, err)
+	assert.Equal(t, "driver_id", entity.JoinKey)
+	assert.NotNil(t, r.registryStore.UpdateRegistryProto(&core.Registry{}))
+}
+
+func TestRemoteRegistryStoreWithTls(t *testing.T) {
+	certFile, serverCert := generateSelfSignedCert(t)
+	address := startFakeRegistryServer(t, grpc.Creds(credentials.NewServerTLSFromCert(&serverCert)))
+
+	store, err := NewRemoteRegistryStore(&RegistryConfig{RegistryType: "remote", Path: address, Cert: certFile})
+	require.Nil(t, err)
+	defer store.Teardown()
+	registry, err := store.GetRegistryProto()
+	require.Nil(t, err)
+	assert.Len(t, registry.Entities, 1)
+
+	// Without the CA certificate the server certificate isn't trusted
+	insecureStore, err := NewRemoteRegistryStore(&RegistryConfig{RegistryType: "remote", Path: address, IsTls: true})
+	require.Nil(t, err)
+	defer insecureStore.Teardown()
+	_, err = insecureStore.GetRegistryProto()
+	assert.NotNil(t, err)
+}
+
+func TestNewRemoteRegistryStoreWithMissingCert(t *testing.T) {
+	_, err := NewRemoteRegistryStore(&RegistryConfig{RegistryType: "remote", Path: "localhost:6570", Cert: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.NotNil(t, err)
+}
+
+// generateSelfSignedCert writes a self-signed certificate for 127.0.0.1 to a temporary file and returns
+// its path together with the certificate and key for the server.
+func generateSelfSignedCert(t *testing.T) (string, tls.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "registry"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+	require.Nil(t, os.WriteFile(certFile, certPem, 0600))
+	serverCert, err := tls.X509KeyPair(certPem, keyPem)
+	require.Nil(t, err)
+	return certFile, serverCert
+}
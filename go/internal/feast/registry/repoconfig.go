@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/feast-dev/feast/go/internal/feast/auth"
 	"github.com/feast-dev/feast/go/internal/feast/server/logging"
 	"github.com/ghodss/yaml"
 )
@@ -14,6 +15,17 @@ import (
 const (
 	defaultCacheTtlSeconds = int64(600)
 	defaultClientID        = "Unknown"
+	// Number of entity groups of a request that are read from the online store concurrently
+	defaultOnlineReadConcurrency = 8
+	// Number of on demand feature views of a request that the transformation service transforms concurrently
+	defaultTransformationConcurrency = 8
+
+	defaultTransformationServiceTimeout    = 10 * time.Second
+	defaultTransformationServiceMaxRetries = 3
+	defaultTransformationServiceBackoff    = 50 * time.Millisecond
+	defaultTransformationServiceMaxBackoff = time.Second
+	defaultCircuitBreakerFailureThreshold  = 5
+	defaultCircuitBreakerResetTimeout      = 30 * time.Second
 )
 
 type RepoConfig struct {
@@ -32,6 +44,8 @@ type RepoConfig struct {
 	FeatureServer map[string]interface{} `json:"feature_server"`
 	// Feature flags for experimental features
 	Flags map[string]interface{} `json:"flags"`
+	// Authentication and authorization config
+	Auth map[string]interface{} `json:"auth"`
 	// RepoPath
 	RepoPath string `json:"repo_path"`
 	// EntityKeySerializationVersion
@@ -40,9 +54,45 @@ type RepoConfig struct {
 
 type RegistryConfig struct {
 	RegistryStoreType string `json:"registry_store_type"`
-	Path              string `json:"path"`
-	ClientId          string `json:"client_id" default:"Unknown"`
-	CacheTtlSeconds   int64  `json:"cache_ttl_seconds" default:"600"`
+	// Registry implementation, "remote" for a Feast registry server at Path (host:port) or "sql" for a
+	// Python SQL registry at the database URL in Path
+	RegistryType string `json:"registry_type"`
+	Path         string `json:"path"`
+	// Read endpoint of a SQL registry, if different from Path
+	ReadPath        string `json:"read_path"`
+	ClientId        string `json:"client_id" default:"Unknown"`
+	CacheTtlSeconds int64  `json:"cache_ttl_seconds" default:"600"`
+	// Path to the CA certificate of a remote registry server; setting it enables TLS
+	Cert string `json:"cert"`
+	// Whether to connect to a remote registry server with TLS, using the system CA certificates unless Cert is set
+	IsTls bool `json:"is_tls"`
+}
+
+// TransformationServiceConfig configures the client of the transformation service, set by
+// transformation_service_endpoint and the transformation_service options in the feature_server section.
+type TransformationServiceConfig struct {
+	// Endpoints that calls are balanced between (transformation_service_endpoint, endpoints)
+	Endpoints []string
+	// Whether to connect with TLS, using the system certificates unless Cert is set (is_tls)
+	IsTls bool
+	// Path of the CA certificate of the transformation server, which implies TLS (cert)
+	Cert string
+	// Deadline of each call, unlimited if 0 (timeout_seconds)
+	Timeout time.Duration
+	// Number of times that calls failing with UNAVAILABLE are retried on the next endpoint (max_retries)
+	MaxRetries int
+	// Backoff before the first retry, doubled for each retry up to MaxBackoff (initial_backoff_ms, max_backoff_ms)
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Consecutive failures after which an endpoint isn't called, disabled if 0 (circuit_breaker_failure_threshold)
+	CircuitBreakerFailureThreshold int
+	// How long an endpoint isn't called before it's tried again (circuit_breaker_reset_seconds)
+	CircuitBreakerResetTimeout time.Duration
+	// Whether to check the endpoints with GetTransformationServiceInfo on startup, which delays the start of the
+	// feature server by up to 5 seconds if the transformation service doesn't respond (health_check)
+	HealthCheck bool
+	// Whether the feature server fails to start when no endpoint passes the health check (require_healthy)
+	RequireHealthy bool
 }
 
 // NewRepoConfigFromJSON converts a JSON string into a RepoConfig struct and also sets the repo path.
@@ -109,6 +159,212 @@ func (r *RepoConfig) GetLoggingOptions() (*logging.LoggingOptions, error) {
 	return &loggingOptions, nil
 }
 
+// GetOnlineReadConcurrency returns how many entity groups of a request are read from the online store
+// concurrently, set by online_read_concurrency in the feature_server section.
+func (r *RepoConfig) GetOnlineReadConcurrency() (int, error) {
+	return r.getFeatureServerConcurrency("online_read_concurrency", defaultOnlineReadConcurrency)
+}
+
+// GetTransformationConcurrency returns how many on demand feature views of a request the transformation service
+// transforms concurrently, set by transformation_concurrency in the feature_server section.
+func (r *RepoConfig) GetTransformationConcurrency() (int, error) {
+	return r.getFeatureServerConcurrency("transformation_concurrency", defaultTransformationConcurrency)
+}
+
+// GetOnDemandTransformationFallback returns whether the features of on demand feature views that are written to the
+// online store are transformed for the entities that they aren't found for, set by
+// on_demand_transformation_fallback in the feature_server section.
+func (r *RepoConfig) GetOnDemandTransformationFallback() (bool, error) {
+	v, ok := r.FeatureServer["on_demand_transformation_fallback"]
+	if !ok {
+		return false, nil
+	}
+	return getFeatureServerBool("on_demand_transformation_fallback", v)
+}
+
+func (r *RepoConfig) getFeatureServerConcurrency(key string, defaultConcurrency int) (int, error) {
+	v, ok := r.FeatureServer[key]
+	if !ok {
+		return defaultConcurrency, nil
+	}
+	concurrency, err := getFeatureServerInt(key, v)
+	if err != nil {
+		return 0, err
+	}
+	if concurrency < 1 {
+		return 0, fmt.Errorf("feature_server %s must be at least 1, got %d", key, concurrency)
+	}
+	return concurrency, nil
+}
+
+// GetTransformationServiceConfig returns the configuration of the transformation service client, or nil if no
+// transformation service endpoint is configured.
+func (r *RepoConfig) GetTransformationServiceConfig() (*TransformationServiceConfig, error) {
+	serviceConfig := TransformationServiceConfig{
+		Timeout:                        defaultTransformationServiceTimeout,
+		MaxRetries:                     defaultTransformationServiceMaxRetries,
+		InitialBackoff:                 defaultTransformationServiceBackoff,
+		MaxBackoff:                     defaultTransformationServiceMaxBackoff,
+		CircuitBreakerFailureThreshold: defaultCircuitBreakerFailureThreshold,
+		CircuitBreakerResetTimeout:     defaultCircuitBreakerResetTimeout,
+	}
+	if v, ok := r.FeatureServer["transformation_service_endpoint"]; ok && v != nil {
+		endpoints, err := getFeatureServerStrings("transformation_service_endpoint", v)
+		if err != nil {
+			return nil, err
+		}
+		serviceConfig.Endpoints = append(serviceConfig.Endpoints, endpoints...)
+	}
+	if v, ok := r.FeatureServer["transformation_service"]; ok && v != nil {
+		options, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T for feature_server transformation_service", v)
+		}
+		for k, v := range options {
+			key := "transformation_service " + k
+			var err error
+			switch k {
+			case "endpoints":
+				var endpoints []string
+				endpoints, err = getFeatureServerStrings(key, v)
+				serviceConfig.Endpoints = append(serviceConfig.Endpoints, endpoints...)
+			case "is_tls":
+				serviceConfig.IsTls, err = getFeatureServerBool(key, v)
+			case "cert":
+				cert, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("unexpected type %T for feature_server %s", v, key)
+				}
+				serviceConfig.Cert = cert
+			case "timeout_seconds":
+				serviceConfig.Timeout, err = getFeatureServerDuration(key, v, time.Second)
+			case "max_retries":
+				serviceConfig.MaxRetries, err = getFeatureServerInt(key, v)
+			case "initial_backoff_ms":
+				serviceConfig.InitialBackoff, err = getFeatureServerDuration(key, v, time.Millisecond)
+			case "max_backoff_ms":
+				serviceConfig.MaxBackoff, err = getFeatureServerDuration(key, v, time.Millisecond)
+			case "circuit_breaker_failure_threshold":
+				serviceConfig.CircuitBreakerFailureThreshold, err = getFeatureServerInt(key, v)
+			case "circuit_breaker_reset_seconds":
+				serviceConfig.CircuitBreakerResetTimeout, err = getFeatureServerDuration(key, v, time.Second)
+			case "health_check":
+				serviceConfig.HealthCheck, err = getFeatureServerBool(key, v)
+			case "require_healthy":
+				serviceConfig.RequireHealthy, err = getFeatureServerBool(key, v)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(serviceConfig.Endpoints) == 0 {
+		return nil, nil
+	}
+	if serviceConfig.MaxRetries < 0 || serviceConfig.CircuitBreakerFailureThreshold < 0 {
+		return nil, fmt.Errorf("feature_server transformation_service max_retries and circuit_breaker_failure_threshold must not be negative")
+	}
+	return &serviceConfig, nil
+}
+
+func getFeatureServerInt(key string, v interface{}) (int, error) {
+	switch value := v.(type) {
+	case float64:
+		return int(value), nil
+	case int:
+		return value, nil
+	case int64:
+		return int(value), nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T for feature_server %s", v, key)
+	}
+}
+
+func getFeatureServerBool(key string, v interface{}) (bool, error) {
+	value, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("unexpected type %T for feature_server %s", v, key)
+	}
+	return value, nil
+}
+
+// getFeatureServerDuration converts a number of units, which may be fractional, into a duration.
+func getFeatureServerDuration(key string, v interface{}, unit time.Duration) (time.Duration, error) {
+	var duration time.Duration
+	if value, ok := v.(float64); ok {
+		duration = time.Duration(value * float64(unit))
+	} else {
+		value, err := getFeatureServerInt(key, v)
+		if err != nil {
+			return 0, err
+		}
+		duration = time.Duration(value) * unit
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("feature_server %s must not be negative, got %v", key, v)
+	}
+	return duration, nil
+}
+
+// getFeatureServerStrings converts a string or a list of strings.
+func getFeatureServerStrings(key string, v interface{}) ([]string, error) {
+	switch value := v.(type) {
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		values := make([]string, len(value))
+		for i, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected type %T in feature_server %s", item, key)
+			}
+			values[i] = s
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unexpected type %T for feature_server %s", v, key)
+	}
+}
+
+func (r *RepoConfig) GetAuthConfig() (*auth.AuthConfig, error) {
+	authConfig := auth.AuthConfig{Type: auth.NoAuth}
+	for k, v := range r.Auth {
+		if k == "roles" {
+			roles, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected type %T for auth %s", v, k)
+			}
+			for _, role := range roles {
+				if role, ok := role.(string); ok {
+					authConfig.Roles = append(authConfig.Roles, role)
+				}
+			}
+			continue
+		}
+		value, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T for auth %s", v, k)
+		}
+		switch k {
+		case "type":
+			authConfig.Type = value
+		case "auth_discovery_url":
+			authConfig.AuthDiscoveryUrl = value
+		case "client_id":
+			authConfig.ClientId = value
+		case "jwks_file":
+			authConfig.JwksFile = value
+		case "roles_claim":
+			authConfig.RolesClaim = value
+		case "api_server_url":
+			authConfig.ApiServerUrl = value
+		case "namespace":
+			authConfig.Namespace = value
+		}
+	}
+	return &authConfig, nil
+}
+
 func (r *RepoConfig) GetRegistryConfig() (*RegistryConfig, error) {
 	if registryConfigMap, ok := r.Registry.(map[string]interface{}); ok {
 		registryConfig := RegistryConfig{CacheTtlSeconds: defaultCacheTtlSeconds, ClientId: defaultClientID}
@@ -122,10 +378,26 @@ func (r *RepoConfig) GetRegistryConfig() (*RegistryConfig, error) {
 				if value, ok := v.(string); ok {
 					registryConfig.RegistryStoreType = value
 				}
+			case "read_path":
+				if value, ok := v.(string); ok {
+					registryConfig.ReadPath = value
+				}
+			case "registry_type":
+				if value, ok := v.(string); ok {
+					registryConfig.RegistryType = value
+				}
 			case "client_id":
 				if value, ok := v.(string); ok {
 					registryConfig.ClientId = value
 				}
+			case "cert":
+				if value, ok := v.(string); ok {
+					registryConfig.Cert = value
+				}
+			case "is_tls":
+				if value, ok := v.(bool); ok {
+					registryConfig.IsTls = value
+				}
 			case "cache_ttl_seconds":
 				// cache_ttl_seconds defaulted to type float64. Ex: "cache_ttl_seconds": 60 in registryConfigMap
 				switch value := v.(type) {
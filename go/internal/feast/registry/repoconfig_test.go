@@ -7,8 +7,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/feast-dev/feast/go/internal/feast/auth"
 	"github.com/feast-dev/feast/go/internal/feast/server/logging"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewRepoConfig(t *testing.T) {
@@ -337,3 +339,127 @@ func TestGetLoggingOptions_InvalidType(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, logging.DefaultOptions, *options)
 }
+
+func TestGetOnlineReadConcurrency(t *testing.T) {
+	concurrency, err := (&RepoConfig{}).GetOnlineReadConcurrency()
+	assert.Nil(t, err)
+	assert.Equal(t, defaultOnlineReadConcurrency, concurrency)
+
+	config, err := NewRepoConfigFromJSON(t.TempDir(), `{"feature_server": {"online_read_concurrency": 3}}`)
+	assert.Nil(t, err)
+	concurrency, err = config.GetOnlineReadConcurrency()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, concurrency)
+
+	_, err = (&RepoConfig{FeatureServer: map[string]interface{}{"online_read_concurrency": 0}}).GetOnlineReadConcurrency()
+	assert.Error(t, err)
+	_, err = (&RepoConfig{FeatureServer: map[string]interface{}{"online_read_concurrency": "3"}}).GetOnlineReadConcurrency()
+	assert.Error(t, err)
+}
+
+func TestGetTransformationConcurrency(t *testing.T) {
+	concurrency, err := (&RepoConfig{}).GetTransformationConcurrency()
+	assert.Nil(t, err)
+	assert.Equal(t, defaultTransformationConcurrency, concurrency)
+
+	config, err := NewRepoConfigFromJSON(t.TempDir(), `{"feature_server": {"transformation_concurrency": 2}}`)
+	assert.Nil(t, err)
+	concurrency, err = config.GetTransformationConcurrency()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, concurrency)
+
+	_, err = (&RepoConfig{FeatureServer: map[string]interface{}{"transformation_concurrency": -1}}).GetTransformationConcurrency()
+	assert.Error(t, err)
+}
+
+func TestGetAuthConfig_Default(t *testing.T) {
+	config := RepoConfig{}
+	authConfig, err := config.GetAuthConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, auth.AuthConfig{Type: auth.NoAuth}, *authConfig)
+}
+
+func TestGetAuthConfig_Kubernetes(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte(`
+project: feature_repo
+registry: "data/registry.db"
+provider: local
+auth:
+  type: kubernetes
+  namespace: feast
+  roles:
+    - feast-reader
+    - feast-writer
+`)
+	err := os.WriteFile(filepath.Join(dir, "feature_store.yaml"), data, 0666)
+	assert.Nil(t, err)
+	config, err := NewRepoConfigFromFile(dir)
+	assert.Nil(t, err)
+	authConfig, err := config.GetAuthConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, auth.AuthConfig{Type: auth.KubernetesAuth, Namespace: "feast", Roles: []string{"feast-reader", "feast-writer"}}, *authConfig)
+}
+
+func TestGetAuthConfig_InvalidType(t *testing.T) {
+	config := RepoConfig{Auth: map[string]interface{}{"type": "oidc", "client_id": 1}}
+	_, err := config.GetAuthConfig()
+	assert.Error(t, err)
+}
+
+func TestGetOnDemandTransformationFallback(t *testing.T) {
+	fallback, err := (&RepoConfig{}).GetOnDemandTransformationFallback()
+	assert.Nil(t, err)
+	assert.False(t, fallback)
+
+	fallback, err = (&RepoConfig{FeatureServer: map[string]interface{}{"on_demand_transformation_fallback": true}}).GetOnDemandTransformationFallback()
+	assert.Nil(t, err)
+	assert.True(t, fallback)
+
+	_, err = (&RepoConfig{FeatureServer: map[string]interface{}{"on_demand_transformation_fallback": "yes"}}).GetOnDemandTransformationFallback()
+	assert.Error(t, err)
+}
+
+func TestGetTransformationServiceConfig(t *testing.T) {
+	config := &RepoConfig{FeatureServer: map[string]interface{}{}}
+	serviceConfig, err := config.GetTransformationServiceConfig()
+	require.Nil(t, err)
+	assert.Nil(t, serviceConfig)
+
+	config.FeatureServer["transformation_service_endpoint"] = "localhost:6566"
+	serviceConfig, err = config.GetTransformationServiceConfig()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"localhost:6566"}, serviceConfig.Endpoints)
+	assert.Equal(t, defaultTransformationServiceTimeout, serviceConfig.Timeout)
+	// The health check delays the start of the feature server, so it's opt-in
+	assert.False(t, serviceConfig.HealthCheck)
+
+	config.FeatureServer["transformation_service"] = map[string]interface{}{
+		"endpoints":                         []interface{}{"localhost:6567"},
+		"is_tls":                            true,
+		"timeout_seconds":                   0.5,
+		"max_retries":                       float64(5),
+		"initial_backoff_ms":                10,
+		"circuit_breaker_failure_threshold": 0,
+		"circuit_breaker_reset_seconds":     float64(60),
+		"health_check":                      true,
+		"require_healthy":                   true,
+	}
+	serviceConfig, err = config.GetTransformationServiceConfig()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"localhost:6566", "localhost:6567"}, serviceConfig.Endpoints)
+	assert.True(t, serviceConfig.IsTls)
+	assert.Equal(t, 500*time.Millisecond, serviceConfig.Timeout)
+	assert.Equal(t, 5, serviceConfig.MaxRetries)
+	assert.Equal(t, 10*time.Millisecond, serviceConfig.InitialBackoff)
+	assert.Equal(t, 0, serviceConfig.CircuitBreakerFailureThreshold)
+	assert.Equal(t, time.Minute, serviceConfig.CircuitBreakerResetTimeout)
+	assert.True(t, serviceConfig.HealthCheck)
+	assert.True(t, serviceConfig.RequireHealthy)
+
+	for _, options := range []map[string]interface{}{{"max_retries": -1}, {"timeout_seconds": "10"}, {"is_tls": "true"}} {
+		config.FeatureServer["transformation_service"] = options
+		_, err = config.GetTransformationServiceConfig()
+		assert.Error(t, err)
+	}
+}
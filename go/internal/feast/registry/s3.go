@@ -0,0 +1,146 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+// An S3RegistryStore is an S3 object based implementation of the RegistryStore interface.
+// Like in the Python SDK, a custom endpoint (e.g. MinIO) can be set with the FEAST_S3_ENDPOINT_URL environment variable.
+type S3RegistryStore struct {
+	filePath string
+	bucket   string
+	key      string
+	s3Client *s3.Client
+
+	// ETag and contents of the last registry read, used to skip downloading an unchanged registry
+	mu             sync.Mutex
+	etag           string
+	cachedRegistry *core.Registry
+}
+
+// NewS3RegistryStore creates an S3RegistryStore for a registry path of the form s3://bucket/key.
+func NewS3RegistryStore(config *RegistryConfig, repoPath string) (*S3RegistryStore, error) {
+	bucket, key, err := parseObjectStoragePath(config.Path, "s3")
+	if err != nil {
+		return nil, err
+	}
+	client, err := newS3Client(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &S3RegistryStore{
+		filePath: config.Path,
+		bucket:   bucket,
+		key:      key,
+		s3Client: client,
+	}, nil
+}
+
+func newS3Client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint, ok := os.LookupEnv("FEAST_S3_ENDPOINT_URL"); ok && endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	}), nil
+}
+
+// GetRegistryProto downloads and parses the registry object. The download is conditional on the ETag of the
+// previous read, so an unchanged registry is returned from memory without transferring it again.
+func (r *S3RegistryStore) GetRegistryProto() (*core.Registry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	input := &s3.GetObjectInput{Bucket: aws.String(r.bucket), Key: aws.String(r.key)}
+	if r.cachedRegistry != nil && r.etag != "" {
+		input.IfNoneMatch = aws.String(r.etag)
+	}
+	output, err := r.s3Client.GetObject(context.Background(), input)
+	if err != nil {
+		var responseError *awshttp.ResponseError
+		if r.cachedRegistry != nil && errors.As(err, &responseError) && responseError.HTTPStatusCode() == http.StatusNotModified {
+			return r.cachedRegistry, nil
+		}
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("%w at %s: %w", errRegistryNotFound, r.filePath, err)
+		}
+		return nil, fmt.Errorf("failed to read registry from %s: %w", r.filePath, err)
+	}
+	defer output.Body.Close()
+
+	in, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, err
+	}
+	registry := &core.Registry{}
+	if err := proto.Unmarshal(in, registry); err != nil {
+		return nil, err
+	}
+	r.etag = aws.ToString(output.ETag)
+	r.cachedRegistry = registry
+	return registry, nil
+}
+
+func (r *S3RegistryStore) UpdateRegistryProto(rp *core.Registry) error {
+	rp.VersionId = uuid.New().String()
+	rp.LastUpdated = timestamppb.Now()
+	data, err := proto.Marshal(rp)
+	if err != nil {
+		return err
+	}
+	_, err = r.s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(r.key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (r *S3RegistryStore) Teardown() error {
+	_, err := r.s3Client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(r.key),
+	})
+	return err
+}
+
+// parseObjectStoragePath splits a registry path like s3://bucket/path/to/registry.db into bucket and object key.
+func parseObjectStoragePath(registryPath string, scheme string) (string, string, error) {
+	uri, err := url.Parse(registryPath)
+	if err != nil {
+		return "", "", err
+	}
+	if uri.Scheme != scheme {
+		return "", "", fmt.Errorf("registry path %s must start with %s://", registryPath, scheme)
+	}
+	key := strings.TrimPrefix(uri.Path, "/")
+	if uri.Host == "" || key == "" {
+		return "", "", fmt.Errorf("registry path %s must be of the form %s://bucket/key", registryPath, scheme)
+	}
+	return uri.Host, key, nil
+}
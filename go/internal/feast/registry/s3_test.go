@@ -0,0 +1,166 @@
+package registry
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+// fakeS3 is a minimal S3-compatible object store supporting path-style GET, PUT and DELETE with ETags.
+type fakeS3 struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	downloads int
+	// If set, reads fail with this status, like when the credentials have expired
+	readStatus int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		if f.readStatus != 0 {
+			w.WriteHeader(f.readStatus)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code></Error>`)
+			return
+		}
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code></Error>`)
+			return
+		}
+		etag := etagOf(data)
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		f.downloads++
+		w.Write(data)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = data
+		w.Header().Set("ETag", etagOf(data))
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func etagOf(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func newFakeS3(t *testing.T) *fakeS3 {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	t.Setenv("FEAST_S3_ENDPOINT_URL", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	return fake
+}
+
+func TestS3RegistryStore(t *testing.T) {
+	fake := newFakeS3(t)
+	store, err := NewS3RegistryStore(&RegistryConfig{Path: "s3://feast-bucket/path/registry.db"}, "")
+	require.Nil(t, err)
+
+	_, err = store.GetRegistryProto()
+	assert.NotNil(t, err)
+
+	err = store.UpdateRegistryProto(&core.Registry{RegistrySchemaVersion: REGISTRY_SCHEMA_VERSION})
+	require.Nil(t, err)
+	assert.Contains(t, fake.objects, "/feast-bucket/path/registry.db")
+
+	first, err := store.GetRegistryProto()
+	require.Nil(t, err)
+	assert.Equal(t, REGISTRY_SCHEMA_VERSION, first.RegistrySchemaVersion)
+	assert.NotEmpty(t, first.VersionId)
+
+	// An unchanged registry is not downloaded again
+	second, err := store.GetRegistryProto()
+	require.Nil(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, fake.downloads)
+
+	err = store.UpdateRegistryProto(&core.Registry{RegistrySchemaVersion: REGISTRY_SCHEMA_VERSION, Entities: []*core.Entity{{Spec: &core.EntitySpecV2{Name: "driver"}}}})
+	require.Nil(t, err)
+	third, err := store.GetRegistryProto()
+	require.Nil(t, err)
+	assert.Equal(t, 2, fake.downloads)
+	assert.Len(t, third.Entities, 1)
+	assert.False(t, proto.Equal(first, third))
+
+	require.Nil(t, store.Teardown())
+	assert.Empty(t, fake.objects)
+}
+
+func TestRegistryWithS3RegistryStore(t *testing.T) {
+	fake := newFakeS3(t)
+	registryConfig := &RegistryConfig{Path: "s3://feast-bucket/registry.db", CacheTtlSeconds: 600}
+	r, err := NewRegistry(registryConfig, "", "feature_repo")
+	require.Nil(t, err)
+	assert.IsType(t, &S3RegistryStore{}, r.registryStore)
+
+	registryProto := &core.Registry{
+		RegistrySchemaVersion: REGISTRY_SCHEMA_VERSION,
+		Entities:              []*core.Entity{{Spec: &core.EntitySpecV2{Name: "driver", Project: "feature_repo", JoinKey: "driver_id"}}},
+	}
+	require.Nil(t, r.registryStore.UpdateRegistryProto(registryProto))
+
+	require.Nil(t, r.Refresh())
+	require.Nil(t, r.Refresh())
+	assert.Equal(t, 1, fake.downloads)
+	entity, err := r.GetEntity("feature_repo", "driver")
+	require.Nil(t, err)
+	assert.Equal(t, "driver_id", entity.JoinKey)
+}
+
+func TestParseObjectStoragePath(t *testing.T) {
+	bucket, key, err := parseObjectStoragePath("gs://bucket/path/to/registry.db", "gs")
+	assert.Nil(t, err)
+	assert.Equal(t, "bucket", bucket)
+	assert.Equal(t, "path/to/registry.db", key)
+
+	_, _, err = parseObjectStoragePath("s3://bucket", "s3")
+	assert.NotNil(t, err)
+	_, _, err = parseObjectStoragePath("gs://bucket/registry.db", "s3")
+	assert.NotNil(t, err)
+}
+
+func TestInitializeRegistryCreatesMissingS3Registry(t *testing.T) {
+	fake := newFakeS3(t)
+	r, err := NewRegistry(&RegistryConfig{Path: "s3://feast-bucket/registry.db", CacheTtlSeconds: 600}, "", "feature_repo")
+	require.Nil(t, err)
+
+	require.Nil(t, r.InitializeRegistry())
+	assert.Contains(t, fake.objects, "/feast-bucket/registry.db")
+}
+
+func TestInitializeRegistryDoesNotOverwriteS3RegistryWhenReadFails(t *testing.T) {
+	fake := newFakeS3(t)
+	fake.objects["/feast-bucket/registry.db"] = []byte("existing")
+	fake.readStatus = http.StatusForbidden
+	r, err := NewRegistry(&RegistryConfig{Path: "s3://feast-bucket/registry.db", CacheTtlSeconds: 600}, "", "feature_repo")
+	require.Nil(t, err)
+
+	assert.Error(t, r.InitializeRegistry())
+	assert.Equal(t, []byte("existing"), fake.objects["/feast-bucket/registry.db"])
+}
@@ -0,0 +1,192 @@
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+const sqlRegistryTimeout = 30 * time.Second
+
+// A SqlRegistryStore is a read-only implementation of the RegistryStore interface that reconstructs the registry
+// from the tables written by the Python SqlRegistry (registry_type: sql). Both the sqlite and the Postgres dialect
+// are supported; the path is the same SQLAlchemy database URL the Python SDK uses.
+type SqlRegistryStore struct {
+	db *sql.DB
+}
+
+// registryTable describes one of the Python SqlRegistry tables and how its protos are added to the registry.
+type registryTable struct {
+	name        string
+	protoColumn string
+	add         func(registry *core.Registry, data []byte) error
+}
+
+var registryTables = []registryTable{
+	{"projects", "project_proto", func(r *core.Registry, data []byte) error {
+		m := &core.Project{}
+		r.Projects = append(r.Projects, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"entities", "entity_proto", func(r *core.Registry, data []byte) error {
+		m := &core.Entity{}
+		r.Entities = append(r.Entities, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"data_sources", "data_source_proto", func(r *core.Registry, data []byte) error {
+		m := &core.DataSource{}
+		r.DataSources = append(r.DataSources, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"feature_views", "feature_view_proto", func(r *core.Registry, data []byte) error {
+		m := &core.FeatureView{}
+		r.FeatureViews = append(r.FeatureViews, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"stream_feature_views", "feature_view_proto", func(r *core.Registry, data []byte) error {
+		m := &core.StreamFeatureView{}
+		r.StreamFeatureViews = append(r.StreamFeatureViews, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"on_demand_feature_views", "feature_view_proto", func(r *core.Registry, data []byte) error {
+		m := &core.OnDemandFeatureView{}
+		r.OnDemandFeatureViews = append(r.OnDemandFeatureViews, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"feature_services", "feature_service_proto", func(r *core.Registry, data []byte) error {
+		m := &core.FeatureService{}
+		r.FeatureServices = append(r.FeatureServices, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"saved_datasets", "saved_dataset_proto", func(r *core.Registry, data []byte) error {
+		m := &core.SavedDataset{}
+		r.SavedDatasets = append(r.SavedDatasets, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"validation_references", "validation_reference_proto", func(r *core.Registry, data []byte) error {
+		m := &core.ValidationReference{}
+		r.ValidationReferences = append(r.ValidationReferences, m)
+		return proto.Unmarshal(data, m)
+	}},
+	{"permissions", "permission_proto", func(r *core.Registry, data []byte) error {
+		m := &core.Permission{}
+		r.Permissions = append(r.Permissions, m)
+		return proto.Unmarshal(data, m)
+	}},
+}
+
+// NewSqlRegistryStore opens the database of a SQL registry. If read_path is set, it is used instead of path
+// since the Go feature server only reads from the registry.
+func NewSqlRegistryStore(config *RegistryConfig, repoPath string) (*SqlRegistryStore, error) {
+	databaseUrl := config.Path
+	if config.ReadPath != "" {
+		databaseUrl = config.ReadPath
+	}
+	driverName, dataSourceName, err := parseSqlAlchemyUrl(databaseUrl, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return &SqlRegistryStore{db: db}, nil
+}
+
+// parseSqlAlchemyUrl converts a SQLAlchemy database URL into a database/sql driver name and data source name.
+// Relative sqlite paths are resolved against the repo path.
+func parseSqlAlchemyUrl(databaseUrl string, repoPath string) (string, string, error) {
+	scheme, rest, ok := strings.Cut(databaseUrl, "://")
+	if !ok {
+		return "", "", fmt.Errorf("sql registry path %s is not a database URL", databaseUrl)
+	}
+	// Strip the Python driver, e.g. postgresql+psycopg://
+	dialect, _, _ := strings.Cut(scheme, "+")
+	switch dialect {
+	case "sqlite":
+		// sqlite:///relative/path.db and sqlite:////absolute/path.db
+		path := strings.TrimPrefix(rest, "/")
+		if path == "" || path == ":memory:" {
+			return "", "", errors.New("sql registry requires a sqlite database file")
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(repoPath, path)
+		}
+		return "sqlite3", path, nil
+	case "postgresql", "postgres":
+		uri, err := url.Parse("postgresql://" + rest)
+		if err != nil {
+			return "", "", err
+		}
+		return "pgx", uri.String(), nil
+	default:
+		return "", "", fmt.Errorf("sql registry dialect %s is not supported; only sqlite and postgresql are supported", dialect)
+	}
+}
+
+// GetRegistryProto reads every registry table and assembles the registry proto, like SqlRegistry.proto() does.
+func (r *SqlRegistryStore) GetRegistryProto() (*core.Registry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqlRegistryTimeout)
+	defer cancel()
+
+	registry := &core.Registry{RegistrySchemaVersion: REGISTRY_SCHEMA_VERSION}
+	var lastUpdated int64
+	for _, table := range registryTables {
+		tableLastUpdated, err := r.readTable(ctx, registry, table)
+		if err != nil {
+			return nil, err
+		}
+		if tableLastUpdated > lastUpdated {
+			lastUpdated = tableLastUpdated
+		}
+	}
+	if lastUpdated > 0 {
+		registry.LastUpdated = timestamppb.New(time.Unix(lastUpdated, 0))
+	}
+	return registry, nil
+}
+
+func (r *SqlRegistryStore) readTable(ctx context.Context, registry *core.Registry, table registryTable) (int64, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf("SELECT %s, last_updated_timestamp FROM %s", table.protoColumn, table.name))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read sql registry table %s: %w", table.name, err)
+	}
+	defer rows.Close()
+
+	var lastUpdated int64
+	for rows.Next() {
+		var data []byte
+		var rowLastUpdated int64
+		if err := rows.Scan(&data, &rowLastUpdated); err != nil {
+			return 0, err
+		}
+		if err := table.add(registry, data); err != nil {
+			return 0, fmt.Errorf("failed to parse proto from sql registry table %s: %w", table.name, err)
+		}
+		if rowLastUpdated > lastUpdated {
+			lastUpdated = rowLastUpdated
+		}
+	}
+	return lastUpdated, rows.Err()
+}
+
+func (r *SqlRegistryStore) UpdateRegistryProto(rp *core.Registry) error {
+	return errors.New("sql registry is read-only; apply changes with the Python SDK")
+}
+
+// Teardown closes the database connection; the registry tables are left untouched.
+func (r *SqlRegistryStore) Teardown() error {
+	return r.db.Close()
+}
@@ -0,0 +1,100 @@
+package registry
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+// createSqlRegistry creates the tables of the Python SqlRegistry that hold protos in a sqlite database.
+func createSqlRegistry(t *testing.T, path string) *sql.DB {
+	db, err := sql.Open("sqlite3", path)
+	require.Nil(t, err)
+	t.Cleanup(func() { db.Close() })
+	for _, table := range registryTables {
+		_, err := db.Exec(fmt.Sprintf(`CREATE TABLE %s (name VARCHAR(255), project_id VARCHAR(255),
+			last_updated_timestamp BIGINT NOT NULL, %s BLOB NOT NULL, PRIMARY KEY (name, project_id))`, table.name, table.protoColumn))
+		require.Nil(t, err)
+	}
+	return db
+}
+
+func insertRegistryProto(t *testing.T, db *sql.DB, table string, protoColumn string, name string, lastUpdated int64, m proto.Message) {
+	data, err := proto.Marshal(m)
+	require.Nil(t, err)
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO %s (name, project_id, last_updated_timestamp, %s) VALUES (?, ?, ?, ?)", table, protoColumn),
+		name, "feature_repo", lastUpdated, data)
+	require.Nil(t, err)
+}
+
+func TestSqlRegistryStore(t *testing.T) {
+	dir := t.TempDir()
+	db := createSqlRegistry(t, filepath.Join(dir, "registry.db"))
+	insertRegistryProto(t, db, "entities", "entity_proto", "driver", 100,
+		&core.Entity{Spec: &core.EntitySpecV2{Name: "driver", Project: "feature_repo", JoinKey: "driver_id"}})
+	insertRegistryProto(t, db, "feature_views", "feature_view_proto", "driver_stats", 200,
+		&core.FeatureView{Spec: &core.FeatureViewSpec{Name: "driver_stats", Project: "feature_repo", Entities: []string{"driver"}}})
+	insertRegistryProto(t, db, "feature_services", "feature_service_proto", "driver_service", 150,
+		&core.FeatureService{Spec: &core.FeatureServiceSpec{Name: "driver_service", Project: "feature_repo"}})
+
+	repoConfig := &RepoConfig{Registry: map[string]interface{}{"registry_type": "sql", "path": "sqlite:///registry.db"}}
+	registryConfig, err := repoConfig.GetRegistryConfig()
+	require.Nil(t, err)
+	r, err := NewRegistry(registryConfig, dir, "feature_repo")
+	require.Nil(t, err)
+	assert.IsType(t, &SqlRegistryStore{}, r.registryStore)
+	defer r.registryStore.Teardown()
+
+	registryProto, err := r.registryStore.GetRegistryProto()
+	require.Nil(t, err)
+	assert.Len(t, registryProto.Entities, 1)
+	assert.Len(t, registryProto.FeatureViews, 1)
+	assert.Len(t, registryProto.FeatureServices, 1)
+	assert.Equal(t, int64(200), registryProto.LastUpdated.Seconds)
+
+	require.Nil(t, r.InitializeRegistry())
+	entity, err := r.GetEntity("feature_repo", "driver")
+	require.Nil(t, err)
+	assert.Equal(t, "driver_id", entity.JoinKey)
+	featureView, err := r.GetFeatureView("feature_repo", "driver_stats")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"driver"}, featureView.EntityNames)
+
+	assert.NotNil(t, r.registryStore.UpdateRegistryProto(registryProto))
+}
+
+func TestSqlRegistryStoreWithoutTables(t *testing.T) {
+	store, err := NewSqlRegistryStore(&RegistryConfig{RegistryType: "sql", Path: "sqlite:///empty.db"}, t.TempDir())
+	require.Nil(t, err)
+	defer store.Teardown()
+	_, err = store.GetRegistryProto()
+	assert.ErrorContains(t, err, "projects")
+}
+
+func TestParseSqlAlchemyUrl(t *testing.T) {
+	driverName, dataSourceName, err := parseSqlAlchemyUrl("sqlite:///data/registry.db", "/repo")
+	assert.Nil(t, err)
+	assert.Equal(t, "sqlite3", driverName)
+	assert.Equal(t, "/repo/data/registry.db", dataSourceName)
+
+	_, dataSourceName, err = parseSqlAlchemyUrl("sqlite:////tmp/registry.db", "/repo")
+	assert.Nil(t, err)
+	assert.Equal(t, "/tmp/registry.db", dataSourceName)
+
+	driverName, dataSourceName, err = parseSqlAlchemyUrl("postgresql+psycopg://feast:secret@db:5432/feast", "/repo")
+	assert.Nil(t, err)
+	assert.Equal(t, "pgx", driverName)
+	assert.Equal(t, "postgresql://feast:secret@db:5432/feast", dataSourceName)
+
+	_, _, err = parseSqlAlchemyUrl("mysql://feast@db/feast", "/repo")
+	assert.NotNil(t, err)
+	_, _, err = parseSqlAlchemyUrl("registry.db", "/repo")
+	assert.NotNil(t, err)
+}
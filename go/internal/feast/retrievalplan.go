@@ -0,0 +1,207 @@
+package feast
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/feast-dev/feast/go/internal/feast/auth"
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
+	"github.com/feast-dev/feast/go/internal/feast/transformation"
+	"github.com/feast-dev/feast/go/protos/feast/core"
+)
+
+// Maximum number of retrieval plans cached per project. Plans of feature references are keyed by the
+// references, so clients requesting arbitrary combinations of features must not grow the cache unbounded.
+const maxRetrievalPlans = 1024
+
+// A retrievalPlan is everything about a request that only depends on the requested features and the registry:
+// the views to read and compute, how to join them to the entities and the order of the returned features.
+// Plans are shared by concurrent requests and must not be modified.
+type retrievalPlan struct {
+	featureViews         []*onlineserving.FeatureViewAndRefs
+	onDemandFeatureViews []*model.OnDemandFeatureView
+	entityNameToJoinKey  map[string]string
+	expectedJoinKeys     map[string]interface{}
+	// Whether any view has no entities and is read with the dummy entity
+	entityless bool
+	// Names of the returned features, in the order they are returned in
+	featureNames []string
+	// Registry objects that the user must be allowed to read
+	resources []*auth.Resource
+}
+
+// retrievalPlanCache caches the retrieval plans of one registry version. It's cleared when the registry changes.
+type retrievalPlanCache struct {
+	mu              sync.RWMutex
+	registryVersion int64
+	plans           map[string]*retrievalPlan
+}
+
+func newRetrievalPlanCache() *retrievalPlanCache {
+	return &retrievalPlanCache{plans: make(map[string]*retrievalPlan)}
+}
+
+func (c *retrievalPlanCache) get(registryVersion int64, key string) (*retrievalPlan, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.registryVersion != registryVersion {
+		return nil, false
+	}
+	plan, ok := c.plans[key]
+	return plan, ok
+}
+
+// put caches a plan resolved from the given registry version. Plans resolved from an older version than the
+// cached ones are dropped, since the registry may have changed while they were resolved.
+func (c *retrievalPlanCache) put(registryVersion int64, key string, plan *retrievalPlan) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if registryVersion < c.registryVersion {
+		return
+	}
+	if registryVersion > c.registryVersion || len(c.plans) >= maxRetrievalPlans {
+		c.registryVersion = registryVersion
+		c.plans = make(map[string]*retrievalPlan)
+	}
+	c.plans[key] = plan
+}
+
+func featureServicePlanKey(featureService *model.FeatureService, fullFeatureNames bool) string {
+	return "service:" + strconv.FormatBool(fullFeatureNames) + ":" + featureService.Name
+}
+
+func featureRefsPlanKey(featureRefs []string, fullFeatureNames bool) string {
+	return "refs:" + strconv.FormatBool(fullFeatureNames) + ":" + strings.Join(featureRefs, ",")
+}
+
+// retrievalPlan returns the cached plan of the feature service or feature references, resolving it from the
+// registry on a cache miss. Exactly one of featureRefs and featureService is used. Feature services are
+// cached by name since they are looked up from the registry, so the cached plan is for the current version.
+func (fs *FeatureStore) retrievalPlan(
+	featureRefs []string,
+	featureService *model.FeatureService,
+	fullFeatureNames bool) (plan *retrievalPlan, cached bool, err error) {
+	var key string
+	if featureService != nil {
+		key = featureServicePlanKey(featureService, fullFeatureNames)
+	} else {
+		key = featureRefsPlanKey(featureRefs, fullFeatureNames)
+	}
+	// The version is read before resolving, so that a plan resolved while the registry changes isn't kept
+	registryVersion := fs.registry.Version()
+	if plan, ok := fs.retrievalPlans.get(registryVersion, key); ok {
+		return plan, true, nil
+	}
+	plan, err = fs.resolveRetrievalPlan(featureRefs, featureService, fullFeatureNames)
+	if err != nil {
+		return nil, false, err
+	}
+	fs.retrievalPlans.put(registryVersion, key, plan)
+	return plan, false, nil
+}
+
+func (fs *FeatureStore) resolveRetrievalPlan(
+	featureRefs []string,
+	featureService *model.FeatureService,
+	fullFeatureNames bool) (*retrievalPlan, error) {
+	fvs, odFvs, err := fs.listAllViews()
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := fs.ListEntities(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestedFeatureViews []*onlineserving.FeatureViewAndRefs
+	var requestedOnDemandFeatureViews []*model.OnDemandFeatureView
+	if featureService != nil {
+		requestedFeatureViews, requestedOnDemandFeatureViews, err =
+			onlineserving.GetFeatureViewsToUseByService(featureService, fvs, odFvs, fs.onDemandTransformationFallback)
+	} else {
+		requestedFeatureViews, requestedOnDemandFeatureViews, err =
+			onlineserving.GetFeatureViewsToUseByFeatureRefs(featureRefs, fvs, odFvs, fs.onDemandTransformationFallback)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if fs.transformationCallback == nil && fs.transformationService == nil {
+		// Without a transformation callback or service, only Substrait plans that can be executed in process can be used
+		for _, odfv := range requestedOnDemandFeatureViews {
+			if !transformation.CanTransformInProcess(odfv) {
+				return nil, FeastTransformationServiceNotConfigured{}
+			}
+		}
+	}
+
+	entityNameToJoinKeyMap, expectedJoinKeysSet, err := onlineserving.GetEntityMaps(requestedFeatureViews, entities)
+	if err != nil {
+		return nil, err
+	}
+
+	err = onlineserving.ValidateFeatureRefs(requestedFeatureViews, fullFeatureNames)
+	if err != nil {
+		return nil, err
+	}
+
+	featureNames, err := onlineserving.RequestedFeatureNames(featureRefs, featureService, fullFeatureNames)
+	if err != nil {
+		return nil, err
+	}
+
+	entitylessCase := false
+	for _, featuresAndView := range requestedFeatureViews {
+		if featuresAndView.View.HasEntity(model.DUMMY_ENTITY_NAME) {
+			entitylessCase = true
+			break
+		}
+	}
+
+	return &retrievalPlan{
+		featureViews:         requestedFeatureViews,
+		onDemandFeatureViews: requestedOnDemandFeatureViews,
+		entityNameToJoinKey:  entityNameToJoinKeyMap,
+		expectedJoinKeys:     expectedJoinKeysSet,
+		entityless:           entitylessCase,
+		featureNames:         featureNames,
+		resources:            fs.readResources(featureService, requestedFeatureViews, requestedOnDemandFeatureViews),
+	}, nil
+}
+
+// readResources returns the registry objects that reading the features requires permissions for: the feature
+// service if one was requested, and otherwise the views of the feature references.
+func (fs *FeatureStore) readResources(
+	featureService *model.FeatureService,
+	requestedFeatureViews []*onlineserving.FeatureViewAndRefs,
+	requestedOnDemandFeatureViews []*model.OnDemandFeatureView) []*auth.Resource {
+	if featureService != nil {
+		return []*auth.Resource{{Type: core.PermissionSpec_FEATURE_SERVICE, Name: featureService.Name, Tags: featureService.Tags}}
+	}
+	resources := make([]*auth.Resource, 0, len(requestedFeatureViews)+len(requestedOnDemandFeatureViews))
+	for _, featureView := range requestedFeatureViews {
+		resources = append(resources, fs.featureViewResource(featureView.View))
+	}
+	for _, onDemandFeatureView := range requestedOnDemandFeatureViews {
+		if onDemandFeatureView.WriteToOnlineStore {
+			continue
+		}
+		resources = append(resources, &auth.Resource{Type: core.PermissionSpec_ON_DEMAND_FEATURE_VIEW, Name: onDemandFeatureView.Base.Name, Tags: onDemandFeatureView.Tags})
+	}
+	return resources
+}
+
+// featureViewResource returns the registry object of a view that is read from or written to the online store.
+func (fs *FeatureStore) featureViewResource(featureView *model.FeatureView) *auth.Resource {
+	resourceType := core.PermissionSpec_FEATURE_VIEW
+	if _, err := fs.registry.GetStreamFeatureView(fs.config.Project, featureView.Base.Name); err == nil {
+		resourceType = core.PermissionSpec_STREAM_FEATURE_VIEW
+	} else if _, err := fs.registry.GetOnDemandFeatureView(fs.config.Project, featureView.Base.Name); err == nil {
+		// On demand feature views written to the online store are read like feature views
+		resourceType = core.PermissionSpec_ON_DEMAND_FEATURE_VIEW
+	}
+	return &auth.Resource{Type: resourceType, Name: featureView.Base.Name, Tags: featureView.Tags}
+}
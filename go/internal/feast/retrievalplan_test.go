@@ -0,0 +1,174 @@
+package feast
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/core"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+func driverRegistryForTest(features ...string) *core.Registry {
+	featureSpecs := make([]*core.FeatureSpecV2, len(features))
+	for index, feature := range features {
+		featureSpecs[index] = &core.FeatureSpecV2{Name: feature, ValueType: types.ValueType_FLOAT}
+	}
+	return &core.Registry{
+		Entities: []*core.Entity{{Spec: &core.EntitySpecV2{Name: "driver", Project: "feature_repo", JoinKey: "driver_id"}}},
+		FeatureViews: []*core.FeatureView{{Spec: &core.FeatureViewSpec{
+			Name:     "driver_stats",
+			Project:  "feature_repo",
+			Entities: []string{"driver"},
+			Features: featureSpecs,
+			Ttl:      &durationpb.Duration{Seconds: 3600},
+		}}},
+		FeatureServices: []*core.FeatureService{{Spec: &core.FeatureServiceSpec{
+			Name:     "driver_service",
+			Project:  "feature_repo",
+			Features: []*core.FeatureViewProjection{{FeatureViewName: "driver_stats", FeatureColumns: featureSpecs}},
+		}, Meta: &core.FeatureServiceMeta{}}},
+	}
+}
+
+func newFeatureStoreForPlanTest(t *testing.T) (*FeatureStore, *registry.FileRegistryStore) {
+	repoPath := t.TempDir()
+	registryPath := filepath.Join(repoPath, "registry.db")
+	registryStore := registry.NewFileRegistryStore(&registry.RegistryConfig{Path: registryPath}, repoPath)
+	require.Nil(t, registryStore.UpdateRegistryProto(driverRegistryForTest("conv_rate")))
+	fs, err := NewFeatureStore(&registry.RepoConfig{
+		Project:     "feature_repo",
+		RepoPath:    repoPath,
+		Registry:    map[string]interface{}{"path": registryPath},
+		Provider:    "local",
+		OnlineStore: map[string]interface{}{"type": "sqlite", "path": "online_store.db"},
+	}, nil)
+	require.Nil(t, err)
+	t.Cleanup(fs.DestructOnlineStore)
+	return fs, registryStore
+}
+
+func TestRetrievalPlanIsCachedUntilRegistryChanges(t *testing.T) {
+	fs, registryStore := newFeatureStoreForPlanTest(t)
+
+	plan, cached, err := fs.retrievalPlan([]string{"driver_stats:conv_rate"}, nil, false)
+	require.Nil(t, err)
+	assert.False(t, cached)
+	assert.Equal(t, []string{"conv_rate"}, plan.featureNames)
+	assert.Equal(t, map[string]string{"driver": "driver_id"}, plan.entityNameToJoinKey)
+	assert.False(t, plan.entityless)
+	require.Len(t, plan.resources, 1)
+	assert.Equal(t, core.PermissionSpec_FEATURE_VIEW, plan.resources[0].Type)
+
+	cachedPlan, cached, err := fs.retrievalPlan([]string{"driver_stats:conv_rate"}, nil, false)
+	require.Nil(t, err)
+	assert.True(t, cached)
+	assert.Same(t, plan, cachedPlan)
+
+	fullNamesPlan, cached, err := fs.retrievalPlan([]string{"driver_stats:conv_rate"}, nil, true)
+	require.Nil(t, err)
+	assert.False(t, cached)
+	assert.Equal(t, []string{"driver_stats__conv_rate"}, fullNamesPlan.featureNames)
+
+	featureService, err := fs.GetFeatureService("driver_service")
+	require.Nil(t, err)
+	servicePlan, _, err := fs.retrievalPlan(nil, featureService, false)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"conv_rate"}, servicePlan.featureNames)
+	assert.Equal(t, core.PermissionSpec_FEATURE_SERVICE, servicePlan.resources[0].Type)
+
+	require.Nil(t, registryStore.UpdateRegistryProto(driverRegistryForTest("conv_rate", "acc_rate")))
+	require.Nil(t, fs.Registry().Refresh())
+
+	featureService, err = fs.GetFeatureService("driver_service")
+	require.Nil(t, err)
+	servicePlan, cached, err = fs.retrievalPlan(nil, featureService, false)
+	require.Nil(t, err)
+	assert.False(t, cached)
+	assert.Equal(t, []string{"conv_rate", "acc_rate"}, servicePlan.featureNames)
+}
+
+func TestRetrievalPlanErrorsAreNotCached(t *testing.T) {
+	fs, _ := newFeatureStoreForPlanTest(t)
+
+	_, _, err := fs.retrievalPlan([]string{"unknown_view:conv_rate"}, nil, false)
+	assert.Error(t, err)
+	assert.Empty(t, fs.retrievalPlans.plans)
+}
+
+func TestRetrievalPlanCacheDropsPlansOfOlderRegistryVersions(t *testing.T) {
+	cache := newRetrievalPlanCache()
+	plan := &retrievalPlan{}
+
+	cache.put(2, "a", plan)
+	cached, ok := cache.get(2, "a")
+	assert.True(t, ok)
+	assert.Same(t, plan, cached)
+	_, ok = cache.get(3, "a")
+	assert.False(t, ok)
+
+	cache.put(1, "b", plan)
+	_, ok = cache.get(1, "b")
+	assert.False(t, ok)
+
+	cache.put(3, "c", plan)
+	_, ok = cache.get(3, "c")
+	assert.True(t, ok)
+	assert.Len(t, cache.plans, 1)
+}
+
+func TestRetrievalPlanCacheIsBounded(t *testing.T) {
+	cache := newRetrievalPlanCache()
+	for index := 0; index <= maxRetrievalPlans; index++ {
+		cache.put(0, featureRefsPlanKey([]string{string(rune('a' + index%26)), string(rune(index))}, false), &retrievalPlan{})
+	}
+	assert.LessOrEqual(t, len(cache.plans), maxRetrievalPlans)
+}
+
+func TestRetrievalPlanReadsWrittenOnDemandFeatureViews(t *testing.T) {
+	fs, registryStore := newFeatureStoreForPlanTest(t)
+	registryProto := driverRegistryForTest("conv_rate")
+	registryProto.OnDemandFeatureViews = []*core.OnDemandFeatureView{{Spec: &core.OnDemandFeatureViewSpec{
+		Name:               "conv_rate_doubled",
+		Project:            "feature_repo",
+		Entities:           []string{"driver"},
+		Features:           []*core.FeatureSpecV2{{Name: "conv_rate_times_two", ValueType: types.ValueType_DOUBLE}},
+		WriteToOnlineStore: true,
+		Sources: map[string]*core.OnDemandSource{"driver_stats": {Source: &core.OnDemandSource_FeatureViewProjection{
+			FeatureViewProjection: &core.FeatureViewProjection{
+				FeatureViewName: "driver_stats",
+				FeatureColumns:  []*core.FeatureSpecV2{{Name: "conv_rate", ValueType: types.ValueType_FLOAT}},
+			},
+		}}},
+	}}}
+	require.Nil(t, registryStore.UpdateRegistryProto(registryProto))
+	require.Nil(t, fs.Registry().Refresh())
+
+	// The view is read from the online store, so no transformation is needed
+	plan, _, err := fs.retrievalPlan([]string{"conv_rate_doubled:conv_rate_times_two"}, nil, true)
+	require.Nil(t, err)
+	assert.Empty(t, plan.onDemandFeatureViews)
+	require.Len(t, plan.featureViews, 1)
+	assert.Equal(t, "conv_rate_doubled", plan.featureViews[0].View.Base.Name)
+	assert.Equal(t, map[string]string{"driver": "driver_id"}, plan.entityNameToJoinKey)
+	assert.Equal(t, []string{"conv_rate_doubled__conv_rate_times_two"}, plan.featureNames)
+	require.Len(t, plan.resources, 1)
+	assert.Equal(t, core.PermissionSpec_ON_DEMAND_FEATURE_VIEW, plan.resources[0].Type)
+
+	// With the transformation fallback the view is also transformed, which requires a transformation
+	fs.onDemandTransformationFallback = true
+	fs.retrievalPlans = newRetrievalPlanCache()
+	_, _, err = fs.retrievalPlan([]string{"conv_rate_doubled:conv_rate_times_two"}, nil, true)
+	assert.Equal(t, FeastTransformationServiceNotConfigured{}, err)
+
+	fs.transformationCallback = func(string, uintptr, uintptr, uintptr, uintptr, bool) int { return 0 }
+	plan, _, err = fs.retrievalPlan([]string{"conv_rate_doubled:conv_rate_times_two"}, nil, true)
+	require.Nil(t, err)
+	require.Len(t, plan.onDemandFeatureViews, 1)
+	assert.Len(t, plan.featureViews, 2)
+	require.Len(t, plan.resources, 2)
+}
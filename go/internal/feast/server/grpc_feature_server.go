@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/feast-dev/feast/go/internal/feast"
+	"github.com/feast-dev/feast/go/internal/feast/server/logging"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+// grpcFeatureServer implements the GrpcFeatureServer service of the Python feature server, so that clients
+// can push data to the Go feature server the same way.
+type grpcFeatureServer struct {
+	servingServer *grpcServingServiceServer
+	serving.UnimplementedGrpcFeatureServerServer
+}
+
+func NewGrpcFeatureServer(fs *feast.FeatureStore, loggingService *logging.LoggingService) *grpcFeatureServer {
+	return &grpcFeatureServer{servingServer: NewGrpcServingServiceServer(fs, loggingService)}
+}
+
+// Push writes a single row to the feature views fed by the push source named in request.StreamFeatureView,
+// which is how the Python feature server interprets that field.
+func (s *grpcFeatureServer) Push(ctx context.Context, request *serving.PushRequest) (*serving.PushResponse, error) {
+	to, err := feast.ParsePushMode(request.GetTo())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err = s.servingServer.fs.Push(ctx, request.GetStreamFeatureView(), stringMapToColumns(request.GetFeatures()), to, request.GetAllowRegistryCache())
+	if err != nil {
+		return nil, err
+	}
+	return &serving.PushResponse{Status: true}, nil
+}
+
+func (s *grpcFeatureServer) WriteToOnlineStore(ctx context.Context, request *serving.WriteToOnlineStoreRequest) (*serving.WriteToOnlineStoreResponse, error) {
+	err := s.servingServer.fs.WriteToOnlineStore(ctx, request.GetFeatureViewName(), stringMapToColumns(request.GetFeatures()), request.GetAllowRegistryCache())
+	if err != nil {
+		return nil, err
+	}
+	return &serving.WriteToOnlineStoreResponse{Status: true}, nil
+}
+
+func (s *grpcFeatureServer) GetOnlineFeatures(ctx context.Context, request *serving.GetOnlineFeaturesRequest) (*serving.GetOnlineFeaturesResponse, error) {
+	return s.servingServer.GetOnlineFeatures(ctx, request)
+}
+
+// stringMapToColumns converts the features of a push request into single-row columns. Values are converted into
+// the feature types when the rows are built.
+func stringMapToColumns(features map[string]string) map[string]*prototypes.RepeatedValue {
+	columns := make(map[string]*prototypes.RepeatedValue, len(features))
+	for name, value := range features {
+		columns[name] = &prototypes.RepeatedValue{Val: []*prototypes.Value{{Val: &prototypes.Value_StringVal{StringVal: value}}}}
+	}
+	return columns
+}
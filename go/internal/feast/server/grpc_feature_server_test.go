@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/feast-dev/feast/go/internal/feast/auth"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+)
+
+func TestStringMapToColumns(t *testing.T) {
+	columns := stringMapToColumns(map[string]string{"driver_id": "1001", "conv_rate": "0.5"})
+	assert.Len(t, columns, 2)
+	assert.Len(t, columns["driver_id"].Val, 1)
+	assert.Equal(t, "1001", columns["driver_id"].Val[0].GetStringVal())
+	assert.Equal(t, "0.5", columns["conv_rate"].Val[0].GetStringVal())
+}
+
+func TestPushRejectsInvalidPushMode(t *testing.T) {
+	s := NewGrpcFeatureServer(nil, nil)
+	_, err := s.Push(context.Background(), &serving.PushRequest{StreamFeatureView: "driver_stats_push_source", To: "nowhere"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "invalid push mode nowhere")
+}
+
+func TestPushAndWriteToOnlineStoreEnforcePermissions(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	s := NewGrpcFeatureServer(newFeatureStoreWithPermissions(t, key), nil)
+	features := map[string]string{"driver_id": "1001", "conv_rate": "0.5"}
+	reader := auth.NewContext(context.Background(), &auth.User{Username: "alice", Roles: []string{"reader"}})
+
+	_, err = s.Push(reader, &serving.PushRequest{StreamFeatureView: "driver_stats_push_source", Features: features})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.WriteToOnlineStore(reader, &serving.WriteToOnlineStoreRequest{FeatureViewName: "driver_stats", Features: features})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.ErrorContains(t, err, "WRITE_ONLINE")
+
+	writer := auth.NewContext(context.Background(), &auth.User{Username: "bob", Roles: []string{"writer"}})
+	_, err = s.WriteToOnlineStore(writer, &serving.WriteToOnlineStoreRequest{FeatureViewName: "driver_stats", Features: features})
+	assert.NotEqual(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestWriteToOnlineStoreRejectsMalformedData(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	s := NewGrpcFeatureServer(newFeatureStoreWithPermissions(t, key), nil)
+	writer := auth.NewContext(context.Background(), &auth.User{Username: "bob", Roles: []string{"writer"}})
+
+	_, err = s.WriteToOnlineStore(writer, &serving.WriteToOnlineStoreRequest{FeatureViewName: "unknown_view", Features: map[string]string{"driver_id": "1001"}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.WriteToOnlineStore(writer, &serving.WriteToOnlineStoreRequest{FeatureViewName: "driver_stats", Features: map[string]string{"conv_rate": "0.5"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "missing join key driver_id")
+
+	_, err = s.Push(writer, &serving.PushRequest{StreamFeatureView: "driver_stats_push_source", Features: map[string]string{"driver_id": "1001", "conv_rate": "fast"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
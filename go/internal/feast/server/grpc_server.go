@@ -5,11 +5,12 @@ import (
 	"fmt"
 	"github.com/feast-dev/feast/go/internal/feast"
 	"github.com/feast-dev/feast/go/internal/feast/server/logging"
+	"github.com/feast-dev/feast/go/internal/feast/tracing"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
 	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
 	"github.com/feast-dev/feast/go/types"
 	"github.com/google/uuid"
-	//"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const feastServerVersion = "0.0.1"
@@ -34,20 +35,21 @@ func (s *grpcServingServiceServer) GetFeastServingInfo(ctx context.Context, requ
 // Metadata contains feature names that corresponds to the number of rows in response.Results.
 // Results contains values including the value of the feature, the event timestamp, and feature status in a columnar format.
 func (s *grpcServingServiceServer) GetOnlineFeatures(ctx context.Context, request *serving.GetOnlineFeaturesRequest) (*serving.GetOnlineFeaturesResponse, error) {
-	//span, ctx := tracer.StartSpanFromContext(ctx, "getOnlineFeatures", tracer.ResourceName("ServingService/GetOnlineFeatures"))
-	//defer span.Finish()
-
 	//logSpanContext := LogWithSpanContext(span)
 
 	requestId := GenerateRequestId()
-	featuresOrService, err := s.fs.ParseFeatures(request.GetKind())
+	fs, err := s.fs.ForProject(request.GetProject())
+	if err != nil {
+		return nil, err
+	}
+	featuresOrService, err := fs.ParseFeatures(request.GetKind())
 
 	if err != nil {
 		//logSpanContext.Error().Err(err).Msg("Error parsing feature service or feature list from request")
 		return nil, err
 	}
 
-	featureVectors, err := s.fs.GetOnlineFeatures(
+	featureVectors, err := fs.GetOnlineFeatures(
 		ctx,
 		featuresOrService.FeaturesRefs,
 		featuresOrService.FeatureService,
@@ -90,7 +92,9 @@ func (s *grpcServingServiceServer) GetOnlineFeatures(ctx context.Context, reques
 	}
 
 	featureService := featuresOrService.FeatureService
-	if featureService != nil && featureService.LoggingConfig != nil && s.loggingService != nil {
+	// Logged features are written to the offline store of the server's project, so only its feature services are logged
+	if featureService != nil && featureService.LoggingConfig != nil && s.loggingService != nil && fs == s.fs {
+		_, span := tracing.StartSpan(ctx, "LogFeatures", attribute.String("feast.feature_service", featureService.Name))
 		logger, err := s.loggingService.GetOrCreateLogger(featureService)
 		if err != nil {
 			//logSpanContext.Error().Err(err).Msg("Error to instantiating logger for feature service: " + featuresOrService.FeatureService.Name)
@@ -102,6 +106,7 @@ func (s *grpcServingServiceServer) GetOnlineFeatures(ctx context.Context, reques
 			//logSpanContext.Error().Err(err).Msg("Error to logging to feature service: " + featuresOrService.FeatureService.Name)
 			fmt.Printf("LoggerImpl error[%s]: %+v", featuresOrService.FeatureService.Name, err)
 		}
+		tracing.EndSpan(span, err)
 	}
 	return resp, nil
 }
@@ -0,0 +1,262 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/ipc"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
+	"github.com/feast-dev/feast/go/types"
+)
+
+// Media types of the requests and responses of /get-online-features
+const (
+	jsonMediaType        = "application/json"
+	protobufMediaType    = "application/x-protobuf"
+	arrowStreamMediaType = "application/vnd.apache.arrow.stream"
+)
+
+// onlineFeaturesRequest is a /get-online-features request decoded from JSON or a GetOnlineFeaturesRequest proto.
+type onlineFeaturesRequest struct {
+	featureService   *string
+	features         []string
+	entities         map[string]*prototypes.RepeatedValue
+	requestContext   map[string]*prototypes.RepeatedValue
+	fullFeatureNames bool
+	project          string
+}
+
+// requestMediaType returns the format of the request body. Bodies that aren't protobuf are decoded as JSON, like
+// they were before other formats were supported, since clients such as curl send JSON as form data by default.
+func requestMediaType(r *http.Request) string {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err == nil && (mediaType == protobufMediaType || mediaType == "application/protobuf") {
+		return protobufMediaType
+	}
+	return jsonMediaType
+}
+
+// responseMediaType returns the supported format that the Accept header prefers, or false if it accepts none of
+// them. Requests without an Accept header, or that accept any type, get a response in the format of the request.
+func responseMediaType(accept string, requestMediaType string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return requestMediaType, true
+	}
+	type acceptedType struct {
+		mediaType string
+		quality   float64
+	}
+	acceptedTypes := make([]acceptedType, 0)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			acceptedTypes = append(acceptedTypes, acceptedType{mediaType, quality})
+		}
+	}
+	sort.SliceStable(acceptedTypes, func(i, j int) bool { return acceptedTypes[i].quality > acceptedTypes[j].quality })
+	for _, accepted := range acceptedTypes {
+		switch accepted.mediaType {
+		case jsonMediaType, protobufMediaType, arrowStreamMediaType:
+			return accepted.mediaType, true
+		case "application/protobuf":
+			return protobufMediaType, true
+		case "*/*", "application/*":
+			return requestMediaType, true
+		}
+	}
+	return "", false
+}
+
+func decodeJSONOnlineFeaturesRequest(body io.Reader) (*onlineFeaturesRequest, error) {
+	var request getOnlineFeaturesRequest
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		return nil, err
+	}
+	entities := make(map[string]*prototypes.RepeatedValue)
+	for key, value := range request.Entities {
+		entities[key] = value.ToProto()
+	}
+	requestContext := make(map[string]*prototypes.RepeatedValue)
+	for key, value := range request.RequestContext {
+		requestContext[key] = value.ToProto()
+	}
+	return &onlineFeaturesRequest{
+		featureService:   request.FeatureService,
+		features:         request.Features,
+		entities:         entities,
+		requestContext:   requestContext,
+		fullFeatureNames: request.FullFeatureNames,
+		project:          request.Project,
+	}, nil
+}
+
+func decodeProtobufOnlineFeaturesRequest(body io.Reader) (*onlineFeaturesRequest, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	var request serving.GetOnlineFeaturesRequest
+	if err := proto.Unmarshal(data, &request); err != nil {
+		return nil, err
+	}
+	decoded := &onlineFeaturesRequest{
+		features:         request.GetFeatures().GetVal(),
+		entities:         request.GetEntities(),
+		requestContext:   request.GetRequestContext(),
+		fullFeatureNames: request.GetFullFeatureNames(),
+		project:          request.GetProject(),
+	}
+	if featureService, ok := request.GetKind().(*serving.GetOnlineFeaturesRequest_FeatureService); ok {
+		decoded.featureService = &featureService.FeatureService
+	}
+	if decoded.entities == nil {
+		decoded.entities = make(map[string]*prototypes.RepeatedValue)
+	}
+	if decoded.requestContext == nil {
+		decoded.requestContext = make(map[string]*prototypes.RepeatedValue)
+	}
+	return decoded, nil
+}
+
+func writeJSONOnlineFeatures(w http.ResponseWriter, featureVectors []*onlineserving.FeatureVector, status bool) error {
+	var featureNames []string
+	var results []map[string]interface{}
+	for _, vector := range featureVectors {
+		featureNames = append(featureNames, vector.Name)
+		result := make(map[string]interface{})
+		if status {
+			var statuses []string
+			for _, status := range vector.Statuses {
+				statuses = append(statuses, status.String())
+			}
+			var timestamps []string
+			for _, timestamp := range vector.Timestamps {
+				timestamps = append(timestamps, timestamp.AsTime().Format(time.RFC3339))
+			}
+
+			result["statuses"] = statuses
+			result["event_timestamps"] = timestamps
+		}
+		// Note, that vector.Values is an Arrow Array, but this type implements JSON Marshaller.
+		// So, it's not necessary to pre-process it in any way.
+		result["values"] = vector.Values
+
+		results = append(results, result)
+	}
+
+	response := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"feature_names": featureNames,
+		},
+		"results": results,
+	}
+
+	w.Header().Set("Content-Type", jsonMediaType)
+	return json.NewEncoder(w).Encode(response)
+}
+
+// writeProtobufOnlineFeatures writes the GetOnlineFeaturesResponse that the gRPC server would return. Statuses
+// and event timestamps are always included, like in gRPC responses.
+func writeProtobufOnlineFeatures(w http.ResponseWriter, featureVectors []*onlineserving.FeatureVector) error {
+	response := &serving.GetOnlineFeaturesResponse{
+		Results: make([]*serving.GetOnlineFeaturesResponse_FeatureVector, 0, len(featureVectors)),
+		Metadata: &serving.GetOnlineFeaturesResponseMetadata{
+			FeatureNames: &serving.FeatureList{Val: make([]string, 0, len(featureVectors))},
+		},
+	}
+	for _, vector := range featureVectors {
+		values, err := types.ArrowValuesToProtoValues(vector.Values)
+		if err != nil {
+			return err
+		}
+		response.Metadata.FeatureNames.Val = append(response.Metadata.FeatureNames.Val, vector.Name)
+		response.Results = append(response.Results, &serving.GetOnlineFeaturesResponse_FeatureVector{
+			Values:          values,
+			Statuses:        vector.Statuses,
+			EventTimestamps: vector.Timestamps,
+		})
+	}
+	data, err := proto.Marshal(response)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", protobufMediaType)
+	_, err = w.Write(data)
+	return err
+}
+
+// writeArrowOnlineFeatures writes the feature vectors as a single record in the Arrow IPC streaming format, with a
+// column per feature named like the feature. With status, each feature is followed by a <feature>__status column
+// with the names of the field statuses and a <feature>__event_timestamp column.
+func writeArrowOnlineFeatures(w http.ResponseWriter, featureVectors []*onlineserving.FeatureVector, status bool) error {
+	allocator := memory.DefaultAllocator
+	fields := make([]arrow.Field, 0, len(featureVectors))
+	columns := make([]arrow.Array, 0, len(featureVectors))
+	defer func() {
+		// The values are retained, so that they can be released like the status columns created here
+		for _, column := range columns {
+			column.Release()
+		}
+	}()
+	numRows := 0
+	for _, vector := range featureVectors {
+		vector.Values.Retain()
+		fields = append(fields, arrow.Field{Name: vector.Name, Type: vector.Values.DataType(), Nullable: true})
+		columns = append(columns, vector.Values)
+		numRows = vector.Values.Len()
+		if !status {
+			continue
+		}
+
+		statusBuilder := array.NewStringBuilder(allocator)
+		for _, fieldStatus := range vector.Statuses {
+			statusBuilder.Append(fieldStatus.String())
+		}
+		fields = append(fields, arrow.Field{Name: vector.Name + "__status", Type: arrow.BinaryTypes.String})
+		columns = append(columns, statusBuilder.NewArray())
+		statusBuilder.Release()
+
+		timestampType := arrow.FixedWidthTypes.Timestamp_us.(*arrow.TimestampType)
+		timestampBuilder := array.NewTimestampBuilder(allocator, timestampType)
+		for _, timestamp := range vector.Timestamps {
+			timestampBuilder.Append(arrow.Timestamp(timestamp.AsTime().UnixMicro()))
+		}
+		fields = append(fields, arrow.Field{Name: vector.Name + "__event_timestamp", Type: timestampType})
+		columns = append(columns, timestampBuilder.NewArray())
+		timestampBuilder.Release()
+	}
+
+	schema := arrow.NewSchema(fields, nil)
+	record := array.NewRecord(schema, columns, int64(numRows))
+	defer record.Release()
+
+	w.Header().Set("Content-Type", arrowStreamMediaType)
+	writer := ipc.NewWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(allocator))
+	if err := writer.Write(record); err != nil {
+		return fmt.Errorf("Error writing Arrow record: %+v", err)
+	}
+	return writer.Close()
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/ipc"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	"github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+func TestResponseMediaType(t *testing.T) {
+	for _, tc := range []struct {
+		accept      string
+		requestType string
+		expected    string
+	}{
+		{"", jsonMediaType, jsonMediaType},
+		{"", protobufMediaType, protobufMediaType},
+		{"*/*", protobufMediaType, protobufMediaType},
+		{"application/json", protobufMediaType, jsonMediaType},
+		{"application/protobuf", jsonMediaType, protobufMediaType},
+		{"application/vnd.apache.arrow.stream", jsonMediaType, arrowStreamMediaType},
+		{"text/html, application/x-protobuf;q=0.5, application/vnd.apache.arrow.stream;q=0.9", jsonMediaType, arrowStreamMediaType},
+		{"application/json;q=0, */*;q=0.1", jsonMediaType, jsonMediaType},
+	} {
+		mediaType, ok := responseMediaType(tc.accept, tc.requestType)
+		assert.True(t, ok, tc.accept)
+		assert.Equal(t, tc.expected, mediaType, tc.accept)
+	}
+
+	_, ok := responseMediaType("text/html, application/json;q=0", jsonMediaType)
+	assert.False(t, ok)
+}
+
+func TestGetOnlineFeaturesDecodesProtobufRequests(t *testing.T) {
+	s := NewHttpServer(newMultiProjectFeatureStore(t), nil)
+	getOnlineFeatures := func(body []byte, contentType string, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/get-online-features", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rr := httptest.NewRecorder()
+		s.getOnlineFeatures(rr, req)
+		return rr
+	}
+
+	body, err := proto.Marshal(&serving.GetOnlineFeaturesRequest{
+		Kind:     &serving.GetOnlineFeaturesRequest_Features{Features: &serving.FeatureList{Val: []string{"driver_stats:conv_rate"}}},
+		Entities: map[string]*types.RepeatedValue{"driver_id": {Val: []*types.Value{{Val: &types.Value_Int64Val{Int64Val: 1001}}}}},
+		Project:  "unknown_repo",
+	})
+	require.Nil(t, err)
+	rr := getOnlineFeatures(body, "application/x-protobuf", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "unknown_repo")
+
+	body, err = proto.Marshal(&serving.GetOnlineFeaturesRequest{
+		Kind:    &serving.GetOnlineFeaturesRequest_FeatureService{FeatureService: "unknown_service"},
+		Project: "other_repo",
+	})
+	require.Nil(t, err)
+	rr = getOnlineFeatures(body, "application/x-protobuf", "")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "other_repo")
+
+	rr = getOnlineFeatures([]byte("not a protobuf"), "application/x-protobuf", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = getOnlineFeatures(body, "application/x-protobuf", "text/html")
+	assert.Equal(t, http.StatusNotAcceptable, rr.Code)
+
+	// Bodies that aren't protobuf are still decoded as JSON
+	rr = getOnlineFeatures([]byte(`{"project": "unknown_repo", "features": ["driver_stats:conv_rate"]}`), "application/x-www-form-urlencoded", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func featureVectorsForTest(t *testing.T) []*onlineserving.FeatureVector {
+	eventTs := time.Unix(1704164645, 0)
+	driverIds := array.NewInt64Builder(memory.DefaultAllocator)
+	driverIds.AppendValues([]int64{1001, 1002}, nil)
+	convRates := array.NewFloat32Builder(memory.DefaultAllocator)
+	convRates.AppendValues([]float32{0.5, 0}, []bool{true, false})
+	vectors := []*onlineserving.FeatureVector{
+		{
+			Name:       "driver_id",
+			Values:     driverIds.NewArray(),
+			Statuses:   []serving.FieldStatus{serving.FieldStatus_PRESENT, serving.FieldStatus_PRESENT},
+			Timestamps: []*timestamppb.Timestamp{timestamppb.New(eventTs), timestamppb.New(eventTs)},
+		},
+		{
+			Name:       "conv_rate",
+			Values:     convRates.NewArray(),
+			Statuses:   []serving.FieldStatus{serving.FieldStatus_PRESENT, serving.FieldStatus_NOT_FOUND},
+			Timestamps: []*timestamppb.Timestamp{timestamppb.New(eventTs), {}},
+		},
+	}
+	t.Cleanup(func() {
+		for _, vector := range vectors {
+			vector.Values.Release()
+		}
+	})
+	return vectors
+}
+
+func TestWriteProtobufOnlineFeatures(t *testing.T) {
+	rr := httptest.NewRecorder()
+	require.Nil(t, writeProtobufOnlineFeatures(rr, featureVectorsForTest(t)))
+	assert.Equal(t, protobufMediaType, rr.Header().Get("Content-Type"))
+
+	var response serving.GetOnlineFeaturesResponse
+	require.Nil(t, proto.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, []string{"driver_id", "conv_rate"}, response.Metadata.FeatureNames.Val)
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, int64(1002), response.Results[0].Values[1].GetInt64Val())
+	assert.Equal(t, float32(0.5), response.Results[1].Values[0].GetFloatVal())
+	assert.Equal(t, serving.FieldStatus_NOT_FOUND, response.Results[1].Statuses[1])
+	assert.Equal(t, int64(1704164645), response.Results[1].EventTimestamps[0].Seconds)
+}
+
+func TestWriteArrowOnlineFeatures(t *testing.T) {
+	vectors := featureVectorsForTest(t)
+	rr := httptest.NewRecorder()
+	require.Nil(t, writeArrowOnlineFeatures(rr, vectors, false))
+	assert.Equal(t, arrowStreamMediaType, rr.Header().Get("Content-Type"))
+
+	reader, err := ipc.NewReader(rr.Body)
+	require.Nil(t, err)
+	defer reader.Release()
+	require.True(t, reader.Next())
+	record := reader.Record()
+	assert.Equal(t, int64(2), record.NumRows())
+	assert.Equal(t, []string{"driver_id", "conv_rate"}, []string{record.ColumnName(0), record.ColumnName(1)})
+	assert.True(t, array.Equal(vectors[1].Values, record.Column(1)))
+	assert.False(t, reader.Next())
+
+	rr = httptest.NewRecorder()
+	require.Nil(t, writeArrowOnlineFeatures(rr, vectors, true))
+	statusReader, err := ipc.NewReader(rr.Body)
+	require.Nil(t, err)
+	defer statusReader.Release()
+	require.True(t, statusReader.Next())
+	record = statusReader.Record()
+	require.Equal(t, int64(6), record.NumCols())
+	assert.Equal(t, "conv_rate__status", record.ColumnName(4))
+	assert.Equal(t, "NOT_FOUND", record.Column(4).(*array.String).Value(1))
+	assert.Equal(t, "conv_rate__event_timestamp", record.ColumnName(5))
+	assert.Equal(t, arrow.Timestamp(1704164645000000), record.Column(5).(*array.Timestamp).Value(0))
+}
+
+func TestWriteJSONOnlineFeatures(t *testing.T) {
+	rr := httptest.NewRecorder()
+	require.Nil(t, writeJSONOnlineFeatures(rr, featureVectorsForTest(t), true))
+	assert.Equal(t, jsonMediaType, rr.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rr.Body.String(), `{"metadata":{"feature_names":["driver_id","conv_rate"]}`))
+	assert.Contains(t, rr.Body.String(), `"statuses":["PRESENT","NOT_FOUND"]`)
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	//"os"
@@ -12,13 +13,17 @@ import (
 	"time"
 
 	"github.com/feast-dev/feast/go/internal/feast"
+	"github.com/feast-dev/feast/go/internal/feast/auth"
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
 	"github.com/feast-dev/feast/go/internal/feast/model"
 	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
 	"github.com/feast-dev/feast/go/internal/feast/server/logging"
+	"github.com/feast-dev/feast/go/internal/feast/tracing"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
 	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
 	"github.com/feast-dev/feast/go/types"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 	//httptrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/net/http"
 	//"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 )
@@ -140,6 +145,14 @@ type getOnlineFeaturesRequest struct {
 	Entities         map[string]repeatedValue `json:"entities"`
 	FullFeatureNames bool                     `json:"full_feature_names"`
 	RequestContext   map[string]repeatedValue `json:"request_context"`
+	Project          string                   `json:"project"`
+}
+
+type pushRequest struct {
+	PushSourceName     string                   `json:"push_source_name"`
+	Df                 map[string]repeatedValue `json:"df"`
+	AllowRegistryCache *bool                    `json:"allow_registry_cache"`
+	To                 string                   `json:"to"`
 }
 
 func NewHttpServer(fs *feast.FeatureStore, loggingService *logging.LoggingService) *httpServer {
@@ -150,8 +163,6 @@ func (s *httpServer) getOnlineFeatures(w http.ResponseWriter, r *http.Request) {
 	var err error
 
 	ctx := r.Context()
-	//span, ctx := tracer.StartSpanFromContext(r.Context(), "getOnlineFeatures", tracer.ResourceName("/get-online-features"))
-	//defer span.Finish(tracer.WithError(err))
 
 	//logSpanContext := LogWithSpanContext(span)
 
@@ -172,123 +183,189 @@ func (s *httpServer) getOnlineFeatures(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	decoder := json.NewDecoder(r.Body)
-	var request getOnlineFeaturesRequest
-	err = decoder.Decode(&request)
+	requestType := requestMediaType(r)
+	responseType, ok := responseMediaType(r.Header.Get("Accept"), requestType)
+	if !ok {
+		writeJSONError(w, fmt.Errorf("Accept header must allow one of %s, %s or %s", jsonMediaType, protobufMediaType, arrowStreamMediaType), http.StatusNotAcceptable)
+		return
+	}
+
+	var request *onlineFeaturesRequest
+	if requestType == protobufMediaType {
+		request, err = decodeProtobufOnlineFeaturesRequest(r.Body)
+		if err != nil {
+			writeJSONError(w, fmt.Errorf("Error decoding protobuf request data: %+v", err), http.StatusBadRequest)
+			return
+		}
+	} else {
+		request, err = decodeJSONOnlineFeaturesRequest(r.Body)
+		if err != nil {
+			//logSpanContext.Error().Err(err).Msg("Error decoding JSON request data")
+			writeJSONError(w, fmt.Errorf("Error decoding JSON request data: %+v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+	fs, err := s.fs.ForProject(request.project)
 	if err != nil {
-		//logSpanContext.Error().Err(err).Msg("Error decoding JSON request data")
-		writeJSONError(w, fmt.Errorf("Error decoding JSON request data: %+v", err), http.StatusInternalServerError)
+		var projectNotFound feast.FeastProjectNotFound
+		if errors.As(err, &projectNotFound) {
+			writeJSONError(w, err, http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Errorf("Error getting feature store for project %s: %+v", request.project, err), http.StatusInternalServerError)
+		}
 		return
 	}
 	var featureService *model.FeatureService
-	if request.FeatureService != nil {
-		featureService, err = s.fs.GetFeatureService(*request.FeatureService)
+	if request.featureService != nil {
+		featureService, err = fs.GetFeatureService(*request.featureService)
 		if err != nil {
 			//logSpanContext.Error().Err(err).Msg("Error getting feature service from registry")
 			writeJSONError(w, fmt.Errorf("Error getting feature service from registry: %+v", err), http.StatusInternalServerError)
 			return
 		}
 	}
-	entitiesProto := make(map[string]*prototypes.RepeatedValue)
-	for key, value := range request.Entities {
-		entitiesProto[key] = value.ToProto()
-	}
-	requestContextProto := make(map[string]*prototypes.RepeatedValue)
-	for key, value := range request.RequestContext {
-		requestContextProto[key] = value.ToProto()
-	}
 
-	featureVectors, err := s.fs.GetOnlineFeatures(
+	featureVectors, err := fs.GetOnlineFeatures(
 		ctx,
-		request.Features,
+		request.features,
 		featureService,
-		entitiesProto,
-		requestContextProto,
-		request.FullFeatureNames)
+		request.entities,
+		request.requestContext,
+		request.fullFeatureNames)
 
 	if err != nil {
 		//logSpanContext.Error().Err(err).Msg("Error getting feature vector")
-		writeJSONError(w, fmt.Errorf("Error getting feature vector: %+v", err), http.StatusInternalServerError)
+		var permissionDenied feast.FeastPermissionDenied
+		if errors.As(err, &permissionDenied) {
+			writeJSONError(w, err, http.StatusForbidden)
+		} else {
+			writeJSONError(w, fmt.Errorf("Error getting feature vector: %+v", err), http.StatusInternalServerError)
+		}
 		return
 	}
 
-	var featureNames []string
-	var results []map[string]interface{}
-	for _, vector := range featureVectors {
-		featureNames = append(featureNames, vector.Name)
-		result := make(map[string]interface{})
-		if status {
-			var statuses []string
-			for _, status := range vector.Statuses {
-				statuses = append(statuses, status.String())
-			}
-			var timestamps []string
-			for _, timestamp := range vector.Timestamps {
-				timestamps = append(timestamps, timestamp.AsTime().Format(time.RFC3339))
-			}
+	switch responseType {
+	case protobufMediaType:
+		err = writeProtobufOnlineFeatures(w, featureVectors)
+	case arrowStreamMediaType:
+		err = writeArrowOnlineFeatures(w, featureVectors, status)
+	default:
+		err = writeJSONOnlineFeatures(w, featureVectors, status)
+	}
+	if err != nil {
+		//logSpanContext.Error().Err(err).Msg("Error encoding response")
+		writeJSONError(w, fmt.Errorf("Error encoding response: %+v", err), http.StatusInternalServerError)
+		return
+	}
 
-			result["statuses"] = statuses
-			result["event_timestamps"] = timestamps
+	// Logged features are written to the offline store of the server's project, so only its feature services are logged
+	if featureService != nil && featureService.LoggingConfig != nil && s.loggingService != nil && fs == s.fs {
+		featureNames := make([]string, len(featureVectors))
+		for i, vector := range featureVectors {
+			featureNames[i] = vector.Name
+		}
+		err = s.logFeatures(ctx, featureService, request.entities, featureVectors[len(request.entities):], featureNames[len(request.entities):], request.requestContext)
+		if err != nil {
+			writeJSONError(w, err, http.StatusInternalServerError)
+			return
 		}
-		// Note, that vector.Values is an Arrow Array, but this type implements JSON Marshaller.
-		// So, it's not necessary to pre-process it in any way.
-		result["values"] = vector.Values
-
-		results = append(results, result)
 	}
 
-	response := map[string]interface{}{
-		"metadata": map[string]interface{}{
-			"feature_names": featureNames,
-		},
-		"results": results,
+	go releaseCGOMemory(featureVectors)
+}
+
+func (s *httpServer) logFeatures(ctx context.Context, featureService *model.FeatureService, entities map[string]*prototypes.RepeatedValue,
+	featureVectors []*onlineserving.FeatureVector, featureNames []string, requestContext map[string]*prototypes.RepeatedValue) (err error) {
+	_, span := tracing.StartSpan(ctx, "LogFeatures", attribute.String("feast.feature_service", featureService.Name))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	logger, err := s.loggingService.GetOrCreateLogger(featureService)
+	if err != nil {
+		//logSpanContext.Error().Err(err).Msgf("Couldn't instantiate logger for feature service %s", featureService.Name)
+		return fmt.Errorf("Couldn't instantiate logger for feature service %s: %+v", featureService.Name, err)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	requestId := GenerateRequestId()
 
-	err = json.NewEncoder(w).Encode(response)
+	// Note: we're converting arrow to proto for feature logging. In the future we should
+	// base feature logging on arrow so that we don't have to do this extra conversion.
+	var featureVectorProtos []*serving.GetOnlineFeaturesResponse_FeatureVector
+	for _, vector := range featureVectors {
+		values, err := types.ArrowValuesToProtoValues(vector.Values)
+		if err != nil {
+			//logSpanContext.Error().Err(err).Msg("Couldn't convert arrow values into protobuf")
+			return fmt.Errorf("Couldn't convert arrow values into protobuf: %+v", err)
+		}
+		featureVectorProtos = append(featureVectorProtos, &serving.GetOnlineFeaturesResponse_FeatureVector{
+			Values:          values,
+			Statuses:        vector.Statuses,
+			EventTimestamps: vector.Timestamps,
+		})
+	}
 
+	err = logger.Log(entities, featureVectorProtos, featureNames, requestContext, requestId)
 	if err != nil {
-		//logSpanContext.Error().Err(err).Msg("Error encoding response")
-		writeJSONError(w, fmt.Errorf("Error encoding response: %+v", err), http.StatusInternalServerError)
+		return fmt.Errorf("LoggerImpl error[%s]: %+v", featureService.Name, err)
+	}
+	return nil
+}
+
+func (s *httpServer) push(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.NotFound(w, r)
 		return
 	}
 
-	if featureService != nil && featureService.LoggingConfig != nil && s.loggingService != nil {
-		logger, err := s.loggingService.GetOrCreateLogger(featureService)
-		if err != nil {
-			//logSpanContext.Error().Err(err).Msgf("Couldn't instantiate logger for feature service %s", featureService.Name)
-			writeJSONError(w, fmt.Errorf("Couldn't instantiate logger for feature service %s: %+v", featureService.Name, err), http.StatusInternalServerError)
-			return
-		}
+	var request pushRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSONError(w, fmt.Errorf("Error decoding JSON request data: %+v", err), http.StatusBadRequest)
+		return
+	}
+	to, err := feast.ParsePushMode(request.To)
+	if err != nil {
+		writeJSONError(w, err, http.StatusBadRequest)
+		return
+	}
+	// Like in the Python feature server, the registry cache is used unless disabled explicitly.
+	allowRegistryCache := request.AllowRegistryCache == nil || *request.AllowRegistryCache
 
-		requestId := GenerateRequestId()
-
-		// Note: we're converting arrow to proto for feature logging. In the future we should
-		// base feature logging on arrow so that we don't have to do this extra conversion.
-		var featureVectorProtos []*serving.GetOnlineFeaturesResponse_FeatureVector
-		for _, vector := range featureVectors[len(request.Entities):] {
-			values, err := types.ArrowValuesToProtoValues(vector.Values)
-			if err != nil {
-				//logSpanContext.Error().Err(err).Msg("Couldn't convert arrow values into protobuf")
-				writeJSONError(w, fmt.Errorf("Couldn't convert arrow values into protobuf: %+v", err), http.StatusInternalServerError)
-				return
-			}
-			featureVectorProtos = append(featureVectorProtos, &serving.GetOnlineFeaturesResponse_FeatureVector{
-				Values:          values,
-				Statuses:        vector.Statuses,
-				EventTimestamps: vector.Timestamps,
-			})
-		}
+	data := make(map[string]*prototypes.RepeatedValue)
+	for key, value := range request.Df {
+		data[key] = value.ToProto()
+	}
 
-		err = logger.Log(entitiesProto, featureVectorProtos, featureNames[len(request.Entities):], requestContextProto, requestId)
-		if err != nil {
-			writeJSONError(w, fmt.Errorf("LoggerImpl error[%s]: %+v", featureService.Name, err), http.StatusInternalServerError)
-			return
+	err = s.fs.Push(r.Context(), request.PushSourceName, data, to, allowRegistryCache)
+	if err != nil {
+		var notFound feast.FeastPushSourceNotFound
+		var notSupported feast.FeastPushModeNotSupported
+		var permissionDenied feast.FeastPermissionDenied
+		var invalidData feast.FeastInvalidFeatureData
+		if errors.As(err, &notFound) {
+			writeJSONError(w, err, http.StatusNotFound)
+		} else if errors.As(err, &invalidData) {
+			writeJSONError(w, err, http.StatusBadRequest)
+		} else if errors.As(err, &permissionDenied) {
+			writeJSONError(w, err, http.StatusForbidden)
+		} else if errors.As(err, &notSupported) {
+			writeJSONError(w, err, http.StatusNotImplemented)
+		} else {
+			writeJSONError(w, fmt.Errorf("Error pushing data: %+v", err), http.StatusInternalServerError)
 		}
+		return
 	}
+	w.WriteHeader(http.StatusOK)
+}
 
-	go releaseCGOMemory(featureVectors)
+func (s *httpServer) listProjects(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(map[string]interface{}{"projects": s.fs.ListProjects()})
+	if err != nil {
+		writeJSONError(w, fmt.Errorf("Error encoding response: %+v", err), http.StatusInternalServerError)
+	}
 }
 
 func releaseCGOMemory(featureVectors []*onlineserving.FeatureVector) {
@@ -340,16 +417,28 @@ func recoverMiddleware(next http.Handler) http.Handler {
 }
 
 func (s *httpServer) Serve(host string, port int) error {
+	return s.ServeTLS(host, port, nil)
+}
+
+// ServeTLS serves HTTPS if TLS is enabled in the options and plain HTTP otherwise.
+func (s *httpServer) ServeTLS(host string, port int, tlsOpts *TlsOptions) error {
 	// DD
 	//if strings.ToLower(os.Getenv("ENABLE_DATADOG_TRACING")) == "true" {
 	//	tracer.Start(tracer.WithRuntimeMetrics())
 	//	defer tracer.Stop()
 	//}
-	mux := http.NewServeMux()
-	mux.Handle("/get-online-features", recoverMiddleware(http.HandlerFunc(s.getOnlineFeatures)))
-	mux.HandleFunc("/health", healthCheckHandler)
-	s.server = &http.Server{Addr: fmt.Sprintf("%s:%d", host, port), Handler: mux, ReadTimeout: 5 * time.Second, WriteTimeout: 10 * time.Second, IdleTimeout: 15 * time.Second}
-	err := s.server.ListenAndServe()
+	s.server = &http.Server{Addr: fmt.Sprintf("%s:%d", host, port), Handler: s.handler(), ReadTimeout: 5 * time.Second, WriteTimeout: 10 * time.Second, IdleTimeout: 15 * time.Second}
+	var err error
+	if tlsOpts.Enabled() {
+		s.server.TLSConfig, err = NewTlsConfig(tlsOpts)
+		if err != nil {
+			return err
+		}
+		// The certificate comes from the TLS config, which reloads it when the files change
+		err = s.server.ListenAndServeTLS("", "")
+	} else {
+		err = s.server.ListenAndServe()
+	}
 	// Don't return the error if it's caused by graceful shutdown using Stop()
 	if err == http.ErrServerClosed {
 		return nil
@@ -358,6 +447,19 @@ func (s *httpServer) Serve(host string, port int) error {
 	return err
 }
 
+// handler routes the requests of the server. Feature requests must be authenticated if authentication is enabled,
+// the health check and metrics are served to everyone.
+func (s *httpServer) handler() http.Handler {
+	authenticator := s.fs.Authenticator()
+	mux := http.NewServeMux()
+	mux.Handle("/get-online-features", metrics.InstrumentHandler("/get-online-features", tracing.HttpMiddleware("/get-online-features", recoverMiddleware(auth.HttpMiddleware(authenticator, writeJSONError, http.HandlerFunc(s.getOnlineFeatures))))))
+	mux.Handle("/push", metrics.InstrumentHandler("/push", tracing.HttpMiddleware("/push", recoverMiddleware(auth.HttpMiddleware(authenticator, writeJSONError, http.HandlerFunc(s.push))))))
+	mux.Handle("/projects", metrics.InstrumentHandler("/projects", recoverMiddleware(auth.HttpMiddleware(authenticator, writeJSONError, http.HandlerFunc(s.listProjects)))))
+	mux.HandleFunc("/health", healthCheckHandler)
+	mux.Handle("/metrics", metrics.Handler())
+	return mux
+}
+
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintf(w, "Healthy")
@@ -1,13 +1,30 @@
 package server
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/apache/arrow/go/v17/arrow"
 	"github.com/apache/arrow/go/v17/arrow/array"
 	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/feast-dev/feast/go/internal/feast"
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/core"
+	"github.com/feast-dev/feast/go/protos/feast/types"
 )
 
 func TestUnmarshalJSON(t *testing.T) {
@@ -76,3 +93,211 @@ func TestMarshalInt64JSON(t *testing.T) {
 	assert.Equal(t, expectedJSON, string(jsonData), "JSON output does not match expected")
 	assert.IsType(t, &array.Int64{}, arrowArray, "arrowArray is not of type *array.Int64")
 }
+
+func TestPushRejectsInvalidRequests(t *testing.T) {
+	s := NewHttpServer(nil, nil)
+
+	rr := httptest.NewRecorder()
+	s.push(rr, httptest.NewRequest("GET", "/push", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	s.push(rr, httptest.NewRequest("POST", "/push", strings.NewReader("not json")))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	body := `{"push_source_name": "driver_stats_push_source", "df": {"driver_id": [1001]}, "to": "somewhere"}`
+	s.push(rr, httptest.NewRequest("POST", "/push", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// newMultiProjectFeatureStore creates a feature store for project feature_repo with a registry that also contains other_repo.
+func newMultiProjectFeatureStore(t *testing.T) *feast.FeatureStore {
+	repoPath := t.TempDir()
+	registryPath := filepath.Join(repoPath, "registry.db")
+	registryStore := registry.NewFileRegistryStore(&registry.RegistryConfig{Path: registryPath}, repoPath)
+	require.Nil(t, registryStore.UpdateRegistryProto(&core.Registry{
+		Projects: []*core.Project{{Spec: &core.ProjectSpec{Name: "feature_repo"}}, {Spec: &core.ProjectSpec{Name: "other_repo"}}},
+	}))
+	fs, err := feast.NewFeatureStore(&registry.RepoConfig{
+		Project:     "feature_repo",
+		RepoPath:    repoPath,
+		Registry:    map[string]interface{}{"path": registryPath},
+		Provider:    "local",
+		OnlineStore: map[string]interface{}{"type": "sqlite", "path": "online_store.db"},
+	}, nil)
+	require.Nil(t, err)
+	t.Cleanup(fs.DestructOnlineStore)
+	return fs
+}
+
+func TestListProjects(t *testing.T) {
+	s := NewHttpServer(newMultiProjectFeatureStore(t), nil)
+
+	rr := httptest.NewRecorder()
+	s.listProjects(rr, httptest.NewRequest("GET", "/projects", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"projects": ["feature_repo", "other_repo"]}`, rr.Body.String())
+}
+
+func TestGetOnlineFeaturesFromUnknownProject(t *testing.T) {
+	s := NewHttpServer(newMultiProjectFeatureStore(t), nil)
+
+	rr := httptest.NewRecorder()
+	body := `{"project": "unknown_repo", "features": ["driver_stats:conv_rate"], "entities": {"driver_id": [1001]}}`
+	s.getOnlineFeatures(rr, httptest.NewRequest("POST", "/get-online-features", strings.NewReader(body)))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	body = `{"project": "other_repo", "feature_service": "unknown_service", "entities": {"driver_id": [1001]}}`
+	s.getOnlineFeatures(rr, httptest.NewRequest("POST", "/get-online-features", strings.NewReader(body)))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "other_repo")
+}
+
+func newFeatureStoreWithPermissions(t *testing.T, key *rsa.PrivateKey) *feast.FeatureStore {
+	repoPath := t.TempDir()
+	jwksPath := filepath.Join(repoPath, "jwks.json")
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "key-1",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(jwksPath, jwks, 0600))
+
+	registryPath := filepath.Join(repoPath, "registry.db")
+	registryStore := registry.NewFileRegistryStore(&registry.RegistryConfig{Path: registryPath}, repoPath)
+	require.Nil(t, registryStore.UpdateRegistryProto(&core.Registry{
+		Entities: []*core.Entity{{Spec: &core.EntitySpecV2{Name: "driver", Project: "feature_repo", JoinKey: "driver_id"}}},
+		FeatureViews: []*core.FeatureView{{Spec: &core.FeatureViewSpec{
+			Name:         "driver_stats",
+			Project:      "feature_repo",
+			Entities:     []string{"driver"},
+			Features:     []*core.FeatureSpecV2{{Name: "conv_rate", ValueType: types.ValueType_FLOAT}},
+			StreamSource: &core.DataSource{Name: "driver_stats_push_source", Type: core.DataSource_PUSH_SOURCE},
+		}}},
+		Permissions: []*core.Permission{{Spec: &core.PermissionSpec{
+			Name:    "read_driver_stats",
+			Project: "feature_repo",
+			Types:   []core.PermissionSpec_Type{core.PermissionSpec_FEATURE_VIEW},
+			Actions: []core.PermissionSpec_AuthzedAction{core.PermissionSpec_READ_ONLINE},
+			Policy:  &core.Policy{PolicyType: &core.Policy_RoleBasedPolicy{RoleBasedPolicy: &core.RoleBasedPolicy{Roles: []string{"reader"}}}},
+		}}, {Spec: &core.PermissionSpec{
+			Name:    "write_driver_stats",
+			Project: "feature_repo",
+			Types:   []core.PermissionSpec_Type{core.PermissionSpec_FEATURE_VIEW},
+			Actions: []core.PermissionSpec_AuthzedAction{core.PermissionSpec_WRITE_ONLINE},
+			Policy:  &core.Policy{PolicyType: &core.Policy_RoleBasedPolicy{RoleBasedPolicy: &core.RoleBasedPolicy{Roles: []string{"writer"}}}},
+		}}},
+	}))
+	fs, err := feast.NewFeatureStore(&registry.RepoConfig{
+		Project:     "feature_repo",
+		RepoPath:    repoPath,
+		Registry:    map[string]interface{}{"path": registryPath},
+		Provider:    "local",
+		OnlineStore: map[string]interface{}{"type": "sqlite", "path": "online_store.db"},
+		Auth:        map[string]interface{}{"type": "oidc", "client_id": "feast", "jwks_file": jwksPath},
+	}, nil)
+	require.Nil(t, err)
+	t.Cleanup(fs.DestructOnlineStore)
+	return fs
+}
+
+func signedToken(t *testing.T, key *rsa.PrivateKey, roles ...string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"preferred_username": "alice",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"resource_access":    map[string]interface{}{"feast": map[string]interface{}{"roles": roles}},
+	})
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(key)
+	require.Nil(t, err)
+	return signed
+}
+
+func TestGetOnlineFeaturesEnforcesPermissions(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	handler := NewHttpServer(newFeatureStoreWithPermissions(t, key), nil).handler()
+	tokenWithRoles := func(roles ...string) string { return signedToken(t, key, roles...) }
+	getOnlineFeatures := func(token string) *httptest.ResponseRecorder {
+		// No entities, so that an authorized request fails validation instead of reading the online store
+		req := httptest.NewRequest("POST", "/get-online-features", strings.NewReader(`{"features": ["driver_stats:conv_rate"], "entities": {}}`))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, getOnlineFeatures("").Code)
+	assert.Equal(t, http.StatusUnauthorized, getOnlineFeatures("invalid").Code)
+
+	rr := getOnlineFeatures(tokenWithRoles("writer"))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "driver_stats")
+
+	rr = getOnlineFeatures(tokenWithRoles("reader"))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "Permission denied")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestPushEnforcesPermissions(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	handler := NewHttpServer(newFeatureStoreWithPermissions(t, key), nil).handler()
+	push := func(token string) *httptest.ResponseRecorder {
+		body := `{"push_source_name": "driver_stats_push_source", "df": {"driver_id": [1001], "conv_rate": [0.5]}}`
+		req := httptest.NewRequest("POST", "/push", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Readers can't write features
+	rr := push(signedToken(t, key, "reader"))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "WRITE_ONLINE")
+
+	rr = push(signedToken(t, key, "writer"))
+	assert.NotEqual(t, http.StatusForbidden, rr.Code)
+}
+
+func TestPushRejectsMalformedData(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	handler := NewHttpServer(newFeatureStoreWithPermissions(t, key), nil).handler()
+	push := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/push", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+signedToken(t, key, "writer"))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := push(`{"push_source_name": "driver_stats_push_source", "df": {"driver_id": [1001, 1002], "conv_rate": [0.5]}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "expected")
+
+	rr = push(`{"push_source_name": "driver_stats_push_source", "df": {"conv_rate": [0.5]}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "missing join key driver_id")
+
+	rr = push(`{"push_source_name": "driver_stats_push_source", "df": {"driver_id": [1001]}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = push(`{"push_source_name": "driver_stats_push_source", "df": {"driver_id": [1001], "conv_rate": ["fast"]}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "conv_rate")
+
+	rr = push(`{"push_source_name": "unknown_push_source", "df": {"driver_id": [1001], "conv_rate": [0.5]}}`)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
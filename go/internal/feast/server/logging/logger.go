@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
 	"github.com/feast-dev/feast/go/protos/feast/types"
 )
@@ -103,6 +104,7 @@ func (l *LoggerImpl) EmitLog(log *Log) error {
 	case l.logCh <- log:
 		return nil
 	case <-time.After(l.config.EmitTimeout):
+		metrics.DroppedLogs.WithLabelValues(l.featureServiceName).Inc()
 		return fmt.Errorf("could not add to log channel with capacity %d. Operation timed out. Current log channel length is %d", cap(l.logCh), len(l.logCh))
 	}
 }
@@ -37,7 +37,9 @@ type LoggingService struct {
 	sink LogSink
 	opts LoggingOptions
 
-	creationLock *sync.Mutex
+	creationLock *sync.RWMutex
+	// Loggers that were reset and are still being stopped
+	stopping sync.WaitGroup
 }
 
 var (
@@ -59,12 +61,15 @@ func NewLoggingService(fs FeatureStore, sink LogSink, opts ...LoggingOptions) (*
 		loggers:      make(map[string]*LoggerImpl),
 		sink:         sink,
 		opts:         opts[0],
-		creationLock: &sync.Mutex{},
+		creationLock: &sync.RWMutex{},
 	}, nil
 }
 
 func (s *LoggingService) GetOrCreateLogger(featureService *model.FeatureService) (Logger, error) {
-	if logger, ok := s.loggers[featureService.Name]; ok {
+	s.creationLock.RLock()
+	logger, ok := s.loggers[featureService.Name]
+	s.creationLock.RUnlock()
+	if ok {
 		return logger, nil
 	}
 
@@ -90,7 +95,7 @@ func (s *LoggingService) GetOrCreateLogger(featureService *model.FeatureService)
 		return nil, err
 	}
 
-	logger, err := NewLogger(schema, featureService.Name, s.sink, config)
+	logger, err = NewLogger(schema, featureService.Name, s.sink, config)
 	if err != nil {
 		return nil, err
 	}
@@ -99,8 +104,45 @@ func (s *LoggingService) GetOrCreateLogger(featureService *model.FeatureService)
 	return logger, nil
 }
 
+// ResetLoggers stops the loggers of the given feature services after flushing their buffered logs. The loggers
+// are recreated on the next request, so that the logged schema follows changes of the feature services. The old
+// loggers are stopped in the background, so that a slow sink doesn't hold up registry refreshes.
+func (s *LoggingService) ResetLoggers(featureServiceNames []string) {
+	s.creationLock.Lock()
+	loggers := make([]*LoggerImpl, 0, len(featureServiceNames))
+	for _, name := range featureServiceNames {
+		if logger, ok := s.loggers[name]; ok {
+			loggers = append(loggers, logger)
+			delete(s.loggers, name)
+		}
+	}
+	s.creationLock.Unlock()
+
+	if len(loggers) == 0 {
+		return
+	}
+	s.stopping.Add(1)
+	go func() {
+		defer s.stopping.Done()
+		stopLoggers(loggers)
+	}()
+}
+
+// Stop stops all loggers after flushing their buffered logs, and waits for the loggers that were reset to stop.
 func (s *LoggingService) Stop() {
+	s.creationLock.Lock()
+	loggers := make([]*LoggerImpl, 0, len(s.loggers))
 	for _, logger := range s.loggers {
+		loggers = append(loggers, logger)
+	}
+	s.creationLock.Unlock()
+
+	stopLoggers(loggers)
+	s.stopping.Wait()
+}
+
+func stopLoggers(loggers []*LoggerImpl) {
+	for _, logger := range loggers {
 		logger.Stop()
 		logger.WaitUntilStopped()
 	}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/feast-dev/feast/go/internal/feast/model"
+)
+
+type featureStoreForTest struct {
+	featureService *model.FeatureService
+	entities       []*model.Entity
+	fvs            []*model.FeatureView
+	odfvs          []*model.OnDemandFeatureView
+}
+
+func (fs *featureStoreForTest) GetFcosMap() (map[string]*model.Entity, map[string]*model.FeatureView, map[string]*model.OnDemandFeatureView, error) {
+	entityMap, fvMap, odFvMap := buildFCOMaps(fs.entities, fs.fvs, fs.odfvs)
+	return entityMap, fvMap, odFvMap, nil
+}
+
+func (fs *featureStoreForTest) GetFeatureService(name string) (*model.FeatureService, error) {
+	return fs.featureService, nil
+}
+
+func TestResetLoggers(t *testing.T) {
+	featureService, entities, fvs, odfvs := InitializeFeatureRepoVariablesForTest()
+	featureService.LoggingConfig = &model.FeatureServiceLoggingConfig{SampleRate: 1.0}
+	service, err := NewLoggingService(&featureStoreForTest{featureService, entities, fvs, odfvs}, &DummySink{})
+	require.Nil(t, err)
+	defer service.Stop()
+
+	logger, err := service.GetOrCreateLogger(featureService)
+	require.Nil(t, err)
+	sameLogger, err := service.GetOrCreateLogger(featureService)
+	require.Nil(t, err)
+	assert.Same(t, logger, sameLogger)
+
+	// Unknown feature services are ignored
+	service.ResetLoggers([]string{"unknown_service"})
+	sameLogger, err = service.GetOrCreateLogger(featureService)
+	require.Nil(t, err)
+	assert.Same(t, logger, sameLogger)
+
+	service.ResetLoggers([]string{featureService.Name})
+	newLogger, err := service.GetOrCreateLogger(featureService)
+	require.Nil(t, err)
+	assert.NotSame(t, logger, newLogger)
+	service.stopping.Wait()
+	assert.True(t, logger.(*LoggerImpl).isStopped)
+}
+
+// blockingSink blocks flushes until unblocked is closed.
+type blockingSink struct {
+	DummySink
+	unblocked chan struct{}
+}
+
+func (s *blockingSink) Flush(featureServiceName string) error {
+	<-s.unblocked
+	return nil
+}
+
+func TestResetLoggersDoesNotWaitForSink(t *testing.T) {
+	featureService, entities, fvs, odfvs := InitializeFeatureRepoVariablesForTest()
+	featureService.LoggingConfig = &model.FeatureServiceLoggingConfig{SampleRate: 1.0}
+	sink := &blockingSink{unblocked: make(chan struct{})}
+	service, err := NewLoggingService(&featureStoreForTest{featureService, entities, fvs, odfvs}, sink)
+	require.Nil(t, err)
+
+	logger, err := service.GetOrCreateLogger(featureService)
+	require.Nil(t, err)
+	service.ResetLoggers([]string{featureService.Name})
+
+	// The reset logger is flushed once the sink unblocks, and Stop waits for it
+	close(sink.unblocked)
+	service.Stop()
+	assert.True(t, logger.(*LoggerImpl).isStopped)
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// How often the certificate files are checked for changes, at most. Certificates are only reloaded when
+// a client connects.
+var certificateCheckInterval = 10 * time.Second
+
+// TlsOptions configures TLS for the HTTP and gRPC servers.
+type TlsOptions struct {
+	// PEM encoded certificate and private key of the server
+	CertFile string
+	KeyFile  string
+	// PEM encoded CA certificates. Clients must present a certificate signed by one of them if set.
+	ClientCaFile string
+}
+
+// Enabled tells whether the servers should serve TLS, which requires a certificate and a private key.
+func (o *TlsOptions) Enabled() bool {
+	return o != nil && (o.CertFile != "" || o.KeyFile != "")
+}
+
+// NewTlsConfig creates a server TLS configuration that picks up changes to the certificate files without a restart.
+func NewTlsConfig(opts *TlsOptions) (*tls.Config, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("TLS requires both a certificate file and a private key file")
+	}
+	reloader := &certificateReloader{opts: *opts}
+	if err := reloader.load(); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.getCertificate,
+	}
+	if opts.ClientCaFile != "" {
+		// The client certificate is verified against the current CA certificates instead of the ones
+		// of a fixed ClientCAs pool, so that the CA file can be rotated too.
+		config.ClientAuth = tls.RequireAnyClientCert
+		config.VerifyPeerCertificate = reloader.verifyClientCertificate
+	}
+	return config, nil
+}
+
+// NewGrpcTlsServerOption returns the gRPC server option for serving TLS with the given options.
+func NewGrpcTlsServerOption(opts *TlsOptions) (grpc.ServerOption, error) {
+	config, err := NewTlsConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.Creds(credentials.NewTLS(config)), nil
+}
+
+type certificateReloader struct {
+	opts TlsOptions
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCas   *x509.CertPool
+	modTimes    []time.Time
+	lastCheck   time.Time
+}
+
+func (r *certificateReloader) files() []string {
+	files := []string{r.opts.CertFile, r.opts.KeyFile}
+	if r.opts.ClientCaFile != "" {
+		files = append(files, r.opts.ClientCaFile)
+	}
+	return files
+}
+
+func (r *certificateReloader) fileModTimes() ([]time.Time, error) {
+	files := r.files()
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func (r *certificateReloader) load() error {
+	modTimes, err := r.fileModTimes()
+	if err != nil {
+		return err
+	}
+	certificate, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the TLS certificate: %w", err)
+	}
+	var clientCas *x509.CertPool
+	if r.opts.ClientCaFile != "" {
+		pem, err := os.ReadFile(r.opts.ClientCaFile)
+		if err != nil {
+			return err
+		}
+		clientCas = x509.NewCertPool()
+		if !clientCas.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no CA certificates found in %s", r.opts.ClientCaFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.certificate = &certificate
+	r.clientCas = clientCas
+	r.modTimes = modTimes
+	r.lastCheck = time.Now()
+	return nil
+}
+
+// reloadIfChanged reloads the certificates if any of the files has changed since they were loaded.
+// The previous certificates stay in use if the new ones can't be loaded, e.g. while the files are being replaced.
+func (r *certificateReloader) reloadIfChanged() {
+	r.mu.Lock()
+	if time.Since(r.lastCheck) < certificateCheckInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastCheck = time.Now()
+	previousModTimes := r.modTimes
+	r.mu.Unlock()
+
+	modTimes, err := r.fileModTimes()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check the TLS certificate files for changes")
+		return
+	}
+	changed := false
+	for i := range modTimes {
+		if !modTimes[i].Equal(previousModTimes[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	if err := r.load(); err != nil {
+		log.Error().Err(err).Msg("Failed to reload the TLS certificates, using the previous ones")
+		return
+	}
+	log.Info().Msg("Reloaded the TLS certificates")
+}
+
+func (r *certificateReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.reloadIfChanged()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.certificate, nil
+}
+
+func (r *certificateReloader) verifyClientCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("client certificate required")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, rawCert := range rawCerts {
+		cert, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	r.mu.RLock()
+	clientCas := r.clientCas
+	r.mu.RUnlock()
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         clientCas,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
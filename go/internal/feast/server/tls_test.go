@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type certificateForTest struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newCertificateForTest(t *testing.T, serial int64, isCa bool, parent *certificateForTest) *certificateForTest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "feast-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if isCa {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	}
+	signer := &certificateForTest{cert: template, key: key}
+	if parent != nil {
+		signer = parent
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.cert, &key.PublicKey, signer.key)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+	return &certificateForTest{cert: cert, key: key}
+}
+
+func (c *certificateForTest) certPem() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})
+}
+
+func (c *certificateForTest) keyPem(t *testing.T) []byte {
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func (c *certificateForTest) tlsCertificate(t *testing.T) tls.Certificate {
+	certificate, err := tls.X509KeyPair(c.certPem(), c.keyPem(t))
+	require.Nil(t, err)
+	return certificate
+}
+
+func writeCertificateFiles(t *testing.T, dir string, c *certificateForTest, modTime time.Time) *TlsOptions {
+	opts := &TlsOptions{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}
+	require.Nil(t, os.WriteFile(opts.CertFile, c.certPem(), 0600))
+	require.Nil(t, os.WriteFile(opts.KeyFile, c.keyPem(t), 0600))
+	require.Nil(t, os.Chtimes(opts.CertFile, modTime, modTime))
+	require.Nil(t, os.Chtimes(opts.KeyFile, modTime, modTime))
+	return opts
+}
+
+func serveHttpsForTest(t *testing.T, opts *TlsOptions) string {
+	config, err := NewTlsConfig(opts)
+	require.Nil(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	server := &http.Server{
+		Handler:   http.HandlerFunc(healthCheckHandler),
+		TLSConfig: config,
+	}
+	go server.ServeTLS(listener, "", "")
+	t.Cleanup(func() { server.Close() })
+	return "https://" + listener.Addr().String()
+}
+
+func httpsClientForTest(ca *certificateForTest, certificates ...tls.Certificate) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certificates},
+		DisableKeepAlives: true,
+	}}
+}
+
+func TestTlsConfigRequiresCertificateAndKey(t *testing.T) {
+	_, err := NewTlsConfig(&TlsOptions{CertFile: "tls.crt"})
+	assert.Error(t, err)
+	assert.False(t, (*TlsOptions)(nil).Enabled())
+	assert.True(t, (&TlsOptions{CertFile: "tls.crt"}).Enabled())
+}
+
+func TestTlsConfigVerifiesClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCertificateForTest(t, 1, true, nil)
+	otherCa := newCertificateForTest(t, 2, true, nil)
+	opts := writeCertificateFiles(t, dir, newCertificateForTest(t, 3, false, ca), time.Now())
+	opts.ClientCaFile = filepath.Join(dir, "ca.crt")
+	require.Nil(t, os.WriteFile(opts.ClientCaFile, ca.certPem(), 0600))
+	url := serveHttpsForTest(t, opts)
+
+	resp, err := httpsClientForTest(ca, newCertificateForTest(t, 4, false, ca).tlsCertificate(t)).Get(url)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = httpsClientForTest(ca).Get(url)
+	assert.Error(t, err)
+
+	_, err = httpsClientForTest(ca, newCertificateForTest(t, 5, false, otherCa).tlsCertificate(t)).Get(url)
+	assert.Error(t, err)
+}
+
+func TestTlsConfigReloadsChangedCertificate(t *testing.T) {
+	previousInterval := certificateCheckInterval
+	certificateCheckInterval = 0
+	defer func() { certificateCheckInterval = previousInterval }()
+
+	dir := t.TempDir()
+	ca := newCertificateForTest(t, 1, true, nil)
+	opts := writeCertificateFiles(t, dir, newCertificateForTest(t, 10, false, ca), time.Now().Add(-time.Minute))
+	url := serveHttpsForTest(t, opts)
+
+	resp, err := httpsClientForTest(ca).Get(url)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int64(10), resp.TLS.PeerCertificates[0].SerialNumber.Int64())
+
+	writeCertificateFiles(t, dir, newCertificateForTest(t, 11, false, ca), time.Now())
+	resp, err = httpsClientForTest(ca).Get(url)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int64(11), resp.TLS.PeerCertificates[0].SerialNumber.Int64())
+}
+
+func TestGrpcServerWithTls(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCertificateForTest(t, 1, true, nil)
+	opts := writeCertificateFiles(t, dir, newCertificateForTest(t, 3, false, ca), time.Now())
+	opts.ClientCaFile = filepath.Join(dir, "ca.crt")
+	require.Nil(t, os.WriteFile(opts.ClientCaFile, ca.certPem(), 0600))
+
+	tlsOption, err := NewGrpcTlsServerOption(opts)
+	require.Nil(t, err)
+	grpcServer := grpc.NewServer(tlsOption)
+	grpc_health_v1.RegisterHealthServer(grpcServer, health.NewServer())
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	creds := credentials.NewTLS(&tls.Config{RootCAs: roots, Certificates: []tls.Certificate{newCertificateForTest(t, 4, false, ca).tlsCertificate(t)}})
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(creds))
+	require.Nil(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.Nil(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+}
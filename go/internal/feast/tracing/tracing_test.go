@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func TestInjectOutgoingContext(t *testing.T) {
+	newInMemoryExporter(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "1")
+	ctx, span := StartSpan(ctx, "parent")
+	defer span.End()
+
+	md, ok := metadata.FromOutgoingContext(InjectOutgoingContext(ctx))
+	require.True(t, ok)
+	assert.Equal(t, []string{"1"}, md.Get("x-request-id"))
+	require.Len(t, md.Get("traceparent"), 1)
+	assert.Contains(t, md.Get("traceparent")[0], span.SpanContext().TraceID().String())
+}
+
+func TestUnaryServerInterceptorContinuesTrace(t *testing.T) {
+	exporter := newInMemoryExporter(t)
+	parentCtx, parent := StartSpan(context.Background(), "client")
+	parent.End()
+	outgoing, _ := metadata.FromOutgoingContext(InjectOutgoingContext(parentCtx))
+	ctx := metadata.NewIncomingContext(context.Background(), outgoing)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/feast.serving.ServingService/GetOnlineFeatures"}
+	_, err := UnaryServerInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, span := StartSpan(ctx, "handler")
+		span.End()
+		return nil, nil
+	})
+	require.Nil(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	handler, server := spans[1], spans[2]
+	assert.Equal(t, "handler", handler.Name)
+	assert.Equal(t, info.FullMethod, server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, parent.SpanContext().TraceID(), server.SpanContext.TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), server.Parent.SpanID())
+	assert.Equal(t, server.SpanContext.SpanID(), handler.Parent.SpanID())
+}
+
+func TestHttpMiddlewareContinuesTrace(t *testing.T) {
+	exporter := newInMemoryExporter(t)
+	handler := HttpMiddleware("/get-online-features", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/get-online-features", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/get-online-features", spans[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+}
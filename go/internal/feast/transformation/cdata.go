@@ -0,0 +1,33 @@
+package transformation
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/apache/arrow/go/v17/arrow/cdata"
+)
+
+// The structs of the C Data Interface are passed to transformation callbacks as uintptrs, so they are allocated
+// in C memory, which the Go runtime doesn't track.
+
+func newCArrowArray() *cdata.CArrowArray {
+	return (*cdata.CArrowArray)(C.calloc(1, C.size_t(unsafe.Sizeof(cdata.CArrowArray{}))))
+}
+
+func newCArrowSchema() *cdata.CArrowSchema {
+	return (*cdata.CArrowSchema)(C.calloc(1, C.size_t(unsafe.Sizeof(cdata.CArrowSchema{}))))
+}
+
+// releaseCArrowArray releases the array, unless it was moved or already released, and frees the struct.
+func releaseCArrowArray(arr *cdata.CArrowArray) {
+	cdata.ReleaseCArrowArray(arr)
+	C.free(unsafe.Pointer(arr))
+}
+
+// releaseCArrowSchema releases the schema, unless it was moved or already released, and frees the struct.
+func releaseCArrowSchema(schema *cdata.CArrowSchema) {
+	cdata.ReleaseCArrowSchema(schema)
+	C.free(unsafe.Pointer(schema))
+}
@@ -0,0 +1,752 @@
+package transformation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/compute"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/arrow/scalar"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+)
+
+// errUnsupportedSubstraitPlan is returned for Substrait plans that can't be executed in process, and that are
+// transformed by the transformation service instead.
+var errUnsupportedSubstraitPlan = errors.New("unsupported substrait plan")
+
+func unsupportedSubstraitPlan(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errUnsupportedSubstraitPlan, fmt.Sprintf(format, args...))
+}
+
+// substraitPlans caches the compiled plans, or the errors compiling them, by the name of the on demand feature
+// view. A view whose plan changed is compiled again and its previous plan evicted, so that the cache holds at most
+// one plan per view.
+var substraitPlans = struct {
+	sync.Mutex
+	byView map[string]*compiledSubstraitPlan
+}{byView: make(map[string]*compiledSubstraitPlan)}
+
+type compiledSubstraitPlan struct {
+	serialized []byte
+	plan       *substraitPlan
+	err        error
+}
+
+// CanTransformInProcess returns whether the on demand feature view has a Substrait plan that can be executed
+// without the transformation service.
+func CanTransformInProcess(featureView *model.OnDemandFeatureView) bool {
+	_, err := getSubstraitPlan(featureView)
+	return err == nil
+}
+
+func getSubstraitPlan(featureView *model.OnDemandFeatureView) (*substraitPlan, error) {
+	if len(featureView.SubstraitPlan) == 0 {
+		return nil, unsupportedSubstraitPlan("%s has no substrait plan", featureView.Base.Name)
+	}
+	substraitPlans.Lock()
+	defer substraitPlans.Unlock()
+	compiled, ok := substraitPlans.byView[featureView.Base.Name]
+	if !ok || !bytes.Equal(compiled.serialized, featureView.SubstraitPlan) {
+		plan, err := parseSubstraitPlan(featureView.SubstraitPlan)
+		compiled = &compiledSubstraitPlan{serialized: featureView.SubstraitPlan, plan: plan, err: err}
+		substraitPlans.byView[featureView.Base.Name] = compiled
+	}
+	return compiled.plan, compiled.err
+}
+
+// transformInProcess executes the Substrait plan of the on demand feature view over the retrieved features and the
+// request context, and returns a vector for each projected feature of the view that the plan outputs.
+func transformInProcess(
+	ctx context.Context,
+	featureView *model.OnDemandFeatureView,
+	retrievedFeatures map[string]arrow.Array,
+	requestContext map[string]arrow.Array,
+	arrowMemory memory.Allocator,
+	numRows int,
+	fullFeatureNames bool,
+) ([]*onlineserving.FeatureVector, error) {
+	plan, err := getSubstraitPlan(featureView)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := plan.execute(compute.WithAllocator(ctx, arrowMemory), substraitInput{retrievedFeatures, requestContext, numRows})
+	if err != nil {
+		return nil, err
+	}
+	defer releaseArrays(columns)
+	if len(plan.names) < len(columns) {
+		return nil, unsupportedSubstraitPlan("the plan names %d of its %d output columns", len(plan.names), len(columns))
+	}
+	outputs := make(map[string]arrow.Array)
+	for index, column := range columns {
+		outputs[plan.names[index]] = column
+	}
+
+	features := featureView.Base.Features
+	if featureView.Base.Projection != nil {
+		features = featureView.Base.Projection.Features
+	}
+	result := make([]*onlineserving.FeatureVector, 0, len(features))
+	for _, feature := range features {
+		values, ok := outputs[feature.Name]
+		if !ok {
+			return nil, fmt.Errorf("the substrait plan of %s doesn't output %s", featureView.Base.Name, feature.Name)
+		}
+		statuses := make([]serving.FieldStatus, numRows)
+		timestamps := make([]*timestamppb.Timestamp, numRows)
+		for idx := 0; idx < numRows; idx++ {
+			statuses[idx] = serving.FieldStatus_PRESENT
+			timestamps[idx] = timestamppb.Now()
+		}
+		name := feature.Name
+		if fullFeatureNames {
+			name = fmt.Sprintf("%s__%s", featureView.Base.Projection.NameToUse(), feature.Name)
+		}
+		values.Retain()
+		result = append(result, &onlineserving.FeatureVector{
+			Name:       name,
+			Values:     values,
+			Statuses:   statuses,
+			Timestamps: timestamps,
+		})
+	}
+	return result, nil
+}
+
+func releaseArrays(arrays []arrow.Array) {
+	for _, arr := range arrays {
+		if arr != nil {
+			arr.Release()
+		}
+	}
+}
+
+// substraitInput holds the columns that the read relations of a plan read by name.
+type substraitInput struct {
+	retrievedFeatures map[string]arrow.Array
+	requestContext    map[string]arrow.Array
+	numRows           int
+}
+
+// column returns the request context value or retrieved feature with the name. Features retrieved with full
+// feature names are also found by their feature name, if only one view has the feature.
+func (in substraitInput) column(name string) (arrow.Array, error) {
+	if column, ok := in.requestContext[name]; ok {
+		return column, nil
+	}
+	if column, ok := in.retrievedFeatures[name]; ok {
+		return column, nil
+	}
+	var found arrow.Array
+	for fullName, column := range in.retrievedFeatures {
+		if strings.HasSuffix(fullName, "__"+name) {
+			if found != nil {
+				return nil, fmt.Errorf("substrait plan input %s is ambiguous", name)
+			}
+			found = column
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("substrait plan input %s is neither a retrieved feature nor in the request context", name)
+	}
+	return found, nil
+}
+
+type substraitPlan struct {
+	root  substraitRel
+	names []string
+}
+
+func (p *substraitPlan) execute(ctx context.Context, input substraitInput) ([]arrow.Array, error) {
+	return p.root.execute(ctx, input)
+}
+
+// substraitRel is a relation that keeps the rows of its input. The caller releases the returned columns.
+type substraitRel interface {
+	execute(ctx context.Context, input substraitInput) ([]arrow.Array, error)
+}
+
+type readRel struct {
+	names []string
+}
+
+func (r *readRel) execute(ctx context.Context, input substraitInput) ([]arrow.Array, error) {
+	columns := make([]arrow.Array, 0, len(r.names))
+	for _, name := range r.names {
+		column, err := input.column(name)
+		if err != nil {
+			releaseArrays(columns)
+			return nil, err
+		}
+		column.Retain()
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// projectRel appends the values of its expressions to the columns of its input, and then emits the columns of
+// the output mapping, if it has one.
+type projectRel struct {
+	input         substraitRel
+	expressions   []substraitExpression
+	outputMapping []int
+}
+
+func (r *projectRel) execute(ctx context.Context, input substraitInput) ([]arrow.Array, error) {
+	columns, err := r.input.execute(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	numInputColumns := len(columns)
+	for _, expression := range r.expressions {
+		// Expressions only refer to the input columns
+		column, err := expression.evaluate(ctx, columns[:numInputColumns], input.numRows)
+		if err != nil {
+			releaseArrays(columns)
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	if r.outputMapping == nil {
+		return columns, nil
+	}
+	defer releaseArrays(columns)
+	emitted := make([]arrow.Array, 0, len(r.outputMapping))
+	for _, index := range r.outputMapping {
+		if index < 0 || index >= len(columns) {
+			releaseArrays(emitted)
+			return nil, unsupportedSubstraitPlan("output mapping %d is out of range", index)
+		}
+		columns[index].Retain()
+		emitted = append(emitted, columns[index])
+	}
+	return emitted, nil
+}
+
+// substraitExpression is a scalar expression over the input columns of a relation. The caller releases the
+// returned array, which has a value for every row.
+type substraitExpression interface {
+	evaluate(ctx context.Context, columns []arrow.Array, numRows int) (arrow.Array, error)
+}
+
+type fieldReference struct {
+	field int
+}
+
+func (e *fieldReference) evaluate(ctx context.Context, columns []arrow.Array, numRows int) (arrow.Array, error) {
+	if e.field < 0 || e.field >= len(columns) {
+		return nil, unsupportedSubstraitPlan("field reference %d is out of range", e.field)
+	}
+	columns[e.field].Retain()
+	return columns[e.field], nil
+}
+
+type literal struct {
+	value scalar.Scalar
+}
+
+func (e *literal) evaluate(ctx context.Context, columns []arrow.Array, numRows int) (arrow.Array, error) {
+	return scalar.MakeArrayFromScalar(e.value, numRows, compute.GetAllocator(ctx))
+}
+
+type cast struct {
+	input  substraitExpression
+	toType arrow.DataType
+}
+
+func (e *cast) evaluate(ctx context.Context, columns []arrow.Array, numRows int) (arrow.Array, error) {
+	input, err := e.input.evaluate(ctx, columns, numRows)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Release()
+	if arrow.TypeEqual(input.DataType(), e.toType) {
+		input.Retain()
+		return input, nil
+	}
+	if e.toType.ID() == arrow.STRING {
+		// Values are formatted directly, since Arrow's cast kernel to strings doesn't release all of its buffers
+		builder := array.NewStringBuilder(compute.GetAllocator(ctx))
+		defer builder.Release()
+		for row := 0; row < input.Len(); row++ {
+			if input.IsNull(row) {
+				builder.AppendNull()
+			} else if binary, ok := input.(*array.Binary); ok {
+				builder.Append(string(binary.Value(row)))
+			} else {
+				builder.Append(input.ValueStr(row))
+			}
+		}
+		return builder.NewArray(), nil
+	}
+	result, err := compute.CastArray(ctx, input, compute.SafeCastOptions(e.toType))
+	if errors.Is(err, arrow.ErrNotImplemented) {
+		return nil, unsupportedSubstraitPlan("cast from %s to %s", input.DataType(), e.toType)
+	}
+	return result, err
+}
+
+type ifClause struct {
+	condition substraitExpression
+	then      substraitExpression
+}
+
+// ifThen takes each row from the first clause whose condition is true, or from else, which is null if the
+// expression has none. Null conditions are false.
+type ifThen struct {
+	ifs       []ifClause
+	elseValue substraitExpression
+}
+
+func (e *ifThen) evaluate(ctx context.Context, columns []arrow.Array, numRows int) (arrow.Array, error) {
+	branches := make([]substraitExpression, 0, len(e.ifs)+1)
+	for _, clause := range e.ifs {
+		branches = append(branches, clause.then)
+	}
+	if e.elseValue != nil {
+		branches = append(branches, e.elseValue)
+	}
+	values, err := evaluateAll(ctx, branches, columns, numRows)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { releaseArrays(values) }()
+	for index := 1; index < len(values); index++ {
+		if !arrow.TypeEqual(values[index].DataType(), values[0].DataType()) {
+			casted, err := compute.CastArray(ctx, values[index], compute.SafeCastOptions(values[0].DataType()))
+			if err != nil {
+				return nil, unsupportedSubstraitPlan("if then branches of types %s and %s", values[0].DataType(), values[index].DataType())
+			}
+			values[index].Release()
+			values[index] = casted
+		}
+	}
+	if e.elseValue == nil {
+		values = append(values, array.MakeArrayOfNull(compute.GetAllocator(ctx), values[0].DataType(), numRows))
+	}
+
+	// Every row takes its value from the concatenated branches
+	takeFrom := make([]int64, numRows)
+	for row := range takeFrom {
+		takeFrom[row] = -1
+	}
+	for branch, clause := range e.ifs {
+		condition, err := clause.condition.evaluate(ctx, columns, numRows)
+		if err != nil {
+			return nil, err
+		}
+		conditions, ok := condition.(*array.Boolean)
+		if !ok {
+			condition.Release()
+			return nil, unsupportedSubstraitPlan("if condition of type %s", condition.DataType())
+		}
+		for row := 0; row < numRows; row++ {
+			if takeFrom[row] == -1 && conditions.IsValid(row) && conditions.Value(row) {
+				takeFrom[row] = int64(branch*numRows + row)
+			}
+		}
+		condition.Release()
+	}
+	for row := range takeFrom {
+		if takeFrom[row] == -1 {
+			takeFrom[row] = int64(len(e.ifs)*numRows + row)
+		}
+	}
+
+	concatenated, err := array.Concatenate(values, compute.GetAllocator(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer concatenated.Release()
+	indicesBuilder := array.NewInt64Builder(compute.GetAllocator(ctx))
+	defer indicesBuilder.Release()
+	indicesBuilder.AppendValues(takeFrom, nil)
+	indices := indicesBuilder.NewArray()
+	defer indices.Release()
+	return compute.TakeArray(ctx, concatenated, indices)
+}
+
+type scalarFunction struct {
+	name      string
+	arguments []substraitExpression
+}
+
+// computeFunctions maps Substrait function names to the Arrow compute functions that implement them
+var computeFunctions = map[string]string{
+	"add":         "add",
+	"subtract":    "subtract",
+	"multiply":    "multiply",
+	"divide":      "divide",
+	"negate":      "negate",
+	"abs":         "abs",
+	"power":       "power",
+	"sqrt":        "sqrt",
+	"ln":          "ln",
+	"log10":       "log10",
+	"log2":        "log2",
+	"sign":        "sign",
+	"floor":       "floor",
+	"ceil":        "ceil",
+	"sin":         "sin",
+	"cos":         "cos",
+	"tan":         "tan",
+	"asin":        "asin",
+	"acos":        "acos",
+	"atan":        "atan",
+	"atan2":       "atan2",
+	"equal":       "equal",
+	"not_equal":   "not_equal",
+	"lt":          "less",
+	"lte":         "less_equal",
+	"gt":          "greater",
+	"gte":         "greater_equal",
+	"and":         "and_kleene",
+	"or":          "or_kleene",
+	"xor":         "xor",
+	"shift_left":  "shift_left",
+	"shift_right": "shift_right",
+}
+
+func (e *scalarFunction) evaluate(ctx context.Context, columns []arrow.Array, numRows int) (arrow.Array, error) {
+	arguments, err := evaluateAll(ctx, e.arguments, columns, numRows)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseArrays(arguments)
+	if len(arguments) == 0 {
+		return nil, unsupportedSubstraitPlan("%s without arguments", e.name)
+	}
+	if implementation, ok := stringFunctions[e.name]; ok {
+		return implementation(compute.GetAllocator(ctx), arguments, numRows)
+	}
+	computeFunction, ok := computeFunctions[e.name]
+	if !ok {
+		return nil, unsupportedSubstraitPlan("function %s", e.name)
+	}
+
+	// Variadic functions, like and, are applied pairwise
+	result := compute.NewDatum(arguments[0])
+	defer func() { result.Release() }()
+	remaining := arguments[1:]
+	for {
+		args := []compute.Datum{result}
+		if len(remaining) > 0 {
+			args = append(args, compute.NewDatumWithoutOwning(remaining[0]))
+			remaining = remaining[1:]
+		}
+		next, err := compute.CallFunction(ctx, computeFunction, nil, args...)
+		if err != nil {
+			if errors.Is(err, arrow.ErrNotImplemented) || errors.Is(err, arrow.ErrType) {
+				return nil, unsupportedSubstraitPlan("%s of %s: %v", e.name, arguments[0].DataType(), err)
+			}
+			return nil, err
+		}
+		result.Release()
+		result = next
+		if len(remaining) == 0 {
+			break
+		}
+	}
+	arrayResult, ok := result.(*compute.ArrayDatum)
+	if !ok {
+		return nil, unsupportedSubstraitPlan("%s returned a %s", e.name, result.Kind())
+	}
+	return arrayResult.MakeArray(), nil
+}
+
+func evaluateAll(ctx context.Context, expressions []substraitExpression, columns []arrow.Array, numRows int) ([]arrow.Array, error) {
+	values := make([]arrow.Array, 0, len(expressions))
+	for _, expression := range expressions {
+		value, err := expression.evaluate(ctx, columns, numRows)
+		if err != nil {
+			releaseArrays(values)
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+type stringFunction func(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error)
+
+// stringFunctions implements the Substrait string functions, and the other functions that Arrow's compute
+// package lacks, row by row.
+var stringFunctions = map[string]stringFunction{
+	"upper":       mapStrings(strings.ToUpper),
+	"lower":       mapStrings(strings.ToLower),
+	"ltrim":       trimFunction(strings.TrimLeft),
+	"rtrim":       trimFunction(strings.TrimRight),
+	"trim":        trimFunction(strings.Trim),
+	"concat":      concat,
+	"substring":   substring,
+	"char_length": charLength,
+	"replace":     replace,
+	"starts_with": matchStrings(strings.HasPrefix),
+	"ends_with":   matchStrings(strings.HasSuffix),
+	"contains":    matchStrings(strings.Contains),
+	"not":         not,
+	"is_null":     isNull(true),
+	"is_not_null": isNull(false),
+	"coalesce":    coalesce,
+}
+
+func stringArguments(arguments []arrow.Array, count int) ([]*array.String, error) {
+	if len(arguments) < count {
+		return nil, unsupportedSubstraitPlan("string function with %d arguments", len(arguments))
+	}
+	strs := make([]*array.String, len(arguments))
+	for index, argument := range arguments {
+		str, ok := argument.(*array.String)
+		if !ok {
+			return nil, unsupportedSubstraitPlan("string function of %s", argument.DataType())
+		}
+		strs[index] = str
+	}
+	return strs, nil
+}
+
+func anyNull(arguments []arrow.Array, row int) bool {
+	for _, argument := range arguments {
+		if argument.IsNull(row) {
+			return true
+		}
+	}
+	return false
+}
+
+func mapStrings(fn func(string) string) stringFunction {
+	return func(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+		strs, err := stringArguments(arguments[:1], 1)
+		if err != nil {
+			return nil, err
+		}
+		builder := array.NewStringBuilder(mem)
+		defer builder.Release()
+		for row := 0; row < numRows; row++ {
+			if strs[0].IsNull(row) {
+				builder.AppendNull()
+			} else {
+				builder.Append(fn(strs[0].Value(row)))
+			}
+		}
+		return builder.NewArray(), nil
+	}
+}
+
+// trimFunction trims spaces, or the characters of the second argument.
+func trimFunction(trim func(string, string) string) stringFunction {
+	return func(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+		strs, err := stringArguments(arguments, 1)
+		if err != nil {
+			return nil, err
+		}
+		builder := array.NewStringBuilder(mem)
+		defer builder.Release()
+		for row := 0; row < numRows; row++ {
+			if anyNull(arguments, row) {
+				builder.AppendNull()
+				continue
+			}
+			characters := " "
+			if len(strs) > 1 {
+				characters = strs[1].Value(row)
+			}
+			builder.Append(trim(strs[0].Value(row), characters))
+		}
+		return builder.NewArray(), nil
+	}
+}
+
+func matchStrings(match func(string, string) bool) stringFunction {
+	return func(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+		strs, err := stringArguments(arguments, 2)
+		if err != nil {
+			return nil, err
+		}
+		builder := array.NewBooleanBuilder(mem)
+		defer builder.Release()
+		for row := 0; row < numRows; row++ {
+			if anyNull(arguments, row) {
+				builder.AppendNull()
+			} else {
+				builder.Append(match(strs[0].Value(row), strs[1].Value(row)))
+			}
+		}
+		return builder.NewArray(), nil
+	}
+}
+
+func concat(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+	strs, err := stringArguments(arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	builder := array.NewStringBuilder(mem)
+	defer builder.Release()
+	for row := 0; row < numRows; row++ {
+		if anyNull(arguments, row) {
+			builder.AppendNull()
+			continue
+		}
+		var value strings.Builder
+		for _, str := range strs {
+			value.WriteString(str.Value(row))
+		}
+		builder.Append(value.String())
+	}
+	return builder.NewArray(), nil
+}
+
+func replace(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+	strs, err := stringArguments(arguments, 3)
+	if err != nil {
+		return nil, err
+	}
+	builder := array.NewStringBuilder(mem)
+	defer builder.Release()
+	for row := 0; row < numRows; row++ {
+		if anyNull(arguments, row) {
+			builder.AppendNull()
+		} else {
+			builder.Append(strings.ReplaceAll(strs[0].Value(row), strs[1].Value(row), strs[2].Value(row)))
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// integerAt returns the value of an integer argument, such as the start of a substring.
+func integerAt(arr arrow.Array, row int) (int64, error) {
+	switch values := arr.(type) {
+	case *array.Int8:
+		return int64(values.Value(row)), nil
+	case *array.Int16:
+		return int64(values.Value(row)), nil
+	case *array.Int32:
+		return int64(values.Value(row)), nil
+	case *array.Int64:
+		return values.Value(row), nil
+	}
+	return 0, unsupportedSubstraitPlan("integer argument of %s", arr.DataType())
+}
+
+// substring returns length characters, or the rest of the string, from the 1-based start. Negative starts count
+// from the end of the string.
+func substring(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+	strs, err := stringArguments(arguments[:1], 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(arguments) < 2 {
+		return nil, unsupportedSubstraitPlan("substring without a start")
+	}
+	builder := array.NewStringBuilder(mem)
+	defer builder.Release()
+	for row := 0; row < numRows; row++ {
+		if anyNull(arguments, row) {
+			builder.AppendNull()
+			continue
+		}
+		characters := []rune(strs[0].Value(row))
+		start, err := integerAt(arguments[1], row)
+		if err != nil {
+			return nil, err
+		}
+		if start > 0 {
+			start--
+		} else if start < 0 {
+			start += int64(len(characters))
+		}
+		end := int64(len(characters))
+		if len(arguments) > 2 {
+			length, err := integerAt(arguments[2], row)
+			if err != nil {
+				return nil, err
+			}
+			if length < 0 {
+				return nil, fmt.Errorf("substring of negative length %d", length)
+			}
+			if start+length < end {
+				end = start + length
+			}
+		}
+		start = int64(math.Max(0, math.Min(float64(start), float64(len(characters)))))
+		if end < start {
+			end = start
+		}
+		builder.Append(string(characters[start:end]))
+	}
+	return builder.NewArray(), nil
+}
+
+func charLength(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+	strs, err := stringArguments(arguments[:1], 1)
+	if err != nil {
+		return nil, err
+	}
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+	for row := 0; row < numRows; row++ {
+		if strs[0].IsNull(row) {
+			builder.AppendNull()
+		} else {
+			builder.Append(int64(len([]rune(strs[0].Value(row)))))
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+func not(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+	values, ok := arguments[0].(*array.Boolean)
+	if !ok {
+		return nil, unsupportedSubstraitPlan("not of %s", arguments[0].DataType())
+	}
+	builder := array.NewBooleanBuilder(mem)
+	defer builder.Release()
+	for row := 0; row < numRows; row++ {
+		if values.IsNull(row) {
+			builder.AppendNull()
+		} else {
+			builder.Append(!values.Value(row))
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+func isNull(null bool) stringFunction {
+	return func(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+		builder := array.NewBooleanBuilder(mem)
+		defer builder.Release()
+		for row := 0; row < numRows; row++ {
+			builder.Append(arguments[0].IsNull(row) == null)
+		}
+		return builder.NewArray(), nil
+	}
+}
+
+// coalesce is evaluated as an if then, with a condition for all but the last argument that it isn't null.
+func coalesce(mem memory.Allocator, arguments []arrow.Array, numRows int) (arrow.Array, error) {
+	ifs := make([]ifClause, 0, len(arguments)-1)
+	references := make([]substraitExpression, len(arguments))
+	for index := range arguments {
+		references[index] = &fieldReference{field: index}
+	}
+	for index := 0; index < len(arguments)-1; index++ {
+		ifs = append(ifs, ifClause{
+			condition: &scalarFunction{name: "is_not_null", arguments: references[index : index+1]},
+			then:      references[index],
+		})
+	}
+	expression := &ifThen{ifs: ifs, elseValue: references[len(arguments)-1]}
+	return expression.evaluate(compute.WithAllocator(context.Background(), mem), arguments, numRows)
+}
@@ -0,0 +1,314 @@
+package transformation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/arrow/scalar"
+	substraitpb "github.com/substrait-io/substrait-go/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// parseSubstraitPlan compiles a serialized substrait.Plan into the relations and expressions that can be executed
+// in process.
+func parseSubstraitPlan(serialized []byte) (*substraitPlan, error) {
+	var plan substraitpb.Plan
+	if err := proto.Unmarshal(serialized, &plan); err != nil {
+		return nil, fmt.Errorf("invalid substrait plan: %w", err)
+	}
+	functions := parseExtensionFunctions(&plan)
+	relations := plan.GetRelations()
+	if len(relations) != 1 {
+		return nil, unsupportedSubstraitPlan("%d relations", len(relations))
+	}
+	root := relations[0].GetRoot()
+	if root == nil {
+		return nil, unsupportedSubstraitPlan("plan without a root relation")
+	}
+	rel, err := parseRel(root.GetInput(), functions)
+	if err != nil {
+		return nil, err
+	}
+	return &substraitPlan{root: rel, names: root.GetNames()}, nil
+}
+
+// parseExtensionFunctions returns the names of the functions by their anchors. The signatures of compound
+// names, like add:i64_i64, are dropped.
+func parseExtensionFunctions(plan *substraitpb.Plan) map[uint32]string {
+	functions := make(map[uint32]string)
+	for _, declaration := range plan.GetExtensions() {
+		if function := declaration.GetExtensionFunction(); function != nil {
+			name, _, _ := strings.Cut(function.GetName(), ":")
+			functions[function.GetFunctionAnchor()] = name
+		}
+	}
+	return functions
+}
+
+func parseRel(rel *substraitpb.Rel, functions map[uint32]string) (substraitRel, error) {
+	switch relType := rel.GetRelType().(type) {
+	case *substraitpb.Rel_Read:
+		return parseReadRel(relType.Read)
+	case *substraitpb.Rel_Project:
+		return parseProjectRel(relType.Project, functions)
+	case nil:
+		return nil, unsupportedSubstraitPlan("missing relation")
+	default:
+		// Filters, joins, aggregations and the other relations change the rows
+		return nil, unsupportedSubstraitPlan("relation %T", relType)
+	}
+}
+
+func parseOutputMapping(common *substraitpb.RelCommon) []int {
+	emit := common.GetEmit()
+	if emit == nil {
+		return nil
+	}
+	outputMapping := make([]int, len(emit.GetOutputMapping()))
+	for i, index := range emit.GetOutputMapping() {
+		outputMapping[i] = int(index)
+	}
+	return outputMapping
+}
+
+func parseReadRel(read *substraitpb.ReadRel) (substraitRel, error) {
+	if read.GetFilter() != nil || read.GetBestEffortFilter() != nil {
+		return nil, unsupportedSubstraitPlan("read relation with a filter")
+	}
+	if read.GetBaseSchema() == nil {
+		return nil, unsupportedSubstraitPlan("read relation without a base schema")
+	}
+	names := read.GetBaseSchema().GetNames()
+	if projection := read.GetProjection(); projection != nil {
+		var err error
+		if names, err = projectNames(projection, names); err != nil {
+			return nil, err
+		}
+	}
+	if outputMapping := parseOutputMapping(read.GetCommon()); outputMapping != nil {
+		emitted := make([]string, 0, len(outputMapping))
+		for _, index := range outputMapping {
+			if index < 0 || index >= len(names) {
+				return nil, unsupportedSubstraitPlan("output mapping %d is out of range", index)
+			}
+			emitted = append(emitted, names[index])
+		}
+		names = emitted
+	}
+	return &readRel{names: names}, nil
+}
+
+// projectNames returns the names of the top level fields that a mask expression selects.
+func projectNames(projection *substraitpb.Expression_MaskExpression, names []string) ([]string, error) {
+	selection := projection.GetSelect()
+	if selection == nil {
+		return names, nil
+	}
+	projected := make([]string, 0, len(selection.GetStructItems()))
+	for _, item := range selection.GetStructItems() {
+		field := int(item.GetField())
+		if item.GetChild() != nil || field < 0 || field >= len(names) {
+			return nil, unsupportedSubstraitPlan("projection of field %d", field)
+		}
+		projected = append(projected, names[field])
+	}
+	return projected, nil
+}
+
+func parseProjectRel(project *substraitpb.ProjectRel, functions map[uint32]string) (substraitRel, error) {
+	inputRel, err := parseRel(project.GetInput(), functions)
+	if err != nil {
+		return nil, err
+	}
+	expressions, err := parseExpressions(project.GetExpressions(), functions)
+	if err != nil {
+		return nil, err
+	}
+	return &projectRel{input: inputRel, expressions: expressions, outputMapping: parseOutputMapping(project.GetCommon())}, nil
+}
+
+func parseExpressions(expressionMessages []*substraitpb.Expression, functions map[uint32]string) ([]substraitExpression, error) {
+	expressions := make([]substraitExpression, 0, len(expressionMessages))
+	for _, message := range expressionMessages {
+		expression, err := parseExpression(message, functions)
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, expression)
+	}
+	return expressions, nil
+}
+
+func parseExpression(expression *substraitpb.Expression, functions map[uint32]string) (substraitExpression, error) {
+	switch rexType := expression.GetRexType().(type) {
+	case *substraitpb.Expression_Literal_:
+		return parseLiteral(rexType.Literal)
+	case *substraitpb.Expression_Selection:
+		return parseFieldReference(rexType.Selection)
+	case *substraitpb.Expression_ScalarFunction_:
+		return parseScalarFunction(rexType.ScalarFunction, functions)
+	case *substraitpb.Expression_IfThen_:
+		return parseIfThen(rexType.IfThen, functions)
+	case *substraitpb.Expression_Cast_:
+		return parseCast(rexType.Cast, functions)
+	case nil:
+		return nil, unsupportedSubstraitPlan("missing expression")
+	default:
+		return nil, unsupportedSubstraitPlan("expression %T", rexType)
+	}
+}
+
+func parseLiteral(literalMessage *substraitpb.Expression_Literal) (substraitExpression, error) {
+	switch value := literalMessage.GetLiteralType().(type) {
+	case *substraitpb.Expression_Literal_Boolean:
+		return &literal{scalar.NewBooleanScalar(value.Boolean)}, nil
+	case *substraitpb.Expression_Literal_I8:
+		return &literal{scalar.NewInt8Scalar(int8(value.I8))}, nil
+	case *substraitpb.Expression_Literal_I16:
+		return &literal{scalar.NewInt16Scalar(int16(value.I16))}, nil
+	case *substraitpb.Expression_Literal_I32:
+		return &literal{scalar.NewInt32Scalar(value.I32)}, nil
+	case *substraitpb.Expression_Literal_I64:
+		return &literal{scalar.NewInt64Scalar(value.I64)}, nil
+	case *substraitpb.Expression_Literal_Fp32:
+		return &literal{scalar.NewFloat32Scalar(value.Fp32)}, nil
+	case *substraitpb.Expression_Literal_Fp64:
+		return &literal{scalar.NewFloat64Scalar(value.Fp64)}, nil
+	case *substraitpb.Expression_Literal_String_:
+		return &literal{scalar.NewStringScalar(value.String_)}, nil
+	case *substraitpb.Expression_Literal_Binary:
+		return &literal{scalar.NewBinaryScalar(memory.NewBufferBytes(value.Binary), arrow.BinaryTypes.Binary)}, nil
+	case *substraitpb.Expression_Literal_VarChar_:
+		return &literal{scalar.NewStringScalar(value.VarChar.GetValue())}, nil
+	case *substraitpb.Expression_Literal_Null:
+		dataType, err := parseType(value.Null)
+		if err != nil {
+			return nil, err
+		}
+		return &literal{scalar.MakeNullScalar(dataType)}, nil
+	default:
+		return nil, unsupportedSubstraitPlan("literal %T", value)
+	}
+}
+
+func parseFieldReference(reference *substraitpb.Expression_FieldReference) (substraitExpression, error) {
+	if reference.GetExpression() != nil || reference.GetOuterReference() != nil {
+		return nil, unsupportedSubstraitPlan("field reference of an expression or an outer query")
+	}
+	segment := reference.GetDirectReference()
+	if segment == nil {
+		return nil, unsupportedSubstraitPlan("field reference without a direct reference")
+	}
+	structField := segment.GetStructField()
+	if structField == nil || structField.GetChild() != nil {
+		return nil, unsupportedSubstraitPlan("reference to a nested field")
+	}
+	return &fieldReference{field: int(structField.GetField())}, nil
+}
+
+func parseScalarFunction(function *substraitpb.Expression_ScalarFunction, functions map[uint32]string) (substraitExpression, error) {
+	anchor := function.GetFunctionReference()
+	name, ok := functions[anchor]
+	if !ok {
+		return nil, fmt.Errorf("invalid substrait plan: no function with the anchor %d", anchor)
+	}
+	var arguments []substraitExpression
+	if len(function.GetArguments()) > 0 {
+		arguments = make([]substraitExpression, 0, len(function.GetArguments()))
+		for _, argumentMessage := range function.GetArguments() {
+			// Enum and type arguments, like the rounding of divide, aren't supported
+			value := argumentMessage.GetValue()
+			if value == nil {
+				return nil, unsupportedSubstraitPlan("%s with an enum or type argument", name)
+			}
+			argument, err := parseExpression(value, functions)
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, argument)
+		}
+	} else {
+		// Plans of older Substrait versions pass the arguments as expressions
+		var err error
+		if arguments, err = parseExpressions(function.GetArgs(), functions); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := stringFunctions[name]; !ok {
+		if _, ok := computeFunctions[name]; !ok {
+			return nil, unsupportedSubstraitPlan("function %s", name)
+		}
+	}
+	return &scalarFunction{name: name, arguments: arguments}, nil
+}
+
+func parseIfThen(ifThenMessage *substraitpb.Expression_IfThen, functions map[uint32]string) (substraitExpression, error) {
+	clauses := ifThenMessage.GetIfs()
+	if len(clauses) == 0 {
+		return nil, unsupportedSubstraitPlan("if then without clauses")
+	}
+	expression := &ifThen{ifs: make([]ifClause, 0, len(clauses))}
+	for _, clause := range clauses {
+		condition, err := parseExpression(clause.GetIf(), functions)
+		if err != nil {
+			return nil, err
+		}
+		then, err := parseExpression(clause.GetThen(), functions)
+		if err != nil {
+			return nil, err
+		}
+		expression.ifs = append(expression.ifs, ifClause{condition: condition, then: then})
+	}
+	if ifThenMessage.GetElse() != nil {
+		var err error
+		if expression.elseValue, err = parseExpression(ifThenMessage.GetElse(), functions); err != nil {
+			return nil, err
+		}
+	}
+	return expression, nil
+}
+
+func parseCast(castMessage *substraitpb.Expression_Cast, functions map[uint32]string) (substraitExpression, error) {
+	toType, err := parseType(castMessage.GetType())
+	if err != nil {
+		return nil, err
+	}
+	input, err := parseExpression(castMessage.GetInput(), functions)
+	if err != nil {
+		return nil, err
+	}
+	return &cast{input: input, toType: toType}, nil
+}
+
+func parseType(typeMessage *substraitpb.Type) (arrow.DataType, error) {
+	switch kind := typeMessage.GetKind().(type) {
+	case *substraitpb.Type_Bool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case *substraitpb.Type_I8_:
+		return arrow.PrimitiveTypes.Int8, nil
+	case *substraitpb.Type_I16_:
+		return arrow.PrimitiveTypes.Int16, nil
+	case *substraitpb.Type_I32_:
+		return arrow.PrimitiveTypes.Int32, nil
+	case *substraitpb.Type_I64_:
+		return arrow.PrimitiveTypes.Int64, nil
+	case *substraitpb.Type_Fp32:
+		return arrow.PrimitiveTypes.Float32, nil
+	case *substraitpb.Type_Fp64:
+		return arrow.PrimitiveTypes.Float64, nil
+	case *substraitpb.Type_String_, *substraitpb.Type_Varchar:
+		return arrow.BinaryTypes.String, nil
+	case *substraitpb.Type_Binary_:
+		return arrow.BinaryTypes.Binary, nil
+	case *substraitpb.Type_Timestamp_:
+		return arrow.FixedWidthTypes.Timestamp_us, nil
+	case *substraitpb.Type_Date_:
+		return arrow.FixedWidthTypes.Date32, nil
+	case nil:
+		return nil, unsupportedSubstraitPlan("missing type")
+	default:
+		return nil, unsupportedSubstraitPlan("type %T", kind)
+	}
+}
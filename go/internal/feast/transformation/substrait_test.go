@@ -0,0 +1,284 @@
+package transformation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	substraitpb "github.com/substrait-io/substrait-go/proto"
+	"github.com/substrait-io/substrait-go/proto/extensions"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
+	"github.com/feast-dev/feast/go/protos/feast/core"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+// The helpers below build Substrait plans with the generated substrait protos.
+
+func fieldRef(field int32) *substraitpb.Expression {
+	return &substraitpb.Expression{RexType: &substraitpb.Expression_Selection{Selection: &substraitpb.Expression_FieldReference{
+		ReferenceType: &substraitpb.Expression_FieldReference_DirectReference{DirectReference: &substraitpb.Expression_ReferenceSegment{
+			ReferenceType: &substraitpb.Expression_ReferenceSegment_StructField_{StructField: &substraitpb.Expression_ReferenceSegment_StructField{Field: field}},
+		}},
+		RootType: &substraitpb.Expression_FieldReference_RootReference_{RootReference: &substraitpb.Expression_FieldReference_RootReference{}},
+	}}}
+}
+
+func literalExpression(literal *substraitpb.Expression_Literal) *substraitpb.Expression {
+	return &substraitpb.Expression{RexType: &substraitpb.Expression_Literal_{Literal: literal}}
+}
+
+func int64Literal(value int64) *substraitpb.Expression {
+	return literalExpression(&substraitpb.Expression_Literal{LiteralType: &substraitpb.Expression_Literal_I64{I64: value}})
+}
+
+func float64Literal(value float64) *substraitpb.Expression {
+	return literalExpression(&substraitpb.Expression_Literal{LiteralType: &substraitpb.Expression_Literal_Fp64{Fp64: value}})
+}
+
+func stringLiteral(value string) *substraitpb.Expression {
+	return literalExpression(&substraitpb.Expression_Literal{LiteralType: &substraitpb.Expression_Literal_String_{String_: value}})
+}
+
+func call(anchor uint32, arguments ...*substraitpb.Expression) *substraitpb.Expression {
+	function := &substraitpb.Expression_ScalarFunction{FunctionReference: anchor}
+	for _, argument := range arguments {
+		function.Arguments = append(function.Arguments, &substraitpb.FunctionArgument{ArgType: &substraitpb.FunctionArgument_Value{Value: argument}})
+	}
+	return &substraitpb.Expression{RexType: &substraitpb.Expression_ScalarFunction_{ScalarFunction: function}}
+}
+
+var (
+	fp64Type   = &substraitpb.Type{Kind: &substraitpb.Type_Fp64{Fp64: &substraitpb.Type_FP64{}}}
+	stringType = &substraitpb.Type{Kind: &substraitpb.Type_String_{String_: &substraitpb.Type_String{}}}
+)
+
+func castTo(toType *substraitpb.Type, input *substraitpb.Expression) *substraitpb.Expression {
+	return &substraitpb.Expression{RexType: &substraitpb.Expression_Cast_{Cast: &substraitpb.Expression_Cast{Type: toType, Input: input}}}
+}
+
+func ifElse(condition *substraitpb.Expression, then *substraitpb.Expression, elseValue *substraitpb.Expression) *substraitpb.Expression {
+	return &substraitpb.Expression{RexType: &substraitpb.Expression_IfThen_{IfThen: &substraitpb.Expression_IfThen{
+		Ifs:  []*substraitpb.Expression_IfThen_IfClause{{If: condition, Then: then}},
+		Else: elseValue,
+	}}}
+}
+
+func readRelation(names ...string) *substraitpb.Rel {
+	return &substraitpb.Rel{RelType: &substraitpb.Rel_Read{Read: &substraitpb.ReadRel{
+		BaseSchema: &substraitpb.NamedStruct{Names: names},
+		ReadType:   &substraitpb.ReadRel_NamedTable_{NamedTable: &substraitpb.ReadRel_NamedTable{Names: []string{"input"}}},
+	}}}
+}
+
+func projectRelation(input *substraitpb.Rel, outputMapping []int32, expressions ...*substraitpb.Expression) *substraitpb.Rel {
+	return &substraitpb.Rel{RelType: &substraitpb.Rel_Project{Project: &substraitpb.ProjectRel{
+		Common:      &substraitpb.RelCommon{EmitKind: &substraitpb.RelCommon_Emit_{Emit: &substraitpb.RelCommon_Emit{OutputMapping: outputMapping}}},
+		Input:       input,
+		Expressions: expressions,
+	}}}
+}
+
+func filterRelation(input *substraitpb.Rel, condition *substraitpb.Expression) *substraitpb.Rel {
+	return &substraitpb.Rel{RelType: &substraitpb.Rel_Filter{Filter: &substraitpb.FilterRel{Input: input, Condition: condition}}}
+}
+
+func substraitPlanBytes(functions []string, rel *substraitpb.Rel, names ...string) []byte {
+	plan := &substraitpb.Plan{Relations: []*substraitpb.PlanRel{{RelType: &substraitpb.PlanRel_Root{Root: &substraitpb.RelRoot{Input: rel, Names: names}}}}}
+	for index, name := range functions {
+		plan.Extensions = append(plan.Extensions, &extensions.SimpleExtensionDeclaration{
+			MappingType: &extensions.SimpleExtensionDeclaration_ExtensionFunction_{ExtensionFunction: &extensions.SimpleExtensionDeclaration_ExtensionFunction{
+				ExtensionUriReference: 1,
+				FunctionAnchor:        uint32(index),
+				Name:                  name,
+			}},
+		})
+	}
+	serialized, err := proto.Marshal(plan)
+	if err != nil {
+		panic(err)
+	}
+	return serialized
+}
+
+const (
+	fnAdd = iota
+	fnUpper
+	fnConcat
+	fnGt
+	fnSubstring
+	fnCharLength
+	fnRegexpMatch
+)
+
+var testFunctions = []string{"add:fp64_fp64", "upper:str", "concat:str", "gt:fp64_fp64", "substring:str_i64_i64", "char_length:str", "regexp_match_substring:str_str"}
+
+func newSubstraitFeatureView(plan []byte, features ...string) *model.OnDemandFeatureView {
+	specs := make([]*core.FeatureSpecV2, 0, len(features))
+	for _, feature := range features {
+		specs = append(specs, &core.FeatureSpecV2{Name: feature})
+	}
+	return model.NewOnDemandFeatureViewFromProto(&core.OnDemandFeatureView{Spec: &core.OnDemandFeatureViewSpec{
+		Name:     "driver_features",
+		Features: specs,
+		FeatureTransformation: &core.FeatureTransformationV2{Transformation: &core.FeatureTransformationV2_SubstraitTransformation{
+			SubstraitTransformation: &core.SubstraitTransformationV2{SubstraitPlan: plan},
+		}},
+	}})
+}
+
+// driverFeaturesPlan reads conv_rate, val_to_add and name and outputs
+//
+//	conv_rate_plus_val = conv_rate + cast(val_to_add as fp64)
+//	label = concat(upper(name), "-", cast(val_to_add as string))
+//	level = if conv_rate > 0.5 then "high" else "low"
+//	name_length = char_length(substring(name, 2, 3))
+func driverFeaturesPlan() []byte {
+	read := readRelation("conv_rate", "val_to_add", "name")
+	project := projectRelation(read, []int32{3, 4, 5, 6},
+		call(fnAdd, fieldRef(0), castTo(fp64Type, fieldRef(1))),
+		call(fnConcat, call(fnUpper, fieldRef(2)), stringLiteral("-"), castTo(stringType, fieldRef(1))),
+		ifElse(call(fnGt, fieldRef(0), float64Literal(0.5)), stringLiteral("high"), stringLiteral("low")),
+		call(fnCharLength, call(fnSubstring, fieldRef(2), int64Literal(2), int64Literal(3))),
+	)
+	return substraitPlanBytes(testFunctions, project, "conv_rate_plus_val", "label", "level", "name_length")
+}
+
+func newDriverFeatures(t *testing.T, mem memory.Allocator) ([]*onlineserving.FeatureVector, map[string]*prototypes.RepeatedValue) {
+	convRates := array.NewFloat64Builder(mem)
+	defer convRates.Release()
+	convRates.AppendValues([]float64{0.25, 0.75, 0}, []bool{true, true, false})
+	features := []*onlineserving.FeatureVector{{Name: "driver_stats__conv_rate", Values: convRates.NewArray()}}
+	t.Cleanup(func() { features[0].Values.Release() })
+
+	requestData := map[string]*prototypes.RepeatedValue{
+		"val_to_add": {Val: []*prototypes.Value{
+			{Val: &prototypes.Value_Int64Val{Int64Val: 1}},
+			{Val: &prototypes.Value_Int64Val{Int64Val: 2}},
+			{Val: &prototypes.Value_Int64Val{Int64Val: 3}},
+		}},
+		"name": {Val: []*prototypes.Value{
+			{Val: &prototypes.Value_StringVal{StringVal: "alice"}},
+			{Val: &prototypes.Value_StringVal{StringVal: "bo"}},
+			{Val: &prototypes.Value_StringVal{StringVal: "carol"}},
+		}},
+	}
+	return features, requestData
+}
+
+func TestSubstraitTransformationInProcess(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	features, requestData := newDriverFeatures(t, mem)
+	odfv := newSubstraitFeatureView(driverFeaturesPlan(), "conv_rate_plus_val", "label", "level", "name_length")
+	assert.True(t, CanTransformInProcess(odfv))
+
+	vectors, err := AugmentResponseWithOnDemandTransforms(context.Background(), []*model.OnDemandFeatureView{odfv},
+		requestData, map[string]*prototypes.RepeatedValue{}, features, nil, nil, mem, 3, false)
+	require.Nil(t, err)
+	defer releaseVectors(vectors)
+	require.Len(t, vectors, 4)
+
+	assert.Equal(t, "conv_rate_plus_val", vectors[0].Name)
+	sums := vectors[0].Values.(*array.Float64)
+	assert.Equal(t, []float64{1.25, 2.75}, sums.Float64Values()[:2])
+	assert.True(t, sums.IsNull(2))
+	assert.Equal(t, serving.FieldStatus_PRESENT, vectors[0].Statuses[0])
+
+	labels := vectors[1].Values.(*array.String)
+	assert.Equal(t, []string{"ALICE-1", "BO-2", "CAROL-3"}, []string{labels.Value(0), labels.Value(1), labels.Value(2)})
+
+	// The null conv rate isn't greater than 0.5
+	levels := vectors[2].Values.(*array.String)
+	assert.Equal(t, []string{"low", "high", "low"}, []string{levels.Value(0), levels.Value(1), levels.Value(2)})
+
+	assert.Equal(t, []int64{3, 1, 3}, vectors[3].Values.(*array.Int64).Int64Values())
+
+	// Projected features are named with full feature names
+	projected, err := odfv.ProjectWithFeatures([]string{"level"})
+	require.Nil(t, err)
+	projectedVectors, err := AugmentResponseWithOnDemandTransforms(context.Background(), []*model.OnDemandFeatureView{projected},
+		requestData, map[string]*prototypes.RepeatedValue{}, features, nil, nil, mem, 3, true)
+	require.Nil(t, err)
+	defer releaseVectors(projectedVectors)
+	require.Len(t, projectedVectors, 1)
+	assert.Equal(t, "driver_features__level", projectedVectors[0].Name)
+}
+
+func releaseVectors(vectors []*onlineserving.FeatureVector) {
+	for _, vector := range vectors {
+		vector.Values.Release()
+	}
+}
+
+func TestUnsupportedSubstraitPlans(t *testing.T) {
+	read := readRelation("conv_rate", "name")
+	for name, plan := range map[string][]byte{
+		"function": substraitPlanBytes(testFunctions,
+			projectRelation(read, []int32{2}, call(fnRegexpMatch, fieldRef(1), stringLiteral("a.*"))), "matches"),
+		"filter": substraitPlanBytes(testFunctions,
+			filterRelation(read, call(fnGt, fieldRef(0), float64Literal(0.5))), "conv_rate"),
+		"string function of a number": substraitPlanBytes(testFunctions,
+			projectRelation(read, []int32{2}, call(fnUpper, fieldRef(0))), "matches"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			t.Cleanup(func() { mem.AssertSize(t, 0) })
+			features, requestData := newDriverFeatures(t, mem)
+			odfv := newSubstraitFeatureView(plan, "matches")
+
+			_, err := AugmentResponseWithOnDemandTransforms(context.Background(), []*model.OnDemandFeatureView{odfv},
+				requestData, map[string]*prototypes.RepeatedValue{}, features, nil, nil, mem, 3, false)
+			assert.ErrorIs(t, err, errUnsupportedSubstraitPlan)
+		})
+	}
+	assert.False(t, CanTransformInProcess(newSubstraitFeatureView(nil, "matches")))
+}
+
+func TestUnsupportedSubstraitPlansFallBackToTransformationService(t *testing.T) {
+	service, fake := newFakeTransformationService(t)
+	features, requestData := newDriverFeatures(t, memory.DefaultAllocator)
+	plan := substraitPlanBytes(testFunctions,
+		projectRelation(readRelation("name"), []int32{1}, call(fnRegexpMatch, fieldRef(0), stringLiteral("a.*"))), "matches")
+
+	vectors, err := AugmentResponseWithOnDemandTransforms(context.Background(),
+		[]*model.OnDemandFeatureView{newSubstraitFeatureView(plan, "matches")},
+		requestData, map[string]*prototypes.RepeatedValue{}, features, nil, service, memory.DefaultAllocator, 3, false)
+	require.Nil(t, err)
+	require.Len(t, vectors, 1)
+	assert.Equal(t, "matches", vectors[0].Name)
+	assert.Equal(t, 3, vectors[0].Values.Len())
+	assert.Equal(t, 1, fake.callCount())
+
+	// Supported plans are executed in process even when the service is configured
+	_, err = AugmentResponseWithOnDemandTransforms(context.Background(),
+		[]*model.OnDemandFeatureView{newSubstraitFeatureView(driverFeaturesPlan(), "label")},
+		requestData, map[string]*prototypes.RepeatedValue{}, features, nil, service, memory.DefaultAllocator, 3, false)
+	require.Nil(t, err)
+	assert.Equal(t, 1, fake.callCount())
+}
+
+func TestSubstraitPlansAreCachedByFeatureView(t *testing.T) {
+	unsupported := substraitPlanBytes(testFunctions,
+		projectRelation(readRelation("name"), []int32{1}, call(fnRegexpMatch, fieldRef(0), stringLiteral("a.*"))), "label")
+	odfv := newSubstraitFeatureView(driverFeaturesPlan(), "label")
+	odfv.Base.Name = "cached_driver_features"
+	assert.True(t, CanTransformInProcess(odfv))
+	cached := substraitPlans.byView[odfv.Base.Name]
+
+	// A view that is read from the registry again reuses the plan compiled for it
+	odfv.SubstraitPlan = driverFeaturesPlan()
+	assert.True(t, CanTransformInProcess(odfv))
+	assert.Same(t, cached, substraitPlans.byView[odfv.Base.Name])
+
+	// A changed plan replaces the previous one
+	odfv.SubstraitPlan = unsupported
+	assert.False(t, CanTransformInProcess(odfv))
+	assert.Equal(t, unsupported, substraitPlans.byView[odfv.Base.Name].serialized)
+}
@@ -2,17 +2,23 @@ package transformation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
+	"unsafe"
 
 	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/cdata"
 	"github.com/apache/arrow/go/v17/arrow/memory"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	//"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 
 	"github.com/feast-dev/feast/go/internal/feast/model"
 	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
 	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
 	"github.com/feast-dev/feast/go/types"
 )
@@ -27,6 +33,9 @@ Python function is expected to return number of rows added to the output record
 */
 type TransformationCallback func(ODFVName string, inputArrPtr, inputSchemaPtr, outArrPtr, outSchemaPtr uintptr, fullFeatureNames bool) int
 
+// AugmentResponseWithOnDemandTransforms computes the features of the on demand feature views from the retrieved
+// features and the request context, which are converted to Arrow once for all the views. The views that are
+// transformed by the transformation service share one serialized input and are transformed concurrently.
 func AugmentResponseWithOnDemandTransforms(
 	ctx context.Context,
 	onDemandFeatureViews []*model.OnDemandFeatureView,
@@ -40,58 +49,214 @@ func AugmentResponseWithOnDemandTransforms(
 	fullFeatureNames bool,
 
 ) ([]*onlineserving.FeatureVector, error) {
-	//span, _ := tracer.StartSpanFromContext(ctx, "transformation.AugmentResponseWithOnDemandTransforms")
-	//defer span.Finish()
-
-	result := make([]*onlineserving.FeatureVector, 0)
 	var err error
+	requestContextArrow := make(map[string]arrow.Array)
+	defer ReleaseArrowContext(requestContextArrow)
+	for name, values := range requestData {
+		requestContextArrow[name], err = types.ProtoValuesToArrowArray(values.Val, arrowMemory, numRows)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for name, values := range entityRows {
+		requestContextArrow[name], err = types.ProtoValuesToArrowArray(values.Val, arrowMemory, numRows)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	for _, odfv := range onDemandFeatureViews {
-		requestContextArrow := make(map[string]arrow.Array)
-		for name, values := range requestData {
-			requestContextArrow[name], err = types.ProtoValuesToArrowArray(values.Val, arrowMemory, numRows)
-			if err != nil {
-				ReleaseArrowContext(requestContextArrow)
-				return nil, err
+	retrievedFeatures := make(map[string]arrow.Array)
+	for _, vector := range features {
+		retrievedFeatures[vector.Name] = vector.Values
+	}
+
+	onDemandFeatures := make([][]*onlineserving.FeatureVector, len(onDemandFeatureViews))
+	release := func() {
+		for _, vectors := range onDemandFeatures {
+			for _, vector := range vectors {
+				vector.Values.Release()
 			}
 		}
+	}
+	serviceViews := make([]*model.OnDemandFeatureView, 0)
+	serviceViewIndexes := make([]int, 0)
+	for index, odfv := range onDemandFeatureViews {
+		vectors, transformed, err := transformWithoutService(
+			ctx,
+			odfv,
+			retrievedFeatures,
+			requestContextArrow,
+			transformationCallback,
+			transformationService != nil,
+			arrowMemory,
+			numRows,
+			fullFeatureNames,
+		)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		if transformed {
+			onDemandFeatures[index] = vectors
+		} else {
+			serviceViews = append(serviceViews, odfv)
+			serviceViewIndexes = append(serviceViewIndexes, index)
+		}
+	}
 
-		for name, values := range entityRows {
-			requestContextArrow[name], err = types.ProtoValuesToArrowArray(values.Val, arrowMemory, numRows)
-			if err != nil {
-				ReleaseArrowContext(requestContextArrow)
-				return nil, err
-			}
+	if len(serviceViews) > 0 {
+		serviceFeatures, err := transformationService.GetTransformations(
+			ctx,
+			serviceViews,
+			retrievedFeatures,
+			requestContextArrow,
+			numRows,
+			fullFeatureNames,
+		)
+		if err != nil {
+			release()
+			return nil, err
 		}
+		for index, vectors := range serviceFeatures {
+			onDemandFeatures[serviceViewIndexes[index]] = vectors
+		}
+	}
+
+	result := make([]*onlineserving.FeatureVector, 0)
+	for _, vectors := range onDemandFeatures {
+		result = append(result, vectors...)
+	}
+	return result, nil
+}
 
-		retrievedFeatures := make(map[string]arrow.Array)
-		for _, vector := range features {
-			retrievedFeatures[vector.Name] = vector.Values
+// transformWithoutService executes the Substrait plan of the view in process, and falls back to the
+// transformation callback for views without a plan or with plans that can't be executed in process. It returns
+// false for views that are left to the transformation service.
+func transformWithoutService(
+	ctx context.Context,
+	odfv *model.OnDemandFeatureView,
+	retrievedFeatures map[string]arrow.Array,
+	requestContext map[string]arrow.Array,
+	transformationCallback TransformationCallback,
+	hasTransformationService bool,
+	arrowMemory memory.Allocator,
+	numRows int,
+	fullFeatureNames bool,
+) ([]*onlineserving.FeatureVector, bool, error) {
+	onDemandFeatures, err := transformInProcess(ctx, odfv, retrievedFeatures, requestContext, arrowMemory, numRows, fullFeatureNames)
+	if err == nil || !errors.Is(err, errUnsupportedSubstraitPlan) {
+		return onDemandFeatures, true, err
+	}
+	if transformationCallback == nil && !hasTransformationService {
+		if len(odfv.SubstraitPlan) == 0 {
+			return nil, true, nil
 		}
+		return nil, true, err
+	}
+	if len(odfv.SubstraitPlan) > 0 {
+		log.Debug().Err(err).Str("on_demand_feature_view", odfv.Base.Name).Msg("Transforming without the substrait plan")
+	}
+	if transformationCallback != nil {
+		onDemandFeatures, err = CallTransformations(odfv, retrievedFeatures, requestContext, transformationCallback, numRows, fullFeatureNames)
+		return onDemandFeatures, true, err
+	}
+	return nil, false, nil
+}
+
+// CallTransformations passes the retrieved features and the request context to the callback as a record batch
+// through the Arrow C Data Interface, and returns the projected features of the record batch that it outputs.
+func CallTransformations(
+	featureView *model.OnDemandFeatureView,
+	retrievedFeatures map[string]arrow.Array,
+	requestContext map[string]arrow.Array,
+	callback TransformationCallback,
+	numRows int,
+	fullFeatureNames bool,
+) ([]*onlineserving.FeatureVector, error) {
+	inputArr := newCArrowArray()
+	inputSchema := newCArrowSchema()
+	outArr := newCArrowArray()
+	outSchema := newCArrowSchema()
+	// The callback moves the input and the import moves the output, but either may have failed before that.
+	// Releasing moved structs is a no-op.
+	defer releaseCArrowArray(inputArr)
+	defer releaseCArrowSchema(inputSchema)
+	defer releaseCArrowArray(outArr)
+	defer releaseCArrowSchema(outSchema)
+
+	inputFields := make([]arrow.Field, 0, len(retrievedFeatures)+len(requestContext))
+	inputColumns := make([]arrow.Array, 0, len(retrievedFeatures)+len(requestContext))
+	for name, arr := range retrievedFeatures {
+		inputFields = append(inputFields, arrow.Field{Name: name, Type: arr.DataType(), Nullable: true})
+		inputColumns = append(inputColumns, arr)
+	}
+	for name, arr := range requestContext {
+		inputFields = append(inputFields, arrow.Field{Name: name, Type: arr.DataType(), Nullable: true})
+		inputColumns = append(inputColumns, arr)
+	}
+	inputRecord := array.NewRecord(arrow.NewSchema(inputFields, nil), inputColumns, int64(numRows))
+	// The exported record batch retains the columns until the callback releases it
+	cdata.ExportArrowRecordBatch(inputRecord, inputArr, inputSchema)
+	inputRecord.Release()
+
+	ret := callback(
+		featureView.Base.Name,
+		uintptr(unsafe.Pointer(inputArr)),
+		uintptr(unsafe.Pointer(inputSchema)),
+		uintptr(unsafe.Pointer(outArr)),
+		uintptr(unsafe.Pointer(outSchema)),
+		fullFeatureNames,
+	)
+	if ret != numRows {
+		return nil, fmt.Errorf("transformation callback of %s returned %d rows instead of %d", featureView.Base.Name, ret, numRows)
+	}
 
-		var onDemandFeatures []*onlineserving.FeatureVector
-		if transformationService != nil {
-			onDemandFeatures, err = transformationService.GetTransformation(
-				ctx,
-				odfv,
-				retrievedFeatures,
-				requestContextArrow,
-				numRows,
-				fullFeatureNames,
-			)
-			if err != nil {
-				ReleaseArrowContext(requestContextArrow)
-				return nil, err
+	outRecord, err := cdata.ImportCRecordBatch(outArr, outSchema)
+	if err != nil {
+		return nil, err
+	}
+	defer outRecord.Release()
+
+	result := make([]*onlineserving.FeatureVector, 0)
+	for idx, field := range outRecord.Schema().Fields() {
+		featureName := field.Name
+		if fullFeatureNames {
+			if _, name, ok := strings.Cut(field.Name, "__"); ok {
+				featureName = name
 			}
 		}
-		result = append(result, onDemandFeatures...)
+		if featureView.Base.Projection != nil && !hasFeature(featureView.Base.Projection.Features, featureName) {
+			continue
+		}
 
-		ReleaseArrowContext(requestContextArrow)
-	}
+		statuses := make([]serving.FieldStatus, numRows)
+		timestamps := make([]*timestamppb.Timestamp, numRows)
+		for idx := 0; idx < numRows; idx++ {
+			statuses[idx] = serving.FieldStatus_PRESENT
+			timestamps[idx] = timestamppb.Now()
+		}
 
+		values := outRecord.Column(idx)
+		values.Retain()
+		result = append(result, &onlineserving.FeatureVector{
+			Name:       field.Name,
+			Values:     values,
+			Statuses:   statuses,
+			Timestamps: timestamps,
+		})
+	}
 	return result, nil
 }
 
+func hasFeature(features []*model.Field, name string) bool {
+	for _, feature := range features {
+		if feature.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func ReleaseArrowContext(requestContextArrow map[string]arrow.Array) {
 	// Release memory used by requestContextArrow
 	for _, arrowArray := range requestContextArrow {
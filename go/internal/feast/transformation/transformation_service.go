@@ -3,8 +3,14 @@ package transformation
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"io"
 
@@ -17,31 +23,218 @@ import (
 	"github.com/apache/arrow/go/v17/arrow/memory"
 	"github.com/feast-dev/feast/go/internal/feast/model"
 	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
+	"github.com/feast-dev/feast/go/internal/feast/tracing"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
-type GrpcTransformationService struct {
-	project string
+// Deadline of the health check of the transformation service on startup
+const transformationServiceHealthTimeout = 5 * time.Second
+
+// TransformationServiceUnavailable is returned when the circuit breakers of all the transformation service
+// endpoints are open, or when the calls to them keep failing with UNAVAILABLE.
+type TransformationServiceUnavailable struct {
+	Endpoints []string
+	Err       error
+}
+
+func (e TransformationServiceUnavailable) GRPCStatus() *status.Status {
+	if e.Err != nil {
+		return status.New(codes.Unavailable, fmt.Sprintf("Transformation service %s is unavailable: %s", strings.Join(e.Endpoints, ", "), status.Convert(e.Err).Message()))
+	}
+	return status.New(codes.Unavailable, fmt.Sprintf("Transformation service %s is unavailable, its circuit breaker is open", strings.Join(e.Endpoints, ", ")))
+}
+
+func (e TransformationServiceUnavailable) Error() string {
+	return e.GRPCStatus().Err().Error()
+}
+
+func (e TransformationServiceUnavailable) Unwrap() error {
+	return e.Err
+}
+
+type transformationServiceEndpoint struct {
+	target  string
 	conn    *grpc.ClientConn
-	client  *serving.TransformationServiceClient
+	client  serving.TransformationServiceClient
+	breaker *circuitBreaker
 }
 
-func NewGrpcTransformationService(config *registry.RepoConfig, endpoint string) (*GrpcTransformationService, error) {
-	opts := make([]grpc.DialOption, 0)
-	opts = append(opts, grpc.WithDefaultCallOptions(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+// GrpcTransformationService calls the transformation service, balancing the calls between its endpoints in a
+// round robin. Calls failing with UNAVAILABLE are retried on the next endpoint with an exponential backoff, and
+// endpoints that keep failing are skipped by their circuit breakers until they recover.
+type GrpcTransformationService struct {
+	project   string
+	config    registry.TransformationServiceConfig
+	endpoints []*transformationServiceEndpoint
+	next      atomic.Uint64
+	// Number of on demand feature views of a request that are transformed concurrently
+	concurrency int
+}
 
-	conn, err := grpc.Dial(endpoint, opts...)
+// NewGrpcTransformationService creates the client of the transformation service. With HealthCheck set, the
+// endpoints are checked before it's returned, which takes up to 5 seconds when none of them respond.
+func NewGrpcTransformationService(project string, concurrency int, serviceConfig *registry.TransformationServiceConfig) (*GrpcTransformationService, error) {
+	creds, err := getTransformationServiceCredentials(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newGrpcTransformationService(project, serviceConfig, concurrency, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		return nil, err
 	}
-	client := serving.NewTransformationServiceClient(conn)
-	return &GrpcTransformationService{config.Project, conn, &client}, nil
+	if serviceConfig.HealthCheck {
+		ctx, cancel := context.WithTimeout(context.Background(), transformationServiceHealthTimeout)
+		defer cancel()
+		if err := s.CheckHealth(ctx); err != nil {
+			if serviceConfig.RequireHealthy {
+				s.Close()
+				return nil, err
+			}
+			log.Warn().Err(err).Msg("Transformation service failed the health check, on demand feature views are transformed once it recovers")
+		}
+	}
+	return s, nil
+}
+
+func newGrpcTransformationService(project string, serviceConfig *registry.TransformationServiceConfig, concurrency int, opts ...grpc.DialOption) (*GrpcTransformationService, error) {
+	s := &GrpcTransformationService{project: project, config: *serviceConfig, concurrency: concurrency}
+	for _, target := range serviceConfig.Endpoints {
+		conn, err := grpc.NewClient(target, opts...)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("failed to connect to transformation service %s: %w", target, err)
+		}
+		s.endpoints = append(s.endpoints, &transformationServiceEndpoint{
+			target:  target,
+			conn:    conn,
+			client:  serving.NewTransformationServiceClient(conn),
+			breaker: newCircuitBreaker(serviceConfig.CircuitBreakerFailureThreshold, serviceConfig.CircuitBreakerResetTimeout),
+		})
+	}
+	return s, nil
+}
+
+func getTransformationServiceCredentials(config *registry.TransformationServiceConfig) (credentials.TransportCredentials, error) {
+	if config.Cert != "" {
+		pem, err := os.ReadFile(config.Cert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transformation service certificate: %w", err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", config.Cert)
+		}
+		return credentials.NewTLS(&tls.Config{RootCAs: certPool}), nil
+	}
+	if config.IsTls {
+		return credentials.NewTLS(&tls.Config{}), nil
+	}
+	return insecure.NewCredentials(), nil
 }
 
 func (s *GrpcTransformationService) Close() error {
-	return s.conn.Close()
+	var errs []error
+	for _, endpoint := range s.endpoints {
+		if err := endpoint.conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckHealth calls GetTransformationServiceInfo on every endpoint, and returns an error if none of them
+// responds.
+func (s *GrpcTransformationService) CheckHealth(ctx context.Context) error {
+	var errs []error
+	for _, endpoint := range s.endpoints {
+		info, err := endpoint.client.GetTransformationServiceInfo(ctx, &serving.GetTransformationServiceInfoRequest{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.target, err))
+			continue
+		}
+		log.Info().Str("endpoint", endpoint.target).Str("version", info.GetVersion()).Msg("Connected to transformation service")
+	}
+	if len(errs) == len(s.endpoints) {
+		return TransformationServiceUnavailable{Endpoints: s.config.Endpoints, Err: errors.Join(errs...)}
+	}
+	for _, err := range errs {
+		log.Warn().Err(err).Msg("Transformation service endpoint failed the health check")
+	}
+	return nil
+}
+
+// nextEndpoint returns the next endpoint in the round robin whose circuit breaker lets a call through, or nil if
+// all of them are open.
+func (s *GrpcTransformationService) nextEndpoint() *transformationServiceEndpoint {
+	start := s.next.Add(1) - 1
+	for offset := 0; offset < len(s.endpoints); offset++ {
+		endpoint := s.endpoints[(start+uint64(offset))%uint64(len(s.endpoints))]
+		if endpoint.breaker.allow() {
+			return endpoint
+		}
+	}
+	return nil
+}
+
+// isEndpointFailure returns whether the error of a call counts against the circuit breaker of the endpoint, as
+// opposed to errors of the transformation itself.
+func isEndpointFailure(ctx context.Context, err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return true
+	case codes.DeadlineExceeded:
+		// Only the per-call deadline, not the one of the request
+		return ctx.Err() == nil
+	}
+	return false
+}
+
+func (s *GrpcTransformationService) transformFeatures(ctx context.Context, req *serving.TransformFeaturesRequest) (*serving.TransformFeaturesResponse, error) {
+	backoff := s.config.InitialBackoff
+	var lastErr error
+	for attempt := 0; attempt <= s.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > s.config.MaxBackoff {
+				backoff = s.config.MaxBackoff
+			}
+		}
+		endpoint := s.nextEndpoint()
+		if endpoint == nil {
+			return nil, TransformationServiceUnavailable{Endpoints: s.config.Endpoints, Err: lastErr}
+		}
+
+		callCtx, cancel := ctx, context.CancelFunc(func() {})
+		if s.config.Timeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, s.config.Timeout)
+		}
+		res, err := endpoint.client.TransformFeatures(callCtx, req)
+		cancel()
+		failed := err != nil && isEndpointFailure(ctx, err)
+		endpoint.breaker.record(!failed)
+		if err == nil {
+			return res, nil
+		}
+		if status.Code(err) != codes.Unavailable {
+			return nil, err
+		}
+		log.Debug().Err(err).Str("endpoint", endpoint.target).Int("attempt", attempt).Msg("Transformation service is unavailable")
+		lastErr = err
+	}
+	return nil, TransformationServiceUnavailable{Endpoints: s.config.Endpoints, Err: lastErr}
 }
 
 func (s *GrpcTransformationService) GetTransformation(
@@ -52,8 +245,61 @@ func (s *GrpcTransformationService) GetTransformation(
 	numRows int,
 	fullFeatureNames bool,
 ) ([]*onlineserving.FeatureVector, error) {
-	var err error
+	vectors, err := s.GetTransformations(ctx, []*model.OnDemandFeatureView{featureView}, retrievedFeatures, requestContext, numRows, fullFeatureNames)
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+// GetTransformations transforms the on demand feature views concurrently, with an input that is serialized once
+// for all of them, and returns the features of each view.
+func (s *GrpcTransformationService) GetTransformations(
+	ctx context.Context,
+	featureViews []*model.OnDemandFeatureView,
+	retrievedFeatures map[string]arrow.Array,
+	requestContext map[string]arrow.Array,
+	numRows int,
+	fullFeatureNames bool,
+) ([][]*onlineserving.FeatureVector, error) {
+	input, err := serializeTransformationInput(retrievedFeatures, requestContext, numRows)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]*onlineserving.FeatureVector, len(featureViews))
+	g, ctx := errgroup.WithContext(ctx)
+	concurrency := s.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	g.SetLimit(concurrency)
+	for index, featureView := range featureViews {
+		g.Go(func() error {
+			ctx, span := tracing.StartSpan(ctx, "GetTransformation", attribute.String("feast.on_demand_feature_view", featureView.Base.Name))
+			vectors, err := s.getTransformation(ctx, featureView, input, numRows, fullFeatureNames)
+			tracing.EndSpan(span, err)
+			results[index] = vectors
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		for _, vectors := range results {
+			for _, vector := range vectors {
+				vector.Values.Release()
+			}
+		}
+		return nil, err
+	}
+	return results, nil
+}
 
+// serializeTransformationInput writes the retrieved features and the request context as an Arrow IPC file.
+func serializeTransformationInput(
+	retrievedFeatures map[string]arrow.Array,
+	requestContext map[string]arrow.Array,
+	numRows int,
+) (*serving.ValueType, error) {
 	inputFields := make([]arrow.Field, 0)
 	inputColumns := make([]arrow.Array, 0)
 	for name, arr := range retrievedFeatures {
@@ -85,16 +331,24 @@ func (s *GrpcTransformationService) GetTransformation(
 		return nil, err
 	}
 
-	arrowInput := serving.ValueType_ArrowValue{ArrowValue: recordValueWriter.buf}
-	transformationInput := serving.ValueType{Value: &arrowInput}
+	return &serving.ValueType{Value: &serving.ValueType_ArrowValue{ArrowValue: recordValueWriter.buf}}, nil
+}
 
+func (s *GrpcTransformationService) getTransformation(
+	ctx context.Context,
+	featureView *model.OnDemandFeatureView,
+	transformationInput *serving.ValueType,
+	numRows int,
+	fullFeatureNames bool,
+) ([]*onlineserving.FeatureVector, error) {
 	req := serving.TransformFeaturesRequest{
 		OnDemandFeatureViewName: featureView.Base.Name,
 		Project:                 s.project,
-		TransformationInput:     &transformationInput,
+		TransformationInput:     transformationInput,
 	}
 
-	res, err := (*s.client).TransformFeatures(ctx, &req)
+	// The transformation server continues the trace of the request
+	res, err := s.transformFeatures(tracing.InjectOutgoingContext(ctx), &req)
 	if err != nil {
 		return nil, err
 	}
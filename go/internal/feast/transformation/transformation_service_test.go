@@ -0,0 +1,278 @@
+package transformation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/ipc"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/internal/feast/registry"
+	"github.com/feast-dev/feast/go/protos/feast/core"
+	"github.com/feast-dev/feast/go/protos/feast/serving"
+	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+type fakeTransformationServer struct {
+	serving.UnimplementedTransformationServiceServer
+	mu    sync.Mutex
+	calls int
+	// inFlight is called with the number of concurrent requests when a request starts
+	inFlight func(int)
+	active   int
+	// failures is the number of calls that fail with UNAVAILABLE before the calls succeed
+	failures  int
+	unhealthy bool
+}
+
+func (s *fakeTransformationServer) GetTransformationServiceInfo(context.Context, *serving.GetTransformationServiceInfoRequest) (*serving.GetTransformationServiceInfoResponse, error) {
+	if s.unhealthy {
+		return nil, status.Error(codes.Unavailable, "restarting")
+	}
+	return &serving.GetTransformationServiceInfoResponse{Version: "test"}, nil
+}
+
+func (s *fakeTransformationServer) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// TransformFeatures returns a matches feature that is true for every row.
+func (s *fakeTransformationServer) TransformFeatures(ctx context.Context, request *serving.TransformFeaturesRequest) (*serving.TransformFeaturesResponse, error) {
+	s.mu.Lock()
+	s.calls++
+	if s.failures > 0 {
+		s.failures--
+		s.mu.Unlock()
+		return nil, status.Error(codes.Unavailable, "restarting")
+	}
+	s.active++
+	active := s.active
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.active--
+		s.mu.Unlock()
+	}()
+	if s.inFlight != nil {
+		s.inFlight(active)
+	}
+	input, err := ipc.NewFileReader(bytes.NewReader(request.TransformationInput.GetArrowValue()))
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	record, err := input.Read()
+	if err != nil {
+		return nil, err
+	}
+	builder := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer builder.Release()
+	for row := 0; row < int(record.NumRows()); row++ {
+		builder.Append(true)
+	}
+	matches := builder.NewArray()
+	defer matches.Release()
+	schema := arrow.NewSchema([]arrow.Field{{Name: request.OnDemandFeatureViewName + "__matches", Type: arrow.FixedWidthTypes.Boolean}}, nil)
+	output := array.NewRecord(schema, []arrow.Array{matches}, record.NumRows())
+	defer output.Release()
+
+	writer := new(ByteSliceWriter)
+	fileWriter, err := ipc.NewFileWriter(writer, ipc.WithSchema(schema))
+	if err != nil {
+		return nil, err
+	}
+	if err := fileWriter.Write(output); err != nil {
+		return nil, err
+	}
+	if err := fileWriter.Close(); err != nil {
+		return nil, err
+	}
+	return &serving.TransformFeaturesResponse{TransformationOutput: &serving.ValueType{Value: &serving.ValueType_ArrowValue{ArrowValue: writer.buf}}}, nil
+}
+
+func newTestTransformationServiceConfig() *registry.TransformationServiceConfig {
+	return &registry.TransformationServiceConfig{
+		Timeout:                        5 * time.Second,
+		MaxRetries:                     3,
+		InitialBackoff:                 time.Millisecond,
+		MaxBackoff:                     time.Millisecond,
+		CircuitBreakerFailureThreshold: 5,
+		CircuitBreakerResetTimeout:     time.Minute,
+	}
+}
+
+// newFakeTransformationServices returns a client of count fake transformation servers, which are the endpoints
+// of the client in order.
+func newFakeTransformationServices(t *testing.T, serviceConfig *registry.TransformationServiceConfig, count int) (*GrpcTransformationService, []*fakeTransformationServer) {
+	listeners := make(map[string]*bufconn.Listener)
+	fakes := make([]*fakeTransformationServer, count)
+	serviceConfig.Endpoints = nil
+	for idx := range fakes {
+		listener := bufconn.Listen(1024 * 1024)
+		server := grpc.NewServer()
+		fakes[idx] = &fakeTransformationServer{}
+		serving.RegisterTransformationServiceServer(server, fakes[idx])
+		go func() { _ = server.Serve(listener) }()
+		t.Cleanup(server.Stop)
+		name := fmt.Sprintf("endpoint-%d", idx)
+		listeners[name] = listener
+		serviceConfig.Endpoints = append(serviceConfig.Endpoints, "passthrough:///"+name)
+	}
+
+	service, err := newGrpcTransformationService("feature_repo", serviceConfig, 8,
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) { return listeners[addr].DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = service.Close() })
+	return service, fakes
+}
+
+func newFakeTransformationService(t *testing.T) (*GrpcTransformationService, *fakeTransformationServer) {
+	service, fakes := newFakeTransformationServices(t, newTestTransformationServiceConfig(), 1)
+	return service, fakes[0]
+}
+
+func newServiceFeatureView(name string) *model.OnDemandFeatureView {
+	return model.NewOnDemandFeatureViewFromProto(&core.OnDemandFeatureView{Spec: &core.OnDemandFeatureViewSpec{
+		Name:     name,
+		Features: []*core.FeatureSpecV2{{Name: "matches", ValueType: prototypes.ValueType_BOOL}},
+	}})
+}
+
+func TestTransformationServiceTransformsViewsConcurrently(t *testing.T) {
+	service, fake := newFakeTransformationService(t)
+	// Every request waits until the requests of all three views are in flight
+	allInFlight := make(chan struct{})
+	var once sync.Once
+	fake.inFlight = func(active int) {
+		if active == 3 {
+			once.Do(func() { close(allInFlight) })
+		}
+		select {
+		case <-allInFlight:
+		case <-time.After(5 * time.Second):
+		}
+	}
+	features, requestData := newDriverFeatures(t, memory.DefaultAllocator)
+	views := []*model.OnDemandFeatureView{
+		newServiceFeatureView("first"),
+		newSubstraitFeatureView(driverFeaturesPlan(), "label"),
+		newServiceFeatureView("second"),
+		newServiceFeatureView("third"),
+	}
+
+	start := time.Now()
+	vectors, err := AugmentResponseWithOnDemandTransforms(context.Background(), views,
+		requestData, map[string]*prototypes.RepeatedValue{}, features, nil, service, memory.DefaultAllocator, 3, false)
+	require.Nil(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, 3, fake.callCount())
+	// The features are returned in the order of the views
+	require.Len(t, vectors, 4)
+	assert.Equal(t, []string{"matches", "label", "matches", "matches"},
+		[]string{vectors[0].Name, vectors[1].Name, vectors[2].Name, vectors[3].Name})
+
+	// With a concurrency of 1 the views are transformed one at a time
+	service.concurrency = 1
+	fake.inFlight = func(active int) { assert.Equal(t, 1, active) }
+	_, err = AugmentResponseWithOnDemandTransforms(context.Background(), views,
+		requestData, map[string]*prototypes.RepeatedValue{}, features, nil, service, memory.DefaultAllocator, 3, false)
+	require.Nil(t, err)
+	assert.Equal(t, 6, fake.callCount())
+}
+
+func transformWithService(t *testing.T, service *GrpcTransformationService, names ...string) error {
+	features, requestData := newDriverFeatures(t, memory.DefaultAllocator)
+	views := make([]*model.OnDemandFeatureView, len(names))
+	for idx, name := range names {
+		views[idx] = newServiceFeatureView(name)
+	}
+	vectors, err := AugmentResponseWithOnDemandTransforms(context.Background(), views,
+		requestData, map[string]*prototypes.RepeatedValue{}, features, nil, service, memory.DefaultAllocator, 3, false)
+	releaseVectors(vectors)
+	return err
+}
+
+func TestTransformationServiceRetriesUnavailableCalls(t *testing.T) {
+	service, fake := newFakeTransformationService(t)
+	fake.failures = 2
+	require.Nil(t, transformWithService(t, service, "first"))
+	assert.Equal(t, 3, fake.callCount())
+
+	// Calls are retried at most max_retries times
+	fake.failures = 4
+	err := transformWithService(t, service, "first")
+	var unavailable TransformationServiceUnavailable
+	require.True(t, errors.As(err, &unavailable))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.ErrorContains(t, err, "restarting")
+	assert.Equal(t, 7, fake.callCount())
+}
+
+func TestTransformationServiceCircuitBreaker(t *testing.T) {
+	serviceConfig := newTestTransformationServiceConfig()
+	serviceConfig.MaxRetries = 0
+	serviceConfig.CircuitBreakerFailureThreshold = 2
+	service, fakes := newFakeTransformationServices(t, serviceConfig, 1)
+	fake := fakes[0]
+	now := time.Now()
+	service.endpoints[0].breaker.now = func() time.Time { return now }
+
+	fake.failures = 2
+	assert.Error(t, transformWithService(t, service, "first"))
+	assert.Error(t, transformWithService(t, service, "first"))
+	assert.True(t, service.endpoints[0].breaker.isOpen())
+
+	// The open circuit fails calls without calling the server
+	err := transformWithService(t, service, "first")
+	assert.EqualError(t, err, "rpc error: code = Unavailable desc = Transformation service passthrough:///endpoint-0 is unavailable, its circuit breaker is open")
+	assert.Equal(t, 2, fake.callCount())
+
+	// Once the reset timeout has passed a call is let through, which closes the circuit
+	now = now.Add(serviceConfig.CircuitBreakerResetTimeout)
+	require.Nil(t, transformWithService(t, service, "first"))
+	assert.False(t, service.endpoints[0].breaker.isOpen())
+	assert.Equal(t, 3, fake.callCount())
+}
+
+func TestTransformationServiceBalancesCallsBetweenEndpoints(t *testing.T) {
+	service, fakes := newFakeTransformationServices(t, newTestTransformationServiceConfig(), 2)
+	require.Nil(t, transformWithService(t, service, "first", "second", "third", "fourth"))
+	assert.Equal(t, 2, fakes[0].callCount())
+	assert.Equal(t, 2, fakes[1].callCount())
+
+	// Calls to an unavailable endpoint are retried on the other one
+	service.concurrency = 1
+	fakes[0].failures = 10
+	require.Nil(t, transformWithService(t, service, "first", "second", "third", "fourth"))
+	assert.Equal(t, 6, fakes[1].callCount())
+}
+
+func TestTransformationServiceHealthCheck(t *testing.T) {
+	service, fakes := newFakeTransformationServices(t, newTestTransformationServiceConfig(), 2)
+	fakes[0].unhealthy = true
+	assert.Nil(t, service.CheckHealth(context.Background()))
+
+	fakes[1].unhealthy = true
+	err := service.CheckHealth(context.Background())
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.ErrorContains(t, err, "passthrough:///endpoint-1")
+}
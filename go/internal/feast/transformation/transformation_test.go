@@ -0,0 +1,129 @@
+package transformation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/cdata"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/protos/feast/core"
+	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+// newConvRateCallback returns a callback that outputs conv_rate_plus_val_to_add and conv_rate_times_two, like a
+// Python UDF would, and records the on demand feature views that it was called with.
+func newConvRateCallback(t *testing.T, mem memory.Allocator, calls *[]string) TransformationCallback {
+	return func(ODFVName string, inputArrPtr, inputSchemaPtr, outArrPtr, outSchemaPtr uintptr, fullFeatureNames bool) int {
+		*calls = append(*calls, ODFVName)
+		input, err := cdata.ImportCRecordBatch(cdata.ArrayFromPtr(inputArrPtr), cdata.SchemaFromPtr(inputSchemaPtr))
+		require.Nil(t, err)
+		defer input.Release()
+
+		columns := make(map[string]arrow.Array)
+		for idx, field := range input.Schema().Fields() {
+			columns[field.Name] = input.Column(idx)
+		}
+		convRates := columns["driver_stats__conv_rate"].(*array.Float64)
+		valsToAdd := columns["val_to_add"].(*array.Int64)
+		plusVal := array.NewFloat64Builder(mem)
+		defer plusVal.Release()
+		timesTwo := array.NewFloat64Builder(mem)
+		defer timesTwo.Release()
+		for row := 0; row < int(input.NumRows()); row++ {
+			plusVal.Append(convRates.Value(row) + float64(valsToAdd.Value(row)))
+			timesTwo.Append(convRates.Value(row) * 2)
+		}
+
+		prefix := ""
+		if fullFeatureNames {
+			prefix = ODFVName + "__"
+		}
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: prefix + "conv_rate_plus_val_to_add", Type: arrow.PrimitiveTypes.Float64},
+			{Name: prefix + "conv_rate_times_two", Type: arrow.PrimitiveTypes.Float64},
+		}, nil)
+		plusValues := plusVal.NewArray()
+		defer plusValues.Release()
+		timesTwoValues := timesTwo.NewArray()
+		defer timesTwoValues.Release()
+		output := array.NewRecord(schema, []arrow.Array{plusValues, timesTwoValues}, input.NumRows())
+		defer output.Release()
+		cdata.ExportArrowRecordBatch(output, cdata.ArrayFromPtr(outArrPtr), cdata.SchemaFromPtr(outSchemaPtr))
+		return int(output.NumRows())
+	}
+}
+
+func newCallbackFeatureView() *model.OnDemandFeatureView {
+	return model.NewOnDemandFeatureViewFromProto(&core.OnDemandFeatureView{Spec: &core.OnDemandFeatureViewSpec{
+		Name: "transformed_conv_rate",
+		Features: []*core.FeatureSpecV2{
+			{Name: "conv_rate_plus_val_to_add", ValueType: prototypes.ValueType_DOUBLE},
+			{Name: "conv_rate_times_two", ValueType: prototypes.ValueType_DOUBLE},
+		},
+	}})
+}
+
+func TestTransformationCallback(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	features, requestData := newDriverFeatures(t, mem)
+	var calls []string
+	callback := newConvRateCallback(t, mem, &calls)
+
+	vectors, err := AugmentResponseWithOnDemandTransforms(context.Background(), []*model.OnDemandFeatureView{newCallbackFeatureView()},
+		requestData, map[string]*prototypes.RepeatedValue{}, features, callback, nil, mem, 3, false)
+	require.Nil(t, err)
+	defer releaseVectors(vectors)
+	assert.Equal(t, []string{"transformed_conv_rate"}, calls)
+	require.Len(t, vectors, 2)
+	assert.Equal(t, "conv_rate_plus_val_to_add", vectors[0].Name)
+	assert.Equal(t, []float64{1.25, 2.75}, vectors[0].Values.(*array.Float64).Float64Values()[:2])
+	assert.Equal(t, "conv_rate_times_two", vectors[1].Name)
+	assert.Len(t, vectors[1].Statuses, 3)
+
+	// Only the projected features are returned, with full feature names
+	projected, err := newCallbackFeatureView().ProjectWithFeatures([]string{"conv_rate_times_two"})
+	require.Nil(t, err)
+	projectedVectors, err := AugmentResponseWithOnDemandTransforms(context.Background(), []*model.OnDemandFeatureView{projected},
+		requestData, map[string]*prototypes.RepeatedValue{}, features, callback, nil, mem, 3, true)
+	require.Nil(t, err)
+	defer releaseVectors(projectedVectors)
+	require.Len(t, projectedVectors, 1)
+	assert.Equal(t, "transformed_conv_rate__conv_rate_times_two", projectedVectors[0].Name)
+	assert.Equal(t, 1.5, projectedVectors[0].Values.(*array.Float64).Value(1))
+}
+
+func TestTransformationCallbackFailure(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	features, requestData := newDriverFeatures(t, mem)
+
+	// The callback fails without consuming the input, which is still released
+	failingCallback := func(ODFVName string, inputArrPtr, inputSchemaPtr, outArrPtr, outSchemaPtr uintptr, fullFeatureNames bool) int {
+		return -1
+	}
+	_, err := AugmentResponseWithOnDemandTransforms(context.Background(), []*model.OnDemandFeatureView{newCallbackFeatureView()},
+		requestData, map[string]*prototypes.RepeatedValue{}, features, failingCallback, nil, mem, 3, false)
+	assert.ErrorContains(t, err, "transformation callback of transformed_conv_rate returned -1 rows instead of 3")
+}
+
+func TestSubstraitPlansAreExecutedBeforeTheTransformationCallback(t *testing.T) {
+	features, requestData := newDriverFeatures(t, memory.DefaultAllocator)
+	var calls []string
+	callback := newConvRateCallback(t, memory.DefaultAllocator, &calls)
+
+	vectors, err := AugmentResponseWithOnDemandTransforms(context.Background(),
+		[]*model.OnDemandFeatureView{newSubstraitFeatureView(driverFeaturesPlan(), "label"), newCallbackFeatureView()},
+		requestData, map[string]*prototypes.RepeatedValue{}, features, callback, nil, memory.DefaultAllocator, 3, false)
+	require.Nil(t, err)
+	defer releaseVectors(vectors)
+	assert.Equal(t, []string{"transformed_conv_rate"}, calls)
+	require.Len(t, vectors, 3)
+	assert.Equal(t, "label", vectors[0].Name)
+}
@@ -0,0 +1,138 @@
+package feast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/feast-dev/feast/go/internal/feast/auth"
+	"github.com/feast-dev/feast/go/internal/feast/model"
+	"github.com/feast-dev/feast/go/internal/feast/onlineserving"
+	"github.com/feast-dev/feast/go/protos/feast/core"
+	prototypes "github.com/feast-dev/feast/go/protos/feast/types"
+)
+
+// PushMode selects the stores that pushed data is written to, mirroring PushMode in the Python SDK.
+type PushMode string
+
+const (
+	PushModeOnline           PushMode = "online"
+	PushModeOffline          PushMode = "offline"
+	PushModeOnlineAndOffline PushMode = "online_and_offline"
+)
+
+// ParsePushMode parses the "to" field of push requests. An empty string defaults to the online store.
+func ParsePushMode(to string) (PushMode, error) {
+	switch PushMode(to) {
+	case "", PushModeOnline:
+		return PushModeOnline, nil
+	case PushModeOffline, PushModeOnlineAndOffline:
+		return PushMode(to), nil
+	default:
+		return "", fmt.Errorf("invalid push mode %s; must be one of online, offline or online_and_offline", to)
+	}
+}
+
+// WriteToOnlineStore writes columnar data to the online store table of a feature view or stream feature view.
+// Columns must include the view's join keys and may include its event and created timestamp columns.
+// Unknown views return FeastFeatureViewNotFound and data that doesn't match the view FeastInvalidFeatureData.
+// If allowRegistryCache is false, the registry is reloaded before looking up the view.
+func (fs *FeatureStore) WriteToOnlineStore(
+	ctx context.Context,
+	featureViewName string,
+	data map[string]*prototypes.RepeatedValue,
+	allowRegistryCache bool) error {
+	if !allowRegistryCache {
+		if err := fs.registry.Refresh(); err != nil {
+			return err
+		}
+	}
+	featureView, err := fs.registry.GetFeatureView(fs.config.Project, featureViewName)
+	if err != nil {
+		var streamErr error
+		if featureView, streamErr = fs.registry.GetStreamFeatureView(fs.config.Project, featureViewName); streamErr != nil {
+			return FeastFeatureViewNotFound{FeatureViewName: featureViewName}
+		}
+	}
+	if err := fs.authorizeWrite(ctx, []*model.FeatureView{featureView}); err != nil {
+		return err
+	}
+	return fs.writeFeatureView(ctx, featureView, data)
+}
+
+// Push writes columnar data to every feature view and stream feature view fed by the given push source.
+// Only the online store is supported; pushing to the offline store returns FeastPushModeNotSupported.
+func (fs *FeatureStore) Push(
+	ctx context.Context,
+	pushSourceName string,
+	data map[string]*prototypes.RepeatedValue,
+	to PushMode,
+	allowRegistryCache bool) error {
+	if to != PushModeOnline {
+		return FeastPushModeNotSupported{PushMode: to}
+	}
+	if !allowRegistryCache {
+		if err := fs.registry.Refresh(); err != nil {
+			return err
+		}
+	}
+	fvs, _, err := fs.listAllViews()
+	if err != nil {
+		return err
+	}
+	featureViews := make([]*model.FeatureView, 0)
+	for _, featureView := range fvs {
+		if featureView.PushSourceName == pushSourceName {
+			featureViews = append(featureViews, featureView)
+		}
+	}
+	if len(featureViews) == 0 {
+		return FeastPushSourceNotFound{PushSourceName: pushSourceName}
+	}
+	// All views are authorized before any is written to, so that a denied push doesn't write partially
+	if err := fs.authorizeWrite(ctx, featureViews); err != nil {
+		return err
+	}
+	for _, featureView := range featureViews {
+		if err := fs.writeFeatureView(ctx, featureView, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorizeWrite checks that the project's permissions allow the user of an authenticated request to write to
+// the online store tables of the feature views.
+func (fs *FeatureStore) authorizeWrite(ctx context.Context, featureViews []*model.FeatureView) error {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	permissions := fs.registry.ListPermissions(fs.config.Project)
+	if len(permissions) == 0 {
+		return nil
+	}
+	resources := make([]*auth.Resource, len(featureViews))
+	for index, featureView := range featureViews {
+		resources[index] = fs.featureViewResource(featureView)
+	}
+	if err := auth.Enforce(permissions, user, resources, core.PermissionSpec_WRITE_ONLINE); err != nil {
+		return FeastPermissionDenied{Message: err.Error()}
+	}
+	return nil
+}
+
+func (fs *FeatureStore) writeFeatureView(ctx context.Context, featureView *model.FeatureView, data map[string]*prototypes.RepeatedValue) error {
+	entities, err := fs.ListEntities(false)
+	if err != nil {
+		return err
+	}
+	rows, err := onlineserving.GetFeatureRowsToWrite(featureView, entities, data, time.Now())
+	if errors.Is(err, onlineserving.ErrInvalidFeatureData) {
+		return FeastInvalidFeatureData{FeatureViewName: featureView.Base.Name, Message: err.Error()}
+	} else if err != nil {
+		return err
+	}
+	return fs.onlineStore.OnlineWrite(ctx, featureView.Base.Name, rows)
+}
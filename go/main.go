@@ -1,40 +1,43 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	//"strings"
 	"syscall"
 
 	"github.com/feast-dev/feast/go/internal/feast"
+	"github.com/feast-dev/feast/go/internal/feast/auth"
+	"github.com/feast-dev/feast/go/internal/feast/metrics"
 	"github.com/feast-dev/feast/go/internal/feast/registry"
 	"github.com/feast-dev/feast/go/internal/feast/server"
 	"github.com/feast-dev/feast/go/internal/feast/server/logging"
+	"github.com/feast-dev/feast/go/internal/feast/tracing"
 	"github.com/feast-dev/feast/go/protos/feast/serving"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
-	//grpctrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/google.golang.org/grpc"
-	//"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 )
 
 type ServerStarter interface {
-	StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) error
-	StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) error
+	StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions, tlsOpts *server.TlsOptions) error
+	StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions, tlsOpts *server.TlsOptions) error
 }
 
 type RealServerStarter struct{}
 
-func (s *RealServerStarter) StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) error {
-	return StartHttpServer(fs, host, port, writeLoggedFeaturesCallback, loggingOpts)
+func (s *RealServerStarter) StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions, tlsOpts *server.TlsOptions) error {
+	return StartHttpServer(fs, host, port, writeLoggedFeaturesCallback, loggingOpts, tlsOpts)
 }
 
-func (s *RealServerStarter) StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) error {
-	return StartGrpcServer(fs, host, port, writeLoggedFeaturesCallback, loggingOpts)
+func (s *RealServerStarter) StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions, tlsOpts *server.TlsOptions) error {
+	return StartGrpcServer(fs, host, port, writeLoggedFeaturesCallback, loggingOpts, tlsOpts)
 }
 
 func main() {
@@ -42,7 +45,9 @@ func main() {
 	serverType := "http"
 	host := ""
 	port := 8080
-	server := RealServerStarter{}
+	metricsPort := 0
+	tlsOpts := &server.TlsOptions{}
+	starter := RealServerStarter{}
 	// Current Directory
 	repoPath, err := os.Getwd()
 	if err != nil {
@@ -54,6 +59,10 @@ func main() {
 
 	flag.StringVar(&host, "host", host, "Specify a host for the server")
 	flag.IntVar(&port, "port", port, "Specify a port for the server")
+	flag.IntVar(&metricsPort, "metrics-port", metricsPort, "Specify a port to serve Prometheus metrics on; the HTTP server also serves them on /metrics")
+	flag.StringVar(&tlsOpts.CertFile, "tls-cert", "", "Path to a PEM encoded TLS certificate; the server serves TLS if set")
+	flag.StringVar(&tlsOpts.KeyFile, "tls-key", "", "Path to the PEM encoded private key of the TLS certificate")
+	flag.StringVar(&tlsOpts.ClientCaFile, "tls-client-ca", "", "Path to PEM encoded CA certificates; clients must present a certificate signed by one of them if set")
 	flag.Parse()
 
 	repoConfig, err := registry.NewRepoConfigFromFile(repoPath)
@@ -71,12 +80,26 @@ func main() {
 		log.Fatal().Stack().Err(err).Msg("Failed to get LoggingOptions")
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), "feast-feature-server")
+	if err != nil {
+		log.Fatal().Stack().Err(err).Msg("Failed to initialize tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error().Err(err).Msg("Failed to flush traces")
+		}
+	}()
+
+	if metricsPort > 0 {
+		go startMetricsServer(host, metricsPort)
+	}
+
 	// TODO: writeLoggedFeaturesCallback is defaulted to nil. write_logged_features functionality needs to be
 	// implemented in Golang specific to OfflineStoreSink. Python Feature Server doesn't support this.
 	if serverType == "http" {
-		err = server.StartHttpServer(fs, host, port, nil, loggingOptions)
+		err = starter.StartHttpServer(fs, host, port, nil, loggingOptions, tlsOpts)
 	} else if serverType == "grpc" {
-		err = server.StartGrpcServer(fs, host, port, nil, loggingOptions)
+		err = starter.StartGrpcServer(fs, host, port, nil, loggingOptions, tlsOpts)
 	} else {
 		fmt.Println("Unknown server type. Please specify 'http' or 'grpc'.")
 	}
@@ -87,6 +110,15 @@ func main() {
 
 }
 
+// startMetricsServer serves the Prometheus metrics on a dedicated port, which is how the metrics of the gRPC server are exposed.
+func startMetricsServer(host string, port int) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	log.Info().Msgf("Serving metrics on host %s port %d", host, port)
+	err := http.ListenAndServe(fmt.Sprintf("%s:%d", host, port), mux)
+	log.Error().Err(err).Msg("Metrics server stopped")
+}
+
 func constructLoggingService(fs *feast.FeatureStore, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) (*logging.LoggingService, error) {
 	var loggingService *logging.LoggingService = nil
 	if writeLoggedFeaturesCallback != nil {
@@ -104,12 +136,14 @@ func constructLoggingService(fs *feast.FeatureStore, writeLoggedFeaturesCallback
 		if err != nil {
 			return nil, err
 		}
+		// Loggers are recreated with the new schema when their feature service changes
+		fs.OnFeatureServicesChange(loggingService.ResetLoggers)
 	}
 	return loggingService, nil
 }
 
 // StartGprcServerWithLogging starts gRPC server with enabled feature logging
-func StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) error {
+func StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions, tlsOpts *server.TlsOptions) error {
 	// #DD
 	//if strings.ToLower(os.Getenv("ENABLE_DATADOG_TRACING")) == "true" {
 	//	tracer.Start(tracer.WithRuntimeMetrics())
@@ -126,8 +160,20 @@ func StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedF
 		return err
 	}
 
-	grpcServer := grpc.NewServer()
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor, metrics.UnaryServerInterceptor, auth.UnaryServerInterceptor(fs.Authenticator())),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor),
+	}
+	if tlsOpts.Enabled() {
+		tlsOption, err := server.NewGrpcTlsServerOption(tlsOpts)
+		if err != nil {
+			return err
+		}
+		serverOpts = append(serverOpts, tlsOption)
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
 	serving.RegisterServingServiceServer(grpcServer, ser)
+	serving.RegisterGrpcFeatureServerServer(grpcServer, server.NewGrpcFeatureServer(fs, loggingService))
 	healthService := health.NewServer()
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthService)
 
@@ -151,7 +197,7 @@ func StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedF
 // StartHttpServerWithLogging starts HTTP server with enabled feature logging
 // Go does not allow direct assignment to package-level functions as a way to
 // mock them for tests
-func StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) error {
+func StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions, tlsOpts *server.TlsOptions) error {
 	loggingService, err := constructLoggingService(fs, writeLoggedFeaturesCallback, loggingOpts)
 	if err != nil {
 		return err
@@ -176,5 +222,5 @@ func StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedF
 		log.Info().Msg("HTTP server terminated")
 	}()
 
-	return ser.Serve(host, port)
+	return ser.ServeTLS(host, port, tlsOpts)
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/feast-dev/feast/go/internal/feast"
+	"github.com/feast-dev/feast/go/internal/feast/server"
 	"github.com/feast-dev/feast/go/internal/feast/server/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,13 +15,13 @@ type MockServerStarter struct {
 	mock.Mock
 }
 
-func (m *MockServerStarter) StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) error {
-	args := m.Called(fs, host, port, writeLoggedFeaturesCallback, loggingOpts)
+func (m *MockServerStarter) StartHttpServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions, tlsOpts *server.TlsOptions) error {
+	args := m.Called(fs, host, port, writeLoggedFeaturesCallback, loggingOpts, tlsOpts)
 	return args.Error(0)
 }
 
-func (m *MockServerStarter) StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions) error {
-	args := m.Called(fs, host, port, writeLoggedFeaturesCallback, loggingOpts)
+func (m *MockServerStarter) StartGrpcServer(fs *feast.FeatureStore, host string, port int, writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback, loggingOpts *logging.LoggingOptions, tlsOpts *server.TlsOptions) error {
+	args := m.Called(fs, host, port, writeLoggedFeaturesCallback, loggingOpts, tlsOpts)
 	return args.Error(0)
 }
 
@@ -33,10 +34,11 @@ func TestStartHttpServer(t *testing.T) {
 	var writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback
 
 	loggingOpts := &logging.LoggingOptions{}
+	tlsOpts := &server.TlsOptions{}
 
-	mockServerStarter.On("StartHttpServer", fs, host, port, mock.AnythingOfType("logging.OfflineStoreWriteCallback"), loggingOpts).Return(nil)
+	mockServerStarter.On("StartHttpServer", fs, host, port, mock.AnythingOfType("logging.OfflineStoreWriteCallback"), loggingOpts, tlsOpts).Return(nil)
 
-	err := mockServerStarter.StartHttpServer(fs, host, port, writeLoggedFeaturesCallback, loggingOpts)
+	err := mockServerStarter.StartHttpServer(fs, host, port, writeLoggedFeaturesCallback, loggingOpts, tlsOpts)
 	assert.NoError(t, err)
 	mockServerStarter.AssertExpectations(t)
 }
@@ -49,10 +51,11 @@ func TestStartGrpcServer(t *testing.T) {
 	port := 9090
 	var writeLoggedFeaturesCallback logging.OfflineStoreWriteCallback
 	loggingOpts := &logging.LoggingOptions{}
+	tlsOpts := &server.TlsOptions{CertFile: "server.crt", KeyFile: "server.key"}
 
-	mockServerStarter.On("StartGrpcServer", fs, host, port, mock.AnythingOfType("logging.OfflineStoreWriteCallback"), loggingOpts).Return(nil)
+	mockServerStarter.On("StartGrpcServer", fs, host, port, mock.AnythingOfType("logging.OfflineStoreWriteCallback"), loggingOpts, tlsOpts).Return(nil)
 
-	err := mockServerStarter.StartGrpcServer(fs, host, port, writeLoggedFeaturesCallback, loggingOpts)
+	err := mockServerStarter.StartGrpcServer(fs, host, port, writeLoggedFeaturesCallback, loggingOpts, tlsOpts)
 	assert.NoError(t, err)
 	mockServerStarter.AssertExpectations(t)
 }
@@ -1,7 +1,11 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/apache/arrow/go/v17/arrow"
 	"github.com/apache/arrow/go/v17/arrow/array"
@@ -344,3 +348,272 @@ func ProtoValuesToArrowArray(protoValues []*types.Value, arrowAllocator memory.A
 		return array.NewNull(numRows), nil
 	}
 }
+
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05"}
+
+var listElementTypes = map[types.ValueType_Enum]types.ValueType_Enum{
+	types.ValueType_BYTES_LIST:          types.ValueType_BYTES,
+	types.ValueType_STRING_LIST:         types.ValueType_STRING,
+	types.ValueType_INT32_LIST:          types.ValueType_INT32,
+	types.ValueType_INT64_LIST:          types.ValueType_INT64,
+	types.ValueType_DOUBLE_LIST:         types.ValueType_DOUBLE,
+	types.ValueType_FLOAT_LIST:          types.ValueType_FLOAT,
+	types.ValueType_BOOL_LIST:           types.ValueType_BOOL,
+	types.ValueType_UNIX_TIMESTAMP_LIST: types.ValueType_UNIX_TIMESTAMP,
+}
+
+// ConvertProtoValue converts a value into the given value type. It is used for input whose types are not known
+// upfront, like numbers decoded from JSON (always int64 or double) or values pushed as strings through gRPC.
+// Null values are returned unchanged.
+func ConvertProtoValue(value *types.Value, valueType types.ValueType_Enum) (*types.Value, error) {
+	if value == nil || value.Val == nil {
+		return &types.Value{}, nil
+	}
+	if _, ok := value.Val.(*types.Value_NullVal); ok {
+		return value, nil
+	}
+	if elementType, ok := listElementTypes[valueType]; ok {
+		return convertProtoListValue(value, valueType, elementType)
+	}
+
+	switch valueType {
+	case types.ValueType_INT32:
+		v, err := protoValueToInt64(value)
+		if err != nil {
+			return nil, err
+		}
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, fmt.Errorf("value %d overflows int32", v)
+		}
+		return &types.Value{Val: &types.Value_Int32Val{Int32Val: int32(v)}}, nil
+	case types.ValueType_INT64:
+		v, err := protoValueToInt64(value)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Value{Val: &types.Value_Int64Val{Int64Val: v}}, nil
+	case types.ValueType_FLOAT:
+		v, err := protoValueToFloat64(value)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Value{Val: &types.Value_FloatVal{FloatVal: float32(v)}}, nil
+	case types.ValueType_DOUBLE:
+		v, err := protoValueToFloat64(value)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Value{Val: &types.Value_DoubleVal{DoubleVal: v}}, nil
+	case types.ValueType_STRING:
+		switch x := value.Val.(type) {
+		case *types.Value_StringVal:
+			return value, nil
+		case *types.Value_BytesVal:
+			return &types.Value{Val: &types.Value_StringVal{StringVal: string(x.BytesVal)}}, nil
+		}
+	case types.ValueType_BYTES:
+		switch x := value.Val.(type) {
+		case *types.Value_BytesVal:
+			return value, nil
+		case *types.Value_StringVal:
+			return &types.Value{Val: &types.Value_BytesVal{BytesVal: []byte(x.StringVal)}}, nil
+		}
+	case types.ValueType_BOOL:
+		switch x := value.Val.(type) {
+		case *types.Value_BoolVal:
+			return value, nil
+		case *types.Value_StringVal:
+			v, err := strconv.ParseBool(x.StringVal)
+			if err != nil {
+				return nil, err
+			}
+			return &types.Value{Val: &types.Value_BoolVal{BoolVal: v}}, nil
+		}
+	case types.ValueType_UNIX_TIMESTAMP:
+		v, err := protoValueToUnixTimestamp(value)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Value{Val: &types.Value_UnixTimestampVal{UnixTimestampVal: v}}, nil
+	}
+	return nil, fmt.Errorf("cannot convert %v to %s", value.Val, valueType)
+}
+
+func convertProtoListValue(value *types.Value, valueType types.ValueType_Enum, elementType types.ValueType_Enum) (*types.Value, error) {
+	elements, err := protoListElements(value)
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]*types.Value, len(elements))
+	for idx, element := range elements {
+		if converted[idx], err = ConvertProtoValue(element, elementType); err != nil {
+			return nil, err
+		}
+	}
+
+	switch valueType {
+	case types.ValueType_BYTES_LIST:
+		vals := make([][]byte, len(converted))
+		for idx, v := range converted {
+			vals[idx] = v.GetBytesVal()
+		}
+		return &types.Value{Val: &types.Value_BytesListVal{BytesListVal: &types.BytesList{Val: vals}}}, nil
+	case types.ValueType_STRING_LIST:
+		vals := make([]string, len(converted))
+		for idx, v := range converted {
+			vals[idx] = v.GetStringVal()
+		}
+		return &types.Value{Val: &types.Value_StringListVal{StringListVal: &types.StringList{Val: vals}}}, nil
+	case types.ValueType_INT32_LIST:
+		vals := make([]int32, len(converted))
+		for idx, v := range converted {
+			vals[idx] = v.GetInt32Val()
+		}
+		return &types.Value{Val: &types.Value_Int32ListVal{Int32ListVal: &types.Int32List{Val: vals}}}, nil
+	case types.ValueType_INT64_LIST:
+		vals := make([]int64, len(converted))
+		for idx, v := range converted {
+			vals[idx] = v.GetInt64Val()
+		}
+		return &types.Value{Val: &types.Value_Int64ListVal{Int64ListVal: &types.Int64List{Val: vals}}}, nil
+	case types.ValueType_DOUBLE_LIST:
+		vals := make([]float64, len(converted))
+		for idx, v := range converted {
+			vals[idx] = v.GetDoubleVal()
+		}
+		return &types.Value{Val: &types.Value_DoubleListVal{DoubleListVal: &types.DoubleList{Val: vals}}}, nil
+	case types.ValueType_FLOAT_LIST:
+		vals := make([]float32, len(converted))
+		for idx, v := range converted {
+			vals[idx] = v.GetFloatVal()
+		}
+		return &types.Value{Val: &types.Value_FloatListVal{FloatListVal: &types.FloatList{Val: vals}}}, nil
+	case types.ValueType_BOOL_LIST:
+		vals := make([]bool, len(converted))
+		for idx, v := range converted {
+			vals[idx] = v.GetBoolVal()
+		}
+		return &types.Value{Val: &types.Value_BoolListVal{BoolListVal: &types.BoolList{Val: vals}}}, nil
+	default:
+		vals := make([]int64, len(converted))
+		for idx, v := range converted {
+			vals[idx] = v.GetUnixTimestampVal()
+		}
+		return &types.Value{Val: &types.Value_UnixTimestampListVal{UnixTimestampListVal: &types.Int64List{Val: vals}}}, nil
+	}
+}
+
+// protoListElements returns the elements of a list value as scalar values. A string value is parsed as a JSON array.
+func protoListElements(value *types.Value) ([]*types.Value, error) {
+	elements := make([]*types.Value, 0)
+	switch x := value.Val.(type) {
+	case *types.Value_BytesListVal:
+		for _, v := range x.BytesListVal.GetVal() {
+			elements = append(elements, &types.Value{Val: &types.Value_BytesVal{BytesVal: v}})
+		}
+	case *types.Value_StringListVal:
+		for _, v := range x.StringListVal.GetVal() {
+			elements = append(elements, &types.Value{Val: &types.Value_StringVal{StringVal: v}})
+		}
+	case *types.Value_Int32ListVal:
+		for _, v := range x.Int32ListVal.GetVal() {
+			elements = append(elements, &types.Value{Val: &types.Value_Int32Val{Int32Val: v}})
+		}
+	case *types.Value_Int64ListVal:
+		for _, v := range x.Int64ListVal.GetVal() {
+			elements = append(elements, &types.Value{Val: &types.Value_Int64Val{Int64Val: v}})
+		}
+	case *types.Value_DoubleListVal:
+		for _, v := range x.DoubleListVal.GetVal() {
+			elements = append(elements, &types.Value{Val: &types.Value_DoubleVal{DoubleVal: v}})
+		}
+	case *types.Value_FloatListVal:
+		for _, v := range x.FloatListVal.GetVal() {
+			elements = append(elements, &types.Value{Val: &types.Value_FloatVal{FloatVal: v}})
+		}
+	case *types.Value_BoolListVal:
+		for _, v := range x.BoolListVal.GetVal() {
+			elements = append(elements, &types.Value{Val: &types.Value_BoolVal{BoolVal: v}})
+		}
+	case *types.Value_UnixTimestampListVal:
+		for _, v := range x.UnixTimestampListVal.GetVal() {
+			elements = append(elements, &types.Value{Val: &types.Value_UnixTimestampVal{UnixTimestampVal: v}})
+		}
+	case *types.Value_StringVal:
+		var items []interface{}
+		if err := json.Unmarshal([]byte(x.StringVal), &items); err != nil {
+			return nil, fmt.Errorf("cannot parse %q as a list: %v", x.StringVal, err)
+		}
+		for _, item := range items {
+			switch v := item.(type) {
+			case string:
+				elements = append(elements, &types.Value{Val: &types.Value_StringVal{StringVal: v}})
+			case float64:
+				elements = append(elements, &types.Value{Val: &types.Value_DoubleVal{DoubleVal: v}})
+			case bool:
+				elements = append(elements, &types.Value{Val: &types.Value_BoolVal{BoolVal: v}})
+			default:
+				return nil, fmt.Errorf("unsupported list element %v in %q", item, x.StringVal)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("cannot convert %v to a list", value.Val)
+	}
+	return elements, nil
+}
+
+func protoValueToInt64(value *types.Value) (int64, error) {
+	switch x := value.Val.(type) {
+	case *types.Value_Int32Val:
+		return int64(x.Int32Val), nil
+	case *types.Value_Int64Val:
+		return x.Int64Val, nil
+	case *types.Value_UnixTimestampVal:
+		return x.UnixTimestampVal, nil
+	case *types.Value_FloatVal:
+		if float32(math.Trunc(float64(x.FloatVal))) == x.FloatVal {
+			return int64(x.FloatVal), nil
+		}
+	case *types.Value_DoubleVal:
+		if math.Trunc(x.DoubleVal) == x.DoubleVal {
+			return int64(x.DoubleVal), nil
+		}
+	case *types.Value_StringVal:
+		return strconv.ParseInt(x.StringVal, 10, 64)
+	}
+	return 0, fmt.Errorf("cannot convert %v to an integer", value.Val)
+}
+
+func protoValueToFloat64(value *types.Value) (float64, error) {
+	switch x := value.Val.(type) {
+	case *types.Value_Int32Val:
+		return float64(x.Int32Val), nil
+	case *types.Value_Int64Val:
+		return float64(x.Int64Val), nil
+	case *types.Value_FloatVal:
+		return float64(x.FloatVal), nil
+	case *types.Value_DoubleVal:
+		return x.DoubleVal, nil
+	case *types.Value_StringVal:
+		return strconv.ParseFloat(x.StringVal, 64)
+	}
+	return 0, fmt.Errorf("cannot convert %v to a floating point number", value.Val)
+}
+
+// protoValueToUnixTimestamp returns the value in seconds since epoch. Strings may either hold a number of seconds
+// or a date time, which is assumed to be UTC if it has no time zone.
+func protoValueToUnixTimestamp(value *types.Value) (int64, error) {
+	stringVal, ok := value.Val.(*types.Value_StringVal)
+	if !ok {
+		return protoValueToInt64(value)
+	}
+	if seconds, err := strconv.ParseInt(stringVal.StringVal, 10, 64); err == nil {
+		return seconds, nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, stringVal.StringVal); err == nil {
+			return t.Unix(), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot parse %q as a timestamp", stringVal.StringVal)
+}
@@ -104,3 +104,38 @@ func protoValuesEquals(t *testing.T, a, b []*types.Value) {
 			"Arrays are not equal. Diff[%d] %v != %v", idx, left, b[idx])
 	}
 }
+
+func TestConvertProtoValue(t *testing.T) {
+	testCases := []struct {
+		value     *types.Value
+		valueType types.ValueType_Enum
+		expected  *types.Value
+	}{
+		{&types.Value{Val: &types.Value_Int64Val{5}}, types.ValueType_INT32, &types.Value{Val: &types.Value_Int32Val{5}}},
+		{&types.Value{Val: &types.Value_DoubleVal{5}}, types.ValueType_INT64, &types.Value{Val: &types.Value_Int64Val{5}}},
+		{&types.Value{Val: &types.Value_StringVal{"7"}}, types.ValueType_INT64, &types.Value{Val: &types.Value_Int64Val{7}}},
+		{&types.Value{Val: &types.Value_Int64Val{2}}, types.ValueType_FLOAT, &types.Value{Val: &types.Value_FloatVal{2}}},
+		{&types.Value{Val: &types.Value_StringVal{"0.5"}}, types.ValueType_DOUBLE, &types.Value{Val: &types.Value_DoubleVal{0.5}}},
+		{&types.Value{Val: &types.Value_StringVal{"true"}}, types.ValueType_BOOL, &types.Value{Val: &types.Value_BoolVal{true}}},
+		{&types.Value{Val: &types.Value_StringVal{"abc"}}, types.ValueType_BYTES, &types.Value{Val: &types.Value_BytesVal{[]byte("abc")}}},
+		{&types.Value{Val: &types.Value_StringVal{"2024-01-02T03:04:05Z"}}, types.ValueType_UNIX_TIMESTAMP, &types.Value{Val: &types.Value_UnixTimestampVal{1704164645}}},
+		{&types.Value{Val: &types.Value_StringVal{"2024-01-02 03:04:05"}}, types.ValueType_UNIX_TIMESTAMP, &types.Value{Val: &types.Value_UnixTimestampVal{1704164645}}},
+		{&types.Value{Val: &types.Value_Int64Val{1704164645}}, types.ValueType_UNIX_TIMESTAMP, &types.Value{Val: &types.Value_UnixTimestampVal{1704164645}}},
+		{&types.Value{Val: &types.Value_Int64ListVal{&types.Int64List{Val: []int64{1, 2}}}}, types.ValueType_FLOAT_LIST, &types.Value{Val: &types.Value_FloatListVal{&types.FloatList{Val: []float32{1, 2}}}}},
+		{&types.Value{Val: &types.Value_StringVal{"[1, 2]"}}, types.ValueType_INT32_LIST, &types.Value{Val: &types.Value_Int32ListVal{&types.Int32List{Val: []int32{1, 2}}}}},
+		{&types.Value{Val: &types.Value_StringVal{`["a", "b"]`}}, types.ValueType_STRING_LIST, &types.Value{Val: &types.Value_StringListVal{&types.StringList{Val: []string{"a", "b"}}}}},
+		{&types.Value{Val: &types.Value_NullVal{types.Null_NULL}}, types.ValueType_INT64, &types.Value{Val: &types.Value_NullVal{types.Null_NULL}}},
+	}
+	for _, tc := range testCases {
+		converted, err := ConvertProtoValue(tc.value, tc.valueType)
+		assert.Nil(t, err)
+		assert.True(t, proto.Equal(tc.expected, converted), "expected %v, got %v", tc.expected, converted)
+	}
+
+	_, err := ConvertProtoValue(&types.Value{Val: &types.Value_DoubleVal{0.5}}, types.ValueType_INT64)
+	assert.NotNil(t, err)
+	_, err = ConvertProtoValue(&types.Value{Val: &types.Value_Int64Val{math.MaxInt64}}, types.ValueType_INT32)
+	assert.NotNil(t, err)
+	_, err = ConvertProtoValue(&types.Value{Val: &types.Value_StringVal{"not a date"}}, types.ValueType_UNIX_TIMESTAMP)
+	assert.NotNil(t, err)
+}